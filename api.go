@@ -1,7 +1,9 @@
 package hyperliquid
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 )
 
 // API implementation general error
@@ -15,12 +17,15 @@ func (e APIError) Error() string {
 
 // IAPIService is an interface for making requests to the API Service.
 //
-// It has a Request method that takes a path and a payload and returns a byte array and an error.
-// It has a debug method that takes a format string and args and returns nothing.
+// It has a Request method that takes a path and a payload and returns a byte array and an error,
+// and a RequestContext method that also takes the context of the request's span.
+// It has a Logger method that returns the structured logger of the service.
 // It has an Endpoint method that returns a string.
 type IAPIService interface {
-	debug(format string, args ...interface{})
+	Logger() *slog.Logger
 	Request(path string, payload any) ([]byte, error)
+	RequestContext(ctx context.Context, path string, payload any) ([]byte, error)
+	Tracing() *Tracing
 	Endpoint() string
 	KeyManager() *PKeyManager
 }
@@ -29,6 +34,11 @@ type IAPIService interface {
 // IAPIService and a request and returns a pointer to the result and an error.
 // It makes a request to the API Service and unmarshals the result into the result type T
 func MakeUniversalRequest[T any](api IAPIService, request any) (*T, error) {
+	return makeUniversalRequestContext[T](context.Background(), api, request)
+}
+
+// makeUniversalRequestContext is MakeUniversalRequest with spans that are children of the span in ctx
+func makeUniversalRequestContext[T any](ctx context.Context, api IAPIService, request any) (*T, error) {
 	if api.Endpoint() == "" {
 		return nil, APIError{Message: "Endpoint not set"}
 	}
@@ -39,13 +49,20 @@ func MakeUniversalRequest[T any](api IAPIService, request any) (*T, error) {
 		return nil, APIError{Message: "API key not set"}
 	}
 
-	response, err := api.Request(api.Endpoint(), request)
+	response, err := api.RequestContext(ctx, api.Endpoint(), request)
 	if err != nil {
 		return nil, err
 	}
+	_, span := api.Tracing().start(ctx, "hyperliquid.parseResponse")
+	result, err := parseUniversalResponse[T](api, response)
+	endSpan(span, err)
+	return result, err
+}
 
+// parseUniversalResponse unmarshals a response into T, or into an APIError for error responses
+func parseUniversalResponse[T any](api IAPIService, response []byte) (*T, error) {
 	var result T
-	err = FastUnmarshal(response, &result)
+	err := FastUnmarshal(response, &result)
 	if err == nil {
 		return &result, nil
 	}
@@ -53,7 +70,7 @@ func MakeUniversalRequest[T any](api IAPIService, request any) (*T, error) {
 	var errResult map[string]interface{}
 	err = FastUnmarshal(response, &errResult)
 	if err != nil {
-		api.debug("Error second FastUnmarshal: %s", err)
+		api.Logger().Debug("Unexpected response", "endpoint", api.Endpoint(), "response", response, "err", err)
 		return nil, APIError{Message: "Unexpected response"}
 	}
 
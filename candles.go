@@ -0,0 +1,330 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ParseCandleInterval returns the duration of a candle interval such as "1m", "4h", "1d" or "1w".
+// Custom multiples like "2h" or "90m" are accepted as well. Monthly intervals ("1M") have no
+// fixed duration and are rejected.
+func ParseCandleInterval(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("invalid candle interval: %q", interval)
+	}
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid candle interval: %q", interval)
+	}
+	var unit time.Duration
+	switch interval[len(interval)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("unsupported candle interval: %q", interval)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// GetCandleHistory returns the candles of coin between startTime and endTime (in milliseconds).
+// Unlike GetCandleSnapshot the range can be arbitrarily long: it is split into requests of
+// at most CANDLE_SNAPSHOT_LIMIT candles. Candles older than the exchange retention are not available.
+func (api *InfoAPI) GetCandleHistory(coin string, interval string, startTime int64, endTime int64) (*[]CandleSnapshot, error) {
+	if interval == "1M" {
+		// 5000 months always fit in a single request
+		return api.GetCandleSnapshot(coin, interval, startTime, endTime)
+	}
+	duration, err := ParseCandleInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	window := duration.Milliseconds() * CANDLE_SNAPSHOT_LIMIT
+
+	series := NewCandleSeries(coin, interval)
+	for from := startTime; from <= endTime; {
+		to := from + window - 1
+		if to > endTime {
+			to = endTime
+		}
+		page, err := api.GetCandleSnapshot(coin, interval, from, to)
+		if err != nil {
+			return nil, err
+		}
+		series.Merge(*page...)
+		from = to + 1
+	}
+	candles := series.Candles()
+	return &candles, nil
+}
+
+// CandleSeries is an ordered set of candles for one coin and interval.
+// It is filled from history with Backfill and kept up to date with Follow.
+// Candles are keyed by open time, so overlapping history and live updates never produce duplicates.
+type CandleSeries struct {
+	Coin     string
+	Interval string
+
+	mu       sync.RWMutex
+	candles  []CandleSnapshot // Sorted by open time
+	onUpdate func(candle CandleSnapshot)
+}
+
+// NewCandleSeries returns an empty candle series
+func NewCandleSeries(coin string, interval string) *CandleSeries {
+	return &CandleSeries{
+		Coin:     coin,
+		Interval: interval,
+	}
+}
+
+// Backfill loads the history between startTime and endTime (in milliseconds) into the series
+func (s *CandleSeries) Backfill(api *InfoAPI, startTime int64, endTime int64) error {
+	candles, err := api.GetCandleHistory(s.Coin, s.Interval, startTime, endTime)
+	if err != nil {
+		return err
+	}
+	s.Merge(*candles...)
+	return nil
+}
+
+// Follow subscribes to live candles and merges every update into the series.
+// The optional handler is called with each merged update.
+func (s *CandleSeries) Follow(ws *WebSocketAPI, handler func(candle CandleSnapshot)) (*SubscriptionHandle, error) {
+	s.mu.Lock()
+	s.onUpdate = handler
+	s.mu.Unlock()
+
+	return ws.SubscribeCandle(s.Coin, s.Interval, func(data interface{}) {
+		candle, err := convertWSData[CandleSnapshot](data)
+		if err != nil {
+			return
+		}
+		s.Merge(*candle)
+
+		s.mu.RLock()
+		onUpdate := s.onUpdate
+		s.mu.RUnlock()
+		if onUpdate != nil {
+			onUpdate(*candle)
+		}
+	})
+}
+
+// Merge inserts candles into the series. A candle with the same open time as an existing one
+// replaces it, unless the existing candle already aggregates more trades (a stale snapshot).
+func (s *CandleSeries) Merge(candles ...CandleSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, candle := range candles {
+		i := sort.Search(len(s.candles), func(i int) bool {
+			return s.candles[i].OpenTime >= candle.OpenTime
+		})
+		if i < len(s.candles) && s.candles[i].OpenTime == candle.OpenTime {
+			if candle.N >= s.candles[i].N {
+				s.candles[i] = candle
+			}
+			continue
+		}
+		s.candles = append(s.candles, CandleSnapshot{})
+		copy(s.candles[i+1:], s.candles[i:])
+		s.candles[i] = candle
+	}
+}
+
+// Candles returns a copy of all candles in the series ordered by open time
+func (s *CandleSeries) Candles() []CandleSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]CandleSnapshot(nil), s.candles...)
+}
+
+// Range returns the candles opened between startTime and endTime (in milliseconds, inclusive)
+func (s *CandleSeries) Range(startTime int64, endTime int64) []CandleSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	from := sort.Search(len(s.candles), func(i int) bool {
+		return s.candles[i].OpenTime >= startTime
+	})
+	to := sort.Search(len(s.candles), func(i int) bool {
+		return s.candles[i].OpenTime > endTime
+	})
+	return append([]CandleSnapshot(nil), s.candles[from:to]...)
+}
+
+// Aggregate returns the series aggregated into a larger interval, see AggregateCandles
+func (s *CandleSeries) Aggregate(interval string) ([]CandleSnapshot, error) {
+	return AggregateCandles(s.Candles(), interval)
+}
+
+// AggregateCandles combines candles ordered by open time into candles of a larger interval
+// (e.g. 2h from 1h). The target interval must be a multiple of the candles' interval.
+// Buckets are aligned to the Unix epoch (UTC) and the last bucket may be incomplete.
+func AggregateCandles(candles []CandleSnapshot, interval string) ([]CandleSnapshot, error) {
+	if len(candles) == 0 {
+		return []CandleSnapshot{}, nil
+	}
+	target, err := ParseCandleInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	base, err := ParseCandleInterval(candles[0].Interval)
+	if err != nil {
+		return nil, err
+	}
+	if target < base || target%base != 0 {
+		return nil, fmt.Errorf("interval %s is not a multiple of %s", interval, candles[0].Interval)
+	}
+
+	bucketMs := target.Milliseconds()
+	var result []CandleSnapshot
+	for _, candle := range candles {
+		start := candle.OpenTime - candle.OpenTime%bucketMs
+		if len(result) == 0 || result[len(result)-1].OpenTime != start {
+			result = append(result, CandleSnapshot{
+				OpenTime:  start,
+				CloseTime: start + bucketMs - 1,
+				Symbol:    candle.Symbol,
+				Interval:  interval,
+				Open:      candle.Open,
+				Close:     candle.Close,
+				High:      candle.High,
+				Low:       candle.Low,
+				Volume:    candle.Volume,
+				N:         candle.N,
+			})
+			continue
+		}
+		current := &result[len(result)-1]
+		current.Close = candle.Close
+		if candle.High > current.High {
+			current.High = candle.High
+		}
+		if candle.Low < current.Low {
+			current.Low = candle.Low
+		}
+		current.Volume += candle.Volume
+		current.N += candle.N
+	}
+	return result, nil
+}
+
+// TradeCandleBuilder builds candles from individual trades.
+// It can be used for coins or intervals the candle API does not offer.
+// Intervals without any trade produce no candle.
+type TradeCandleBuilder struct {
+	Coin     string
+	Interval string
+
+	mu       sync.Mutex
+	bucketMs int64
+	current  *CandleSnapshot
+	seen     map[int64]struct{} // Trade ids already added to the current candle
+}
+
+// NewTradeCandleBuilder returns a builder for candles of the given coin and interval
+func NewTradeCandleBuilder(coin string, interval string) (*TradeCandleBuilder, error) {
+	duration, err := ParseCandleInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	return &TradeCandleBuilder{
+		Coin:     coin,
+		Interval: interval,
+		bucketMs: duration.Milliseconds(),
+		seen:     make(map[int64]struct{}),
+	}, nil
+}
+
+// AddTrade adds a trade to the current candle. When the trade opens a new interval
+// the previous candle is finished and returned as closed.
+// Trades for other coins, duplicates and trades older than the current candle are ignored.
+func (b *TradeCandleBuilder) AddTrade(trade Trade) (closed *CandleSnapshot, updated bool) {
+	if trade.Coin != b.Coin {
+		return nil, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start := trade.Time - trade.Time%b.bucketMs
+	if b.current != nil {
+		if start < b.current.OpenTime {
+			return nil, false
+		}
+		if start == b.current.OpenTime {
+			if _, exists := b.seen[trade.Tid]; exists {
+				return nil, false
+			}
+			b.seen[trade.Tid] = struct{}{}
+			b.current.Close = trade.Px
+			if trade.Px > b.current.High {
+				b.current.High = trade.Px
+			}
+			if trade.Px < b.current.Low {
+				b.current.Low = trade.Px
+			}
+			b.current.Volume += trade.Sz
+			b.current.N++
+			return nil, true
+		}
+		finished := *b.current
+		closed = &finished
+	}
+
+	b.current = &CandleSnapshot{
+		OpenTime:  start,
+		CloseTime: start + b.bucketMs - 1,
+		Symbol:    b.Coin,
+		Interval:  b.Interval,
+		Open:      trade.Px,
+		Close:     trade.Px,
+		High:      trade.Px,
+		Low:       trade.Px,
+		Volume:    trade.Sz,
+		N:         1,
+	}
+	b.seen = map[int64]struct{}{trade.Tid: {}}
+	return closed, true
+}
+
+// Current returns the candle currently being built, or nil if no trade was added yet
+func (b *TradeCandleBuilder) Current() *CandleSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.current == nil {
+		return nil
+	}
+	current := *b.current
+	return &current
+}
+
+// Follow subscribes to the trades of the builder's coin. The handler is called with every
+// finished candle (closed == true) and with the in-progress candle after each update.
+func (b *TradeCandleBuilder) Follow(ws *WebSocketAPI, handler func(candle CandleSnapshot, closed bool)) (*SubscriptionHandle, error) {
+	return ws.SubscribeTrades(b.Coin, func(data interface{}) {
+		trades, err := convertWSData[[]Trade](data)
+		if err != nil {
+			return
+		}
+		for _, trade := range *trades {
+			closed, updated := b.AddTrade(trade)
+			if closed != nil {
+				handler(*closed, true)
+			}
+			if updated {
+				if current := b.Current(); current != nil {
+					handler(*current, false)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package hyperliquid
+
+import (
+	"testing"
+	"time"
+)
+
+func testCandle(openTime int64, interval string, open, high, low, close, volume float64, n int) CandleSnapshot {
+	duration, _ := ParseCandleInterval(interval)
+	return CandleSnapshot{
+		OpenTime:  openTime,
+		CloseTime: openTime + duration.Milliseconds() - 1,
+		Symbol:    "BTC",
+		Interval:  interval,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+		N:         n,
+	}
+}
+
+// TestAggregateCandles tests building 2h candles from 1h candles
+func TestAggregateCandles(t *testing.T) {
+	hour := time.Hour.Milliseconds()
+	candles := []CandleSnapshot{
+		testCandle(0, "1h", 100, 110, 95, 105, 1, 10),
+		testCandle(hour, "1h", 105, 120, 100, 115, 2, 20),
+		testCandle(2*hour, "1h", 115, 116, 90, 92, 3, 30),
+	}
+
+	result, err := AggregateCandles(candles, "2h")
+	if err != nil {
+		t.Fatalf("AggregateCandles failed: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(result))
+	}
+	first := result[0]
+	if first.Open != 100 || first.Close != 115 || first.High != 120 || first.Low != 95 || first.Volume != 3 || first.N != 30 {
+		t.Errorf("Unexpected aggregated candle: %+v", first)
+	}
+	if first.Interval != "2h" || first.CloseTime != 2*hour-1 {
+		t.Errorf("Unexpected aggregated candle bounds: %+v", first)
+	}
+	if result[1].OpenTime != 2*hour || result[1].Close != 92 {
+		t.Errorf("Unexpected partial candle: %+v", result[1])
+	}
+
+	if _, err := AggregateCandles(candles, "90m"); err == nil {
+		t.Error("Expected error for interval that is not a multiple of 1h")
+	}
+}
+
+// TestCandleSeriesMerge tests that overlapping history and live updates do not duplicate candles
+func TestCandleSeriesMerge(t *testing.T) {
+	minute := time.Minute.Milliseconds()
+	series := NewCandleSeries("BTC", "1m")
+	series.Merge(
+		testCandle(2*minute, "1m", 1, 1, 1, 1, 1, 5),
+		testCandle(0, "1m", 1, 1, 1, 1, 1, 1),
+	)
+	// Live update of the last candle
+	series.Merge(testCandle(2*minute, "1m", 1, 2, 1, 2, 3, 8))
+	// Stale snapshot of the same candle must not overwrite the live update
+	series.Merge(testCandle(2*minute, "1m", 1, 1, 1, 1, 1, 6), testCandle(minute, "1m", 1, 1, 1, 1, 1, 1))
+
+	candles := series.Candles()
+	if len(candles) != 3 {
+		t.Fatalf("Expected 3 candles, got %d", len(candles))
+	}
+	for i, candle := range candles {
+		if candle.OpenTime != int64(i)*minute {
+			t.Errorf("Candle %d out of order: %+v", i, candle)
+		}
+	}
+	if candles[2].N != 8 || candles[2].Close != 2 {
+		t.Errorf("Expected live update to be kept, got %+v", candles[2])
+	}
+	if got := series.Range(minute, 2*minute); len(got) != 2 {
+		t.Errorf("Expected 2 candles in range, got %d", len(got))
+	}
+}
+
+// TestTradeCandleBuilder tests building candles from trades
+func TestTradeCandleBuilder(t *testing.T) {
+	builder, err := NewTradeCandleBuilder("BTC", "1m")
+	if err != nil {
+		t.Fatalf("NewTradeCandleBuilder failed: %v", err)
+	}
+	minute := time.Minute.Milliseconds()
+
+	trades := []Trade{
+		{Coin: "BTC", Px: 100, Sz: 1, Time: 1000, Tid: 1},
+		{Coin: "BTC", Px: 104, Sz: 2, Time: 2000, Tid: 2},
+		{Coin: "BTC", Px: 104, Sz: 2, Time: 2000, Tid: 2}, // duplicate
+		{Coin: "ETH", Px: 1, Sz: 1, Time: 3000, Tid: 3},   // other coin
+		{Coin: "BTC", Px: 98, Sz: 1, Time: 4000, Tid: 4},
+	}
+	for _, trade := range trades {
+		if closed, _ := builder.AddTrade(trade); closed != nil {
+			t.Fatalf("Unexpected closed candle: %+v", closed)
+		}
+	}
+
+	closed, updated := builder.AddTrade(Trade{Coin: "BTC", Px: 99, Sz: 5, Time: minute + 1, Tid: 5})
+	if !updated || closed == nil {
+		t.Fatal("Expected previous candle to be closed")
+	}
+	if closed.Open != 100 || closed.High != 104 || closed.Low != 98 || closed.Close != 98 || closed.Volume != 4 || closed.N != 3 {
+		t.Errorf("Unexpected closed candle: %+v", closed)
+	}
+	if current := builder.Current(); current == nil || current.OpenTime != minute || current.Open != 99 {
+		t.Errorf("Unexpected current candle: %+v", current)
+	}
+
+	if _, updated := builder.AddTrade(Trade{Coin: "BTC", Px: 1, Sz: 1, Time: 500, Tid: 6}); updated {
+		t.Error("Expected trade older than the current candle to be ignored")
+	}
+}
@@ -2,13 +2,17 @@ package hyperliquid
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // IClient is the interface that wraps the basic Requst method.
@@ -30,17 +34,19 @@ type IClient interface {
 //
 // It contains the base URL of the HyperLiquid API, the HTTP client, the debug mode,
 // the network type, the private key, and the logger.
-// The debug method prints the debug messages.
 type Client struct {
 	baseUrl        string        // Base URL of the HyperLiquid API
 	privateKey     string        // Private key for the client
 	defaultAddress string        // Default address for the client
 	isMainnet      bool          // Network type
-	Debug          bool          // Debug mode
+	Debug          bool          // Debug mode, logs debug messages to stdout if no logger is set
 	httpClient     *http.Client  // HTTP client
 	keyManager     *PKeyManager  // Private key manager
-	Logger         *log.Logger   // Logger for debug messages
+	logger         *slog.Logger  // Structured logger, see Logger
 	webSocketAPI   *WebSocketAPI // WebSocket API for automatic fallback
+	telemetry      *Telemetry    // Round trip measurements, DefaultTelemetry if nil
+	metrics        MetricsHook   // Request and signing metrics, NopMetrics if nil
+	tracing        *Tracing      // Request spans, DefaultTracing if nil
 }
 
 // Returns the private key manager connected to the API.
@@ -59,13 +65,6 @@ func getURL(isMainnet bool) string {
 
 // NewClient returns a new instance of the Client struct.
 func NewClient(isMainnet bool) *Client {
-	logger := log.New()
-	logger.SetFormatter(&log.TextFormatter{
-		FullTimestamp: true,
-		PadLevelText:  true,
-	})
-	logger.SetOutput(os.Stdout)
-	logger.SetLevel(log.DebugLevel)
 	return &Client{
 		baseUrl:        getURL(isMainnet),
 		httpClient:     http.DefaultClient,
@@ -73,16 +72,20 @@ func NewClient(isMainnet bool) *Client {
 		isMainnet:      isMainnet,
 		privateKey:     "",
 		defaultAddress: "",
-		Logger:         logger,
 		keyManager:     nil,
 	}
 }
 
-// debug prints the debug messages.
-func (client *Client) debug(format string, v ...interface{}) {
-	if client.Debug {
-		client.Logger.Debugf(format, v...)
-	}
+// SetLogger sets the structured logger of the client. Signatures and keys are redacted
+// from its records, see NewRedactingHandler. nil restores the default logger.
+func (client *Client) SetLogger(logger *slog.Logger) {
+	client.logger = redactingLogger(logger)
+}
+
+// Logger returns the logger set with SetLogger. Without one, messages go to slog.Default,
+// or to stdout including debug messages in debug mode.
+func (client *Client) Logger() *slog.Logger {
+	return clientLogger(client.logger, client.Debug)
 }
 
 // SetPrivateKey sets the private key for the client.
@@ -124,144 +127,155 @@ func (client *Client) SetWebSocketAPI(wsAPI *WebSocketAPI) {
 	client.webSocketAPI = wsAPI
 }
 
-// Request sends a POST request to the HyperLiquid API.
-// If WebSocket is connected, it will use WebSocket instead of HTTP.
-func (client *Client) Request(endpoint string, payload any) ([]byte, error) {
-	// Try WebSocket first if connected
-	if client.webSocketAPI != nil && client.webSocketAPI.IsConnected() {
-		client.debug("WebSocket connected, checking if endpoint supports WebSocket...")
-		return client.requestViaWebSocket(endpoint, payload)
-	}
-
-	// Fallback to HTTP
-	client.debug("Using HTTP for request to %s (WebSocket not connected or not supported)", endpoint)
-	return client.requestViaHTTP(endpoint, payload)
+// SetTelemetry sets the Telemetry that records the round trip times of the client's HTTP requests
+func (client *Client) SetTelemetry(telemetry *Telemetry) {
+	client.telemetry = telemetry
 }
 
-// requestViaWebSocket sends a request via WebSocket
-func (client *Client) requestViaWebSocket(endpoint string, payload any) ([]byte, error) {
-	// Clean endpoint by removing leading slash
-	cleanEndpoint := strings.TrimPrefix(endpoint, "/")
-
-	// Use WebSocket for info requests
-	if cleanEndpoint == "info" {
-		client.debug("Using WebSocket for info request")
-		response, err := client.webSocketAPI.PostRequest("info", payload)
-		if err != nil {
-			return nil, err
-		}
-
-		// The WebSocket response has a nested structure:
-		// response.Response.Payload contains the actual API response
-		// We need to extract the data from the nested structure
-		if payloadMap, ok := response.Response.Payload.(map[string]interface{}); ok {
-			if data, exists := payloadMap["data"]; exists {
-				// Return the data directly
-				responseBytes, err := FastMarshal(data)
-				if err != nil {
-					return nil, err
-				}
-				return responseBytes, nil
-			}
-		}
+// Telemetry returns the Telemetry of the client, DefaultTelemetry unless set with SetTelemetry
+func (client *Client) Telemetry() *Telemetry {
+	if client.telemetry == nil {
+		return DefaultTelemetry
+	}
+	return client.telemetry
+}
 
-		// Fallback: convert response to bytes as before
-		responseBytes, err := FastMarshal(response.Response.Payload)
-		if err != nil {
-			return nil, err
-		}
+// SetMetrics sets the MetricsHook that receives the client's requests and signatures
+func (client *Client) SetMetrics(metrics MetricsHook) {
+	client.metrics = metrics
+}
 
-		return responseBytes, nil
+// Metrics returns the MetricsHook of the client, NopMetrics unless set with SetMetrics
+func (client *Client) Metrics() MetricsHook {
+	if client.metrics == nil {
+		return NopMetrics{}
 	}
+	return client.metrics
+}
 
-	// Use WebSocket for exchange requests (orders, cancels, modifies)
-	if cleanEndpoint == "exchange" {
-		client.debug("Using WebSocket for exchange request")
-
-		// Handle ExchangeRequest struct
-		exchangeReq, ok := payload.(ExchangeRequest)
-		if !ok {
-			client.debug("Invalid payload format for WebSocket exchange request")
-			return client.requestViaHTTP(endpoint, payload)
-		}
+// SetTracing sets the Tracing that creates the spans of the client's requests
+func (client *Client) SetTracing(tracing *Tracing) {
+	client.tracing = tracing
+}
 
-		client.debug("Sending via WebSocket - Action: %+v", exchangeReq.Action)
-		client.debug("Sending via WebSocket - Nonce: %d", exchangeReq.Nonce)
-		client.debug("Sending via WebSocket - Signature: %+v", exchangeReq.Signature)
+// Tracing returns the Tracing of the client, DefaultTracing unless set with SetTracing
+func (client *Client) Tracing() *Tracing {
+	if client.tracing == nil {
+		return DefaultTracing
+	}
+	return client.tracing
+}
 
-		// Send via WebSocket
-		response, err := client.webSocketAPI.PostOrderRequest(exchangeReq.Action, exchangeReq.Nonce, exchangeReq.Signature, exchangeReq.VaultAddress)
-		if err != nil {
-			client.debug("WebSocket exchange request failed: %v", err)
-			return client.requestViaHTTP(endpoint, payload)
-		}
+// Request sends a POST request to the HyperLiquid API.
+// If WebSocket is connected, it will use WebSocket instead of HTTP.
+func (client *Client) Request(endpoint string, payload any) ([]byte, error) {
+	return client.RequestContext(context.Background(), endpoint, payload)
+}
 
-		client.debug("WebSocket response received: %+v", response)
+// RequestContext is Request with a span that is a child of the span in ctx.
+// HTTP requests are canceled with ctx, WebSocket posts stop waiting for their response.
+func (client *Client) RequestContext(ctx context.Context, endpoint string, payload any) (data []byte, err error) {
+	ctx, span := client.Tracing().start(ctx, "hyperliquid.request",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(AttrEndpoint.String(requestEndpoint(endpoint))))
+	defer func() { endSpan(span, err) }()
 
-		// The WebSocket response has a nested structure:
-		// response.Response.Payload contains the actual API response
-		// We need to extract the nested response from the payload
-		if payloadMap, ok := response.Response.Payload.(map[string]interface{}); ok {
-			if nestedResponse, exists := payloadMap["response"]; exists {
-				// The nested response is the actual API response
-				// We need to wrap it in the expected OrderResponse format
-				wrappedResponse := map[string]interface{}{
-					"status":   "ok",
-					"response": nestedResponse,
-				}
+	// Try WebSocket first if connected
+	if client.webSocketAPI != nil && client.webSocketAPI.IsConnected() {
+		return client.requestViaWebSocket(ctx, endpoint, payload)
+	}
+	return client.requestViaHTTP(ctx, endpoint, payload)
+}
 
-				// Return the wrapped response
-				responseBytes, err := FastMarshal(wrappedResponse)
-				if err != nil {
-					return nil, err
-				}
-				client.debug("WebSocket wrapped response bytes: %s", string(responseBytes))
-				return responseBytes, nil
-			}
-		}
+// requestViaWebSocket sends a request via WebSocket post.
+// The returned bytes and errors are the same as requestViaHTTP would produce for the request.
+func (client *Client) requestViaWebSocket(ctx context.Context, endpoint string, payload any) ([]byte, error) {
+	var requestType string
+	switch strings.TrimPrefix(endpoint, "/") {
+	case "info":
+		requestType = "info"
+	case "exchange":
+		// Every signed action (orders, cancels, modifies, leverage, transfers) is posted as-is
+		requestType = "action"
+	default:
+		client.Logger().Debug("WebSocket not supported for endpoint, using HTTP", "endpoint", endpoint)
+		return client.requestViaHTTP(ctx, endpoint, payload)
+	}
 
-		// Fallback: convert response to bytes as before
-		responseBytes, err := FastMarshal(response.Response.Payload)
-		if err != nil {
-			return nil, err
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(AttrTransport.String(TransportWS))
+	start := time.Now()
+	response, err := client.webSocketAPI.PostRequestContext(ctx, requestType, payload)
+	if err != nil {
+		latency := time.Since(start)
+		client.Metrics().ObserveRequest(requestEndpoint(endpoint), TransportWS, latency, err)
+		// Info requests can always be retried. Actions are only retried when they were never sent,
+		// otherwise the outcome is unknown and resending could execute them twice.
+		if ctx.Err() == nil && (requestType == "info" || errors.Is(err, ErrPostNotSent)) {
+			client.Logger().Warn("WebSocket request failed, falling back to HTTP",
+				"endpoint", endpoint, "transport", TransportWS, "latency", latency, "err", err)
+			span.AddEvent("fallback to HTTP", trace.WithAttributes(attribute.String("error", err.Error())))
+			return client.requestViaHTTP(ctx, endpoint, payload)
 		}
+		client.Logger().Warn("WebSocket request failed",
+			"endpoint", endpoint, "transport", TransportWS, "latency", latency, "err", err)
+		return nil, err
+	}
 
-		client.debug("WebSocket response bytes: %s", string(responseBytes))
-		return responseBytes, nil
+	data, err := response.Response.Bytes()
+	latency := time.Since(start)
+	client.Metrics().ObserveRequest(requestEndpoint(endpoint), TransportWS, latency, err)
+	if err != nil {
+		client.Logger().Debug("WebSocket request returned error",
+			"endpoint", endpoint, "transport", TransportWS, "latency", latency, "err", err)
+		return nil, err
 	}
+	client.Logger().Debug("WebSocket request",
+		"endpoint", endpoint, "transport", TransportWS, "latency", latency, "response", data)
+	return data, nil
+}
 
-	// Fallback to HTTP for unsupported endpoints
-	client.debug("WebSocket not supported for %s endpoint, falling back to HTTP", endpoint)
-	return client.requestViaHTTP(endpoint, payload)
+// requestViaHTTP sends a request via HTTP and reports it to the MetricsHook
+func (client *Client) requestViaHTTP(ctx context.Context, endpoint string, payload any) ([]byte, error) {
+	trace.SpanFromContext(ctx).SetAttributes(AttrTransport.String(TransportHTTP))
+	start := time.Now()
+	data, err := client.postHTTP(ctx, endpoint, payload)
+	latency := time.Since(start)
+	client.Metrics().ObserveRequest(requestEndpoint(endpoint), TransportHTTP, latency, err)
+	if err != nil {
+		client.Logger().Debug("HTTP request failed",
+			"endpoint", endpoint, "transport", TransportHTTP, "latency", latency, "err", err)
+		return nil, err
+	}
+	client.Logger().Debug("HTTP request",
+		"endpoint", endpoint, "transport", TransportHTTP, "latency", latency, "response", data)
+	return data, nil
 }
 
-// requestViaHTTP sends a request via HTTP (original implementation)
-func (client *Client) requestViaHTTP(endpoint string, payload any) ([]byte, error) {
-	client.debug("Using HTTP for %s request", endpoint)
+// postHTTP sends a request via HTTP (original implementation)
+func (client *Client) postHTTP(ctx context.Context, endpoint string, payload any) ([]byte, error) {
 	endpoint = strings.TrimPrefix(endpoint, "/") // Remove leading slash if present
 	url := fmt.Sprintf("%s/%s", client.baseUrl, endpoint)
-	client.debug("Request to %s", url)
 	payloadBytes, err := FastMarshal(payload)
 	if err != nil {
-		client.debug("Error FastMarshal: %s", err)
 		return nil, err
 	}
-	client.debug("Request payload: %s", string(payloadBytes))
-	request, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	client.Logger().Debug("HTTP request payload", "endpoint", endpoint, "payload", payloadBytes)
+	request, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
-		client.debug("Error http.NewRequest: %s", err)
 		return nil, err
 	}
 	request.Header.Set("Content-Type", "application/json")
+	start := time.Now()
 	response, err := client.httpClient.Do(request)
 	if err != nil {
-		client.debug("Error client.httpClient.Do: %s", err)
 		return nil, err
 	}
 	data, err := io.ReadAll(response.Body)
 	if err != nil {
 		return nil, err
 	}
+	client.Telemetry().ObserveRTT("http:"+endpoint, time.Since(start))
 	defer func() {
 		cerr := response.Body.Close()
 		// Only overwrite the retured error if the original error was nil and an
@@ -270,9 +284,6 @@ func (client *Client) requestViaHTTP(endpoint string, payload any) ([]byte, erro
 			err = cerr
 		}
 	}()
-	client.debug("response: %#v", response)
-	client.debug("response body: %s", string(data))
-	client.debug("response status code: %d", response.StatusCode)
 	if response.StatusCode >= http.StatusBadRequest {
 		// If the status code is 400 or greater, return an error
 		return nil, APIError{Message: fmt.Sprintf("HTTP %d: %s", response.StatusCode, data)}
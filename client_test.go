@@ -0,0 +1,179 @@
+package hyperliquid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testPrivateKey = "0123456789012345678901234567890123456789012345678901234567890123"
+
+// newFakeWSServer starts a local WebSocket server that passes every received message to handler
+// and returns a connected WebSocketAPI pointing to it.
+func newFakeWSServer(t testing.TB, handler func(conn *websocket.Conn, message []byte)) *WebSocketAPI {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			handler(conn, message)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ws := NewWebSocketAPI(true)
+	ws.url = "ws" + strings.TrimPrefix(server.URL, "http")
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Failed to connect to fake server: %v", err)
+	}
+	t.Cleanup(func() { ws.Disconnect() })
+	return ws
+}
+
+// replyToPost answers a post request with the given response type and raw JSON payload
+func replyToPost(t *testing.T, conn *websocket.Conn, message []byte, responseType string, payload string) {
+	t.Helper()
+	var request WSPostRequest
+	if err := FastUnmarshal(message, &request); err != nil {
+		t.Errorf("Failed to unmarshal post request: %v", err)
+		return
+	}
+	reply := `{"channel":"post","data":{"id":` + itoa(request.ID) + `,"response":{"type":"` + responseType + `","payload":` + payload + `}}}`
+	conn.WriteMessage(websocket.TextMessage, []byte(reply))
+}
+
+func itoa(i int) string {
+	b, _ := FastMarshal(i)
+	return string(b)
+}
+
+func newTestExchangeAPI(t testing.TB, ws *WebSocketAPI) *ExchangeAPI {
+	t.Helper()
+	api := &ExchangeAPI{
+		Client:       *NewClient(true),
+		baseEndpoint: "/exchange",
+		meta:         map[string]AssetInfo{"BTC": {SzDecimals: 5, AssetId: 0}},
+	}
+	if err := api.SetPrivateKey(testPrivateKey); err != nil {
+		t.Fatalf("Failed to set private key: %v", err)
+	}
+	api.SetWebSocketAPI(ws)
+	return api
+}
+
+// TestWebSocketExchangeResponseParity checks that WS post responses are returned exactly like HTTP ones
+func TestWebSocketExchangeResponseParity(t *testing.T) {
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		FastUnmarshal(message, &request)
+		if request.Method != "post" {
+			return
+		}
+		if request.Request.Type != "action" {
+			t.Errorf("Expected action request, got %s", request.Request.Type)
+		}
+		action := request.Request.Payload.(map[string]interface{})["action"].(map[string]interface{})
+		switch action["type"] {
+		case "cancel":
+			replyToPost(t, conn, message, "action", `{"status":"err","response":"User or API Wallet does not exist."}`)
+		case "order":
+			replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"order","data":{"statuses":[{"resting":{"oid":77738308}}]}}}`)
+		case "updateLeverage":
+			replyToPost(t, conn, message, "error", `"Invalid request"`)
+		}
+	})
+	api := newTestExchangeAPI(t, ws)
+
+	_, err := api.BulkCancelOrders([]CancelOidWire{{Asset: 0, Oid: 1}})
+	if err == nil || err.Error() != "User or API Wallet does not exist." {
+		t.Fatalf("Expected exchange error to be returned, got %v", err)
+	}
+
+	response, err := api.Order(OrderRequest{
+		Coin:      "BTC",
+		IsBuy:     true,
+		Sz:        0.001,
+		LimitPx:   50000,
+		OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}},
+	}, GroupingNa)
+	if err != nil {
+		t.Fatalf("Order failed: %v", err)
+	}
+	if response.Status != "ok" || response.Response.Data.Statuses[0].Resting.OrderId != 77738308 {
+		t.Errorf("Unexpected order response: %+v", response)
+	}
+
+	_, err = api.UpdateLeverage("BTC", true, 10)
+	var apiErr APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Invalid request" {
+		t.Errorf("Expected APIError for error post response, got %v", err)
+	}
+}
+
+// TestWebSocketPostTimeout checks that timed out post requests return an error and are cleaned up
+func TestWebSocketPostTimeout(t *testing.T) {
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {})
+
+	_, err := ws.PostRequestWithTimeout("action", map[string]interface{}{"type": "noop"}, 50*time.Millisecond)
+	if !errors.Is(err, ErrPostTimeout) {
+		t.Fatalf("Expected ErrPostTimeout, got %v", err)
+	}
+
+	ws.mu.RLock()
+	pending := len(ws.postResponses)
+	ws.mu.RUnlock()
+	if pending != 0 {
+		t.Errorf("Expected no pending post responses, got %d", pending)
+	}
+
+	// A canceled context ends the wait before the post timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := ws.PostRequestContext(ctx, "action", map[string]interface{}{"type": "noop"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the post to stop waiting with its context, waited %v", elapsed)
+	}
+	if _, err := ws.PostRequestContext(ctx, "action", map[string]interface{}{"type": "noop"}); !errors.Is(err, ErrPostNotSent) {
+		t.Errorf("Expected ErrPostNotSent for a done context, got %v", err)
+	}
+}
+
+// TestWebSocketStaleReaderKeepsPosts checks that the reader of a replaced connection does not
+// fail the posts waiting on the current one
+func TestWebSocketStaleReaderKeepsPosts(t *testing.T) {
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		if FastUnmarshal(message, &request); request.Method == "post" {
+			time.Sleep(100 * time.Millisecond)
+			replyToPost(t, conn, message, "info", `{"type":"meta","data":{}}`)
+		}
+	})
+	ws.mu.RLock()
+	old := ws.conn
+	ws.mu.RUnlock()
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Failed to reconnect: %v", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() { old.Close() })
+	if _, err := ws.PostRequestWithTimeout("info", map[string]string{"type": "meta"}, 2*time.Second); err != nil {
+		t.Errorf("Expected post on the new connection to succeed, got %v", err)
+	}
+}
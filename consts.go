@@ -16,6 +16,9 @@ const SPOT_MAX_DECIMALS = 8    // Default decimals for spot
 const PERP_MAX_DECIMALS = 6    // Default decimals for perp
 var USDC_SZ_DECIMALS = 2       // Default decimals for usdc that is used for withdraw
 
+// Info constants
+const CANDLE_SNAPSHOT_LIMIT = 5000 // Max candles returned by a single candleSnapshot request
+
 // Signing constants
 const HYPERLIQUID_CHAIN_ID = 1337
 const VERIFYING_CONTRACT = "0x0000000000000000000000000000000000000000"
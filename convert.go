@@ -56,8 +56,8 @@ func OrderRequestToWire(req OrderRequest, meta map[string]AssetInfo, isSpot bool
 	return OrderWire{
 		Asset:      assetId,
 		IsBuy:      req.IsBuy,
-		LimitPx:    PriceToWire(req.LimitPx, maxDecimals, info.SzDecimals),
-		SizePx:     SizeToWire(req.Sz, info.SzDecimals),
+		LimitPx:    priceToWire(req.LimitPx, req.ExactLimitPx, maxDecimals, info.SzDecimals),
+		SizePx:     sizeToWire(req.Sz, req.ExactSz, info.SzDecimals),
 		ReduceOnly: req.ReduceOnly,
 		OrderType:  OrderTypeToWire(req.OrderType),
 		Cloid:      req.Cloid,
@@ -75,19 +75,40 @@ func ModifyOrderRequestToWire(req ModifyOrderRequest, meta map[string]AssetInfo,
 		assetId = info.AssetId
 		maxDecimals = PERP_MAX_DECIMALS
 	}
+	var orderId any = req.OrderId
+	if req.OrderCloid != "" {
+		orderId = req.OrderCloid
+	}
 	return ModifyOrderWire{
-		OrderId: req.OrderId,
+		OrderId: orderId,
 		Order: OrderWire{
 			Asset:      assetId,
 			IsBuy:      req.IsBuy,
-			LimitPx:    PriceToWire(req.LimitPx, maxDecimals, info.SzDecimals),
-			SizePx:     SizeToWire(req.Sz, info.SzDecimals),
+			LimitPx:    priceToWire(req.LimitPx, req.ExactLimitPx, maxDecimals, info.SzDecimals),
+			SizePx:     sizeToWire(req.Sz, req.ExactSz, info.SzDecimals),
 			ReduceOnly: req.ReduceOnly,
 			OrderType:  OrderTypeToWire(req.OrderType),
+			Cloid:      req.Cloid,
 		},
 	}
 }
 
+// priceToWire formats the exact price if it is set, the float price otherwise
+func priceToWire(px float64, exact *Decimal, maxDecimals int, szDecimals int) string {
+	if exact != nil {
+		return DecimalPriceToWire(*exact, maxDecimals, szDecimals)
+	}
+	return PriceToWire(px, maxDecimals, szDecimals)
+}
+
+// sizeToWire formats the exact size if it is set, the float size otherwise
+func sizeToWire(sz float64, exact *Decimal, szDecimals int) string {
+	if exact != nil {
+		return DecimalSizeToWire(*exact, szDecimals)
+	}
+	return SizeToWire(sz, szDecimals)
+}
+
 func OrderTypeToWire(orderType OrderType) OrderTypeWire {
 	if orderType.Limit != nil {
 		return OrderTypeWire{
@@ -134,6 +155,11 @@ func FloatToWire(x float64, maxDecimals int, szDecimals int) string {
 	return rounded
 }
 
+// FloatToUsdInt converts a USD amount to micro USD as used by margin actions.
+func FloatToUsdInt(x float64) int {
+	return int(math.Round(x * 1e6))
+}
+
 // fastPow10 returns 10^exp as a float64. For our purposes exp is small.
 func pow10(exp int) float64 {
 	var res float64 = 1
@@ -0,0 +1,144 @@
+package hyperliquid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const SCHEDULE_CANCEL_MIN_DELAY = 5 * time.Second // The exchange rejects scheduled cancels closer than this
+
+var ErrHeartbeatDisconnected = errors.New("websocket is not connected, cancel deadline not extended")
+
+// CancelHeartbeat is a dead man's switch built on ScheduleCancel.
+// While running it keeps moving the scheduled cancel of all open orders window ahead of the current time,
+// every interval. The deadline is only extended while the process is alive and, if the ExchangeAPI has a
+// WebSocketAPI attached, while it is connected. If either is lost the deadline passes and the exchange
+// cancels every resting order.
+type CancelHeartbeat struct {
+	api      *ExchangeAPI
+	window   time.Duration
+	interval time.Duration
+
+	mu       sync.Mutex
+	onError  func(err error)
+	deadline time.Time
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewCancelHeartbeat creates a heartbeat that schedules the cancel window ahead and refreshes it every interval.
+// The interval must be shorter than the window so the deadline is extended before it is reached.
+func NewCancelHeartbeat(api *ExchangeAPI, window time.Duration, interval time.Duration) (*CancelHeartbeat, error) {
+	if window < SCHEDULE_CANCEL_MIN_DELAY {
+		return nil, fmt.Errorf("cancel window must be at least %s, got %s", SCHEDULE_CANCEL_MIN_DELAY, window)
+	}
+	if interval <= 0 || interval >= window {
+		return nil, fmt.Errorf("heartbeat interval must be positive and shorter than the window, got %s", interval)
+	}
+	return &CancelHeartbeat{
+		api:      api,
+		window:   window,
+		interval: interval,
+	}, nil
+}
+
+// OnError sets a callback for failed heartbeats. It is called with ErrHeartbeatDisconnected
+// when the deadline was not extended because the WebSocket is down, or with the request error.
+func (h *CancelHeartbeat) OnError(handler func(err error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onError = handler
+}
+
+// Deadline returns the currently scheduled cancel time, zero if nothing is scheduled.
+func (h *CancelHeartbeat) Deadline() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.deadline
+}
+
+// Start schedules the first deadline and keeps extending it in the background.
+// The error of the first heartbeat is returned and the heartbeat is not started in that case.
+// When ctx is done the heartbeat stops without removing the schedule, so open orders
+// are canceled at the last deadline. Use Stop to remove it.
+func (h *CancelHeartbeat) Start(ctx context.Context) error {
+	h.mu.Lock()
+	if h.cancel != nil {
+		h.mu.Unlock()
+		return errors.New("cancel heartbeat already started")
+	}
+	h.mu.Unlock()
+
+	if err := h.beat(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	h.mu.Lock()
+	h.cancel = cancel
+	h.done = done
+	h.mu.Unlock()
+
+	go h.run(ctx, done)
+	return nil
+}
+
+// Stop stops the heartbeat and removes the scheduled cancel, leaving open orders untouched.
+func (h *CancelHeartbeat) Stop() error {
+	h.mu.Lock()
+	cancel, done := h.cancel, h.done
+	h.cancel, h.done = nil, nil
+	h.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	if _, err := h.api.ScheduleCancel(time.Time{}); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.deadline = time.Time{}
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *CancelHeartbeat) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.beat(); err != nil {
+				h.mu.Lock()
+				handler := h.onError
+				h.mu.Unlock()
+				if handler != nil {
+					handler(err)
+				}
+			}
+		}
+	}
+}
+
+// beat moves the deadline window ahead of now
+func (h *CancelHeartbeat) beat() error {
+	if ws := h.api.webSocketAPI; ws != nil && !ws.IsConnected() {
+		return ErrHeartbeatDisconnected
+	}
+	deadline := time.Now().Add(h.window)
+	if _, err := h.api.ScheduleCancel(deadline); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.deadline = deadline
+	h.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,76 @@
+package hyperliquid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestCancelHeartbeat tests that the heartbeat extends the scheduled cancel, removes it on Stop
+// and reports failures while the WebSocket is disconnected
+func TestCancelHeartbeat(t *testing.T) {
+	actions := make(chan map[string]interface{}, 100)
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		FastUnmarshal(message, &request)
+		if request.Method != "post" {
+			return
+		}
+		actions <- request.Request.Payload.(map[string]interface{})["action"].(map[string]interface{})
+		replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"default"}}`)
+	})
+	api := newTestExchangeAPI(t, ws)
+
+	if _, err := NewCancelHeartbeat(api, time.Second, 100*time.Millisecond); err == nil {
+		t.Error("Expected error for a window shorter than the minimum delay")
+	}
+	heartbeat, err := NewCancelHeartbeat(api, 10*time.Second, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewCancelHeartbeat failed: %v", err)
+	}
+	start := time.Now()
+	if err := heartbeat.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		action := <-actions
+		if action["type"] != "scheduleCancel" {
+			t.Fatalf("Expected scheduleCancel action, got %v", action)
+		}
+		at, _ := action["time"].(float64)
+		if int64(at) < start.Add(10*time.Second).UnixMilli() {
+			t.Errorf("Expected deadline 10s ahead, got %v", at)
+		}
+	}
+	if err := heartbeat.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	// Drain heartbeats sent before Stop, the last action must remove the schedule
+	var last map[string]interface{}
+	for len(actions) > 0 {
+		last = <-actions
+	}
+	if _, ok := last["time"]; ok || !heartbeat.Deadline().IsZero() {
+		t.Errorf("Expected scheduled cancel to be removed, got %v", last)
+	}
+
+	failures := make(chan error, 10)
+	heartbeat.OnError(func(err error) { failures <- err })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := heartbeat.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	ws.Disconnect()
+	select {
+	case err := <-failures:
+		if !errors.Is(err, ErrHeartbeatDisconnected) {
+			t.Errorf("Expected ErrHeartbeatDisconnected, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected heartbeat failure after disconnect")
+	}
+}
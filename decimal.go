@@ -0,0 +1,473 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var bigTen = big.NewInt(10)
+
+// Decimal is an exact decimal number, the value is coef * 10^-scale.
+// It is used where float64 rounding is not acceptable: prices and sizes parsed from exchange
+// strings and order wires. The zero value is 0. Decimals are immutable, every operation
+// returns a new value.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// NewDecimal returns coef * 10^-scale, e.g. NewDecimal(12345, 2) is 123.45
+func NewDecimal(coef int64, scale int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), scale: scale}.normalize()
+}
+
+// NewDecimalFromFloat returns the shortest decimal that converts back to f.
+// The decimal of a float64 parsed from a string with at most 15 significant digits is that string.
+func NewDecimalFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}
+	}
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	return d
+}
+
+// ParseDecimal parses a decimal string such as "-0.0012", "42" or "1.5e-3" without rounding
+func ParseDecimal(s string) (Decimal, error) {
+	str := s
+	var exp int64
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		var err error
+		exp, err = strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal: %q", s)
+		}
+		str = str[:i]
+	}
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "+")
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal: %q", s)
+	}
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if negative {
+		coef.Neg(coef)
+	}
+	scale := int64(len(fracPart)) - exp
+	if scale < 0 {
+		coef.Mul(coef, new(big.Int).Exp(bigTen, big.NewInt(-scale), nil))
+		scale = 0
+	}
+	if scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal out of range: %q", s)
+	}
+	return Decimal{coef: coef, scale: int32(scale)}.normalize(), nil
+}
+
+// MustParseDecimal is ParseDecimal that panics on invalid input, for constants
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// normalize removes trailing zeros of the fraction
+func (d Decimal) normalize() Decimal {
+	if d.coef == nil || d.coef.Sign() == 0 {
+		return Decimal{}
+	}
+	coef := new(big.Int).Set(d.coef)
+	scale := d.scale
+	if scale < 0 {
+		coef.Mul(coef, new(big.Int).Exp(bigTen, big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+	remainder := new(big.Int)
+	for scale > 0 {
+		quotient, r := new(big.Int).QuoRem(coef, bigTen, remainder)
+		if r.Sign() != 0 {
+			break
+		}
+		coef = quotient
+		scale--
+	}
+	return Decimal{coef: coef, scale: scale}
+}
+
+func (d Decimal) bigCoef() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient of d at a scale not smaller than d.scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	coef := new(big.Int).Set(d.bigCoef())
+	if scale > d.scale {
+		coef.Mul(coef, new(big.Int).Exp(bigTen, big.NewInt(int64(scale-d.scale)), nil))
+	}
+	return coef
+}
+
+func align(a Decimal, b Decimal) (*big.Int, *big.Int, int32) {
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: a.Add(a, b), scale: scale}.normalize()
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{coef: a.Sub(a, b), scale: scale}.normalize()
+}
+
+// Mul returns d * other
+func (d Decimal) Mul(other Decimal) Decimal {
+	coef := new(big.Int).Mul(d.bigCoef(), other.bigCoef())
+	return Decimal{coef: coef, scale: d.scale + other.scale}.normalize()
+}
+
+// Div returns d / other rounded half away from zero to places decimals.
+// It panics if other is zero.
+func (d Decimal) Div(other Decimal, places int32) Decimal {
+	if other.IsZero() {
+		panic("hyperliquid: decimal division by zero")
+	}
+	// d / other = (a * 10^-sa) / (b * 10^-sb), computed with one extra digit for rounding
+	shift := int64(places) + 1 + int64(other.scale) - int64(d.scale)
+	numerator := new(big.Int).Set(d.bigCoef())
+	denominator := new(big.Int).Set(other.bigCoef())
+	if shift >= 0 {
+		numerator.Mul(numerator, new(big.Int).Exp(bigTen, big.NewInt(shift), nil))
+	} else {
+		denominator.Mul(denominator, new(big.Int).Exp(bigTen, big.NewInt(-shift), nil))
+	}
+	quotient := numerator.Quo(numerator, denominator)
+	return Decimal{coef: quotient, scale: places + 1}.Round(places)
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.bigCoef()), scale: d.scale}.normalize()
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.bigCoef()), scale: d.scale}.normalize()
+}
+
+// Cmp compares d and other and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and other are the same number
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Sign returns -1, 0 or +1
+func (d Decimal) Sign() int {
+	return d.bigCoef().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsInteger reports whether d has no fractional part
+func (d Decimal) IsInteger() bool {
+	return d.normalize().scale <= 0
+}
+
+// Decimals returns the number of decimal places of d
+func (d Decimal) Decimals() int {
+	return int(d.normalize().scale)
+}
+
+// Round rounds d half away from zero to places decimals
+func (d Decimal) Round(places int32) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	divisor := new(big.Int).Exp(bigTen, big.NewInt(int64(d.scale-places)), nil)
+	quotient, remainder := new(big.Int).QuoRem(d.bigCoef(), divisor, new(big.Int))
+	// Round up if the remainder is at least half of the divisor
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(divisor) >= 0 {
+		if d.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Decimal{coef: quotient, scale: places}.normalize()
+}
+
+// Truncate drops the decimals of d after places
+func (d Decimal) Truncate(places int32) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	divisor := new(big.Int).Exp(bigTen, big.NewInt(int64(d.scale-places)), nil)
+	return Decimal{coef: new(big.Int).Quo(d.bigCoef(), divisor), scale: places}.normalize()
+}
+
+// significantPlaces returns the number of decimals that keeps figures significant figures of d
+func (d Decimal) significantPlaces(figures int) int32 {
+	digits := len(new(big.Int).Abs(d.bigCoef()).String())
+	// digits - scale is the position of the first significant digit relative to the decimal point
+	return int32(figures) - (int32(digits) - d.scale)
+}
+
+// Float64 returns the float64 nearest to d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d without exponent and trailing zeros
+func (d Decimal) String() string {
+	d = d.normalize()
+	if d.coef == nil {
+		return "0"
+	}
+	digits := new(big.Int).Abs(d.coef).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		point := len(digits) - int(d.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if d.coef.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON encodes d as a string like the exchange does
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts decimal strings and numbers, null leaves d unchanged
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	parsed, err := ParseDecimal(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// DecimalPriceToWire is PriceToWire without float rounding.
+// The price is rounded half away from zero to at most 5 significant figures and
+// maxDecimals - szDecimals decimals. Integer prices are returned as is.
+func DecimalPriceToWire(px Decimal, maxDecimals int, szDecimals int) string {
+	if px.IsInteger() {
+		return px.String()
+	}
+	places := int32(maxDecimals - szDecimals)
+	if significant := px.significantPlaces(5); significant < places {
+		places = significant
+	}
+	if places < 0 {
+		places = 0
+	}
+	return px.Round(places).String()
+}
+
+// DecimalSizeToWire is SizeToWire without float rounding, the size is rounded half away from zero to szDecimals
+func DecimalSizeToWire(sz Decimal, szDecimals int) string {
+	return sz.Round(int32(szDecimals)).String()
+}
+
+// exactOrFloat returns the decoded decimal string of a field if it still matches the float value, so
+// fields that were changed after decoding fall back to the float value
+func exactOrFloat(exact string, value float64) Decimal {
+	if exact != "" {
+		if d, err := ParseDecimal(exact); err == nil && d.Float64() == value {
+			return d
+		}
+	}
+	return NewDecimalFromFloat(value)
+}
+
+// exactField pairs a decoded decimal string with the float field it sets
+type exactField struct {
+	exact string
+	value *float64
+}
+
+// setExactFloats sets the float fields from the decoded strings of their exact values.
+// Like the ,string tag it rejects strings that are not numbers, empty strings leave the field zero.
+func setExactFloats(fields ...exactField) error {
+	for _, field := range fields {
+		if field.exact == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(field.exact, 64)
+		if err != nil {
+			return fmt.Errorf("invalid decimal %q: %w", field.exact, err)
+		}
+		*field.value = value
+	}
+	return nil
+}
+
+// positionDecimals keeps the decoded strings of the prices and sizes of a position.
+// Strings keep decoded positions comparable with ==.
+type positionDecimals struct {
+	EntryPx       string `json:"entryPx"`
+	LiquidationPx string `json:"liquidationPx"`
+	MarginUsed    string `json:"marginUsed"`
+	PositionValue string `json:"positionValue"`
+	Szi           string `json:"szi"`
+	UnrealizedPnl string `json:"unrealizedPnl"`
+}
+
+// UnmarshalJSON decodes the position in one pass and keeps the exact values of its prices and sizes
+func (p *Position) UnmarshalJSON(data []byte) error {
+	type position Position
+	type nested struct{ *position }
+	// The decimals are less nested than the position, so their fields take the place of its ,string fields
+	decoded := struct {
+		nested
+		*positionDecimals
+	}{nested{(*position)(p)}, &p.exact}
+	if err := FastUnmarshal(data, &decoded); err != nil {
+		return err
+	}
+	return setExactFloats(
+		exactField{p.exact.EntryPx, &p.EntryPx},
+		exactField{p.exact.LiquidationPx, &p.LiquidationPx},
+		exactField{p.exact.MarginUsed, &p.MarginUsed},
+		exactField{p.exact.PositionValue, &p.PositionValue},
+		exactField{p.exact.Szi, &p.Szi},
+		exactField{p.exact.UnrealizedPnl, &p.UnrealizedPnl},
+	)
+}
+
+// ExactEntryPx returns EntryPx as a decimal
+func (p Position) ExactEntryPx() Decimal { return exactOrFloat(p.exact.EntryPx, p.EntryPx) }
+
+// ExactLiquidationPx returns LiquidationPx as a decimal
+func (p Position) ExactLiquidationPx() Decimal {
+	return exactOrFloat(p.exact.LiquidationPx, p.LiquidationPx)
+}
+
+// ExactMarginUsed returns MarginUsed as a decimal
+func (p Position) ExactMarginUsed() Decimal { return exactOrFloat(p.exact.MarginUsed, p.MarginUsed) }
+
+// ExactPositionValue returns PositionValue as a decimal
+func (p Position) ExactPositionValue() Decimal {
+	return exactOrFloat(p.exact.PositionValue, p.PositionValue)
+}
+
+// ExactSzi returns Szi as a decimal
+func (p Position) ExactSzi() Decimal { return exactOrFloat(p.exact.Szi, p.Szi) }
+
+// ExactUnrealizedPnl returns UnrealizedPnl as a decimal
+func (p Position) ExactUnrealizedPnl() Decimal {
+	return exactOrFloat(p.exact.UnrealizedPnl, p.UnrealizedPnl)
+}
+
+// orderDecimals keeps the decoded strings of the prices and sizes of an order
+type orderDecimals struct {
+	LimitPx   string `json:"limitPx"`
+	OrigSz    string `json:"origSz"`
+	Sz        string `json:"sz"`
+	TriggerPx string `json:"triggerPx"`
+}
+
+// UnmarshalJSON decodes the order in one pass and keeps the exact values of its prices and sizes
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	type nested struct{ *order }
+	decoded := struct {
+		nested
+		*orderDecimals
+	}{nested{(*order)(o)}, &o.exact}
+	if err := FastUnmarshal(data, &decoded); err != nil {
+		return err
+	}
+	return setExactFloats(
+		exactField{o.exact.LimitPx, &o.LimitPx},
+		exactField{o.exact.OrigSz, &o.OrigSz},
+		exactField{o.exact.Sz, &o.Sz},
+		exactField{o.exact.TriggerPx, &o.TriggerPx},
+	)
+}
+
+// ExactLimitPx returns LimitPx as a decimal
+func (o Order) ExactLimitPx() Decimal { return exactOrFloat(o.exact.LimitPx, o.LimitPx) }
+
+// ExactOrigSz returns OrigSz as a decimal
+func (o Order) ExactOrigSz() Decimal { return exactOrFloat(o.exact.OrigSz, o.OrigSz) }
+
+// ExactSz returns Sz as a decimal
+func (o Order) ExactSz() Decimal { return exactOrFloat(o.exact.Sz, o.Sz) }
+
+// ExactTriggerPx returns TriggerPx as a decimal
+func (o Order) ExactTriggerPx() Decimal { return exactOrFloat(o.exact.TriggerPx, o.TriggerPx) }
+
+// fillDecimals keeps the decoded strings of the prices and sizes of a fill
+type fillDecimals struct {
+	ClosedPnl string `json:"closedPnl"`
+	Fee       string `json:"fee"`
+	Px        string `json:"px"`
+	Sz        string `json:"sz"`
+}
+
+// UnmarshalJSON decodes the fill in one pass and keeps the exact values of its prices and sizes
+func (f *OrderFill) UnmarshalJSON(data []byte) error {
+	type fill OrderFill
+	type nested struct{ *fill }
+	decoded := struct {
+		nested
+		*fillDecimals
+	}{nested{(*fill)(f)}, &f.exact}
+	if err := FastUnmarshal(data, &decoded); err != nil {
+		return err
+	}
+	return setExactFloats(
+		exactField{f.exact.ClosedPnl, &f.ClosedPnl},
+		exactField{f.exact.Fee, &f.Fee},
+		exactField{f.exact.Px, &f.Px},
+		exactField{f.exact.Sz, &f.Sz},
+	)
+}
+
+// ExactClosedPnl returns ClosedPnl as a decimal
+func (f OrderFill) ExactClosedPnl() Decimal { return exactOrFloat(f.exact.ClosedPnl, f.ClosedPnl) }
+
+// ExactFee returns Fee as a decimal
+func (f OrderFill) ExactFee() Decimal { return exactOrFloat(f.exact.Fee, f.Fee) }
+
+// ExactPx returns Px as a decimal
+func (f OrderFill) ExactPx() Decimal { return exactOrFloat(f.exact.Px, f.Px) }
+
+// ExactSz returns Sz as a decimal
+func (f OrderFill) ExactSz() Decimal { return exactOrFloat(f.exact.Sz, f.Sz) }
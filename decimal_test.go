@@ -0,0 +1,153 @@
+package hyperliquid
+
+import (
+	"testing"
+)
+
+// TestDecimalArithmetic tests parsing, formatting, arithmetic and rounding of decimals
+func TestDecimalArithmetic(t *testing.T) {
+	for input, want := range map[string]string{
+		"0.1":                     "0.1",
+		"-000123.4500":            "-123.45",
+		"1e-7":                    "0.0000001",
+		"1.5E3":                   "1500",
+		"0.000000000000000000001": "0.000000000000000000001",
+		"-0":                      "0",
+	} {
+		d, err := ParseDecimal(input)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) failed: %v", input, err)
+			continue
+		}
+		if d.String() != want {
+			t.Errorf("ParseDecimal(%q): expected %s, got %s", input, want, d)
+		}
+	}
+	for _, input := range []string{"", "abc", "1.2.3", "0x10"} {
+		if _, err := ParseDecimal(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+
+	if sum := MustParseDecimal("0.1").Add(MustParseDecimal("0.2")); !sum.Equal(MustParseDecimal("0.3")) {
+		t.Errorf("Expected 0.1 + 0.2 = 0.3, got %s", sum)
+	}
+	if diff := MustParseDecimal("1").Sub(MustParseDecimal("1.0001")); diff.String() != "-0.0001" {
+		t.Errorf("Expected -0.0001, got %s", diff)
+	}
+	if product := MustParseDecimal("1.1").Mul(MustParseDecimal("1.1")); product.String() != "1.21" {
+		t.Errorf("Expected 1.21, got %s", product)
+	}
+	if quotient := MustParseDecimal("2").Div(MustParseDecimal("3"), 4); quotient.String() != "0.6667" {
+		t.Errorf("Expected 0.6667, got %s", quotient)
+	}
+	if rounded := MustParseDecimal("-2.5").Round(0); rounded.String() != "-3" {
+		t.Errorf("Expected half away from zero rounding to -3, got %s", rounded)
+	}
+	if truncated := MustParseDecimal("1.999").Truncate(2); truncated.String() != "1.99" {
+		t.Errorf("Expected 1.99, got %s", truncated)
+	}
+
+	var decoded struct {
+		Quoted Decimal  `json:"quoted"`
+		Number Decimal  `json:"number"`
+		Null   *Decimal `json:"null"`
+	}
+	if err := FastUnmarshal([]byte(`{"quoted":"12.340","number":0.5,"null":null}`), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal decimals: %v", err)
+	}
+	if decoded.Quoted.String() != "12.34" || decoded.Number.String() != "0.5" || decoded.Null != nil {
+		t.Errorf("Unexpected decoded decimals: %+v", decoded)
+	}
+	if data, _ := FastMarshal(decoded.Quoted); string(data) != `"12.34"` {
+		t.Errorf("Expected quoted JSON decimal, got %s", data)
+	}
+}
+
+// TestDecimalWire tests that decimal wires match the float wires and keep precision the floats lose
+func TestDecimalWire(t *testing.T) {
+	for _, px := range []float64{50000, 123456.7, 1234.56, 0.00012345678, 3.14159, 0.1, 99999.5} {
+		want := PriceToWire(px, PERP_MAX_DECIMALS, 2)
+		if got := DecimalPriceToWire(NewDecimalFromFloat(px), PERP_MAX_DECIMALS, 2); got != want {
+			t.Errorf("DecimalPriceToWire(%v): expected %s, got %s", px, want, got)
+		}
+	}
+	for _, sz := range []float64{1, 0.01, 0.123456, 12.5} {
+		want := SizeToWire(sz, 4)
+		if got := DecimalSizeToWire(NewDecimalFromFloat(sz), 4); got != want {
+			t.Errorf("DecimalSizeToWire(%v): expected %s, got %s", sz, want, got)
+		}
+	}
+
+	// 1.00005 and 1.005 are slightly below the halfway points as floats
+	exactPx := MustParseDecimal("1.00005")
+	exactSz := MustParseDecimal("1.005")
+	order := OrderRequest{
+		Coin: "BTC", IsBuy: true, Sz: 1.005, LimitPx: 1.00005,
+		ExactSz: &exactSz, ExactLimitPx: &exactPx,
+		OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}},
+	}
+	meta := map[string]AssetInfo{"BTC": {SzDecimals: 2}}
+	wire := OrderRequestToWire(order, meta, false)
+	if wire.LimitPx != "1.0001" || wire.SizePx != "1.01" {
+		t.Errorf("Expected exact wire 1.0001 / 1.01, got %s / %s", wire.LimitPx, wire.SizePx)
+	}
+	if sz := OrderRequestToWire(OrderRequest{Coin: "BTC", Sz: 1.005}, meta, false).SizePx; sz != "1" {
+		t.Errorf("Expected float wire 1, got %s", sz)
+	}
+}
+
+// TestExactAccessors tests the decimal accessors of decoded positions, orders and fills
+func TestExactAccessors(t *testing.T) {
+	var position Position
+	data := `{"coin":"BTC","entryPx":"0.123456789012345678901","szi":"-1.10","positionValue":"10.1",
+		"unrealizedPnl":"0.3","marginUsed":"1","liquidationPx":null,"returnOnEquity":"0",
+		"leverage":{"type":"cross","value":10},"cumFunding":{"allTime":"0","sinceOpen":"0","sinceChange":"0"}}`
+	if err := FastUnmarshal([]byte(data), &position); err != nil {
+		t.Fatalf("Failed to unmarshal position: %v", err)
+	}
+	if got := position.ExactEntryPx().String(); got != "0.123456789012345678901" {
+		t.Errorf("Expected lossless entry price, got %s", got)
+	}
+	if position.ExactSzi().String() != "-1.1" || position.Coin != "BTC" || position.Leverage.Value != 10 {
+		t.Errorf("Unexpected decoded position: %+v", position)
+	}
+	if !position.ExactLiquidationPx().IsZero() {
+		t.Errorf("Expected zero liquidation price, got %s", position.ExactLiquidationPx())
+	}
+	position.Szi = 2
+	if position.ExactSzi().String() != "2" {
+		t.Errorf("Expected changed size to be used, got %s", position.ExactSzi())
+	}
+
+	var fills []OrderFill
+	if err := FastUnmarshal([]byte(`[{"coin":"ETH","px":"2500.123456789","sz":"0.3","fee":"0.000000000001","closedPnl":"-0.1","oid":7}]`), &fills); err != nil {
+		t.Fatalf("Failed to unmarshal fills: %v", err)
+	}
+	if len(fills) != 1 || fills[0].Oid != 7 || fills[0].ExactPx().String() != "2500.123456789" || fills[0].ExactFee().String() != "0.000000000001" {
+		t.Errorf("Unexpected decoded fills: %+v", fills)
+	}
+
+	var order Order
+	if err := FastUnmarshal([]byte(`{"coin":"BTC","limitPx":"100000.1","sz":"0.00001","origSz":"0.00002","oid":1}`), &order); err != nil {
+		t.Fatalf("Failed to unmarshal order: %v", err)
+	}
+	if order.ExactLimitPx().String() != "100000.1" || order.ExactSz().String() != "0.00001" || order.ExactOrigSz().String() != "0.00002" {
+		t.Errorf("Unexpected decoded order: %+v", order)
+	}
+	if (Order{Sz: 0.5}).ExactSz().String() != "0.5" {
+		t.Error("Expected float fallback for orders that were not decoded")
+	}
+
+	// Decoded values stay comparable and keep the float fields
+	var again []OrderFill
+	if err := FastUnmarshal([]byte(`[{"coin":"ETH","px":"2500.123456789","sz":"0.3","fee":"0.000000000001","closedPnl":"-0.1","oid":7}]`), &again); err != nil {
+		t.Fatalf("Failed to unmarshal fills: %v", err)
+	}
+	if again[0] != fills[0] || again[0].Px != 2500.123456789 || again[0].ClosedPnl != -0.1 {
+		t.Errorf("Expected equal decoded fills, got %+v and %+v", fills[0], again[0])
+	}
+	if err := FastUnmarshal([]byte(`{"coin":"BTC","limitPx":"abc","oid":1}`), &order); err == nil {
+		t.Error("Expected an error for an invalid decimal")
+	}
+}
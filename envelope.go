@@ -0,0 +1,168 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ActionEnvelope is an unsigned exchange action in a portable JSON form. It allows a two phase
+// workflow: the envelope is built where the market data is, signed on another machine
+// (e.g. an air-gapped one) and submitted later with SubmitSigned.
+//
+// TypedData is the EIP-712 payload to sign. It can be signed with ExchangeAPI.SignEnvelope
+// or by any external EIP-712 signer (eth_signTypedData_v4).
+//
+//	envelope, _ := client.BuildWithdrawEnvelope(destination, 100)
+//	data, _ := FastMarshal(envelope)
+//	// ... on the signing machine
+//	signature, _ := signer.SignEnvelope(envelope)
+//	// ... back online
+//	response, _ := SubmitSigned[WithdrawResponse](client.ExchangeAPI, envelope, signature)
+type ActionEnvelope struct {
+	Action       jsoniter.RawMessage `json:"action"`
+	Nonce        uint64              `json:"nonce"`
+	VaultAddress *string             `json:"vaultAddress,omitempty"`
+	ExpiresAfter *uint64             `json:"expiresAfter,omitempty"`
+	IsMainnet    bool                `json:"isMainnet"`
+	TypedData    apitypes.TypedData  `json:"typedData"`
+}
+
+// Request returns the exchange request of the envelope signed with signature
+func (envelope *ActionEnvelope) Request(signature RsvSignature) ExchangeRequest {
+	return ExchangeRequest{
+		Action:       envelope.Action,
+		Nonce:        envelope.Nonce,
+		Signature:    signature,
+		VaultAddress: envelope.VaultAddress,
+		ExpiresAfter: envelope.ExpiresAfter,
+	}
+}
+
+// BuildL1Envelope builds an envelope of an L1 action (orders, cancels, leverage, ...)
+// with a fresh nonce and the configured expiry
+func (api *ExchangeAPI) BuildL1Envelope(action any) (*ActionEnvelope, error) {
+	timestamp := GetNonce()
+	expiresAfter := api.nextExpiry()
+	srequest, err := api.buildL1SignRequest(action, timestamp, expiresAfter)
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := newActionEnvelope(action, timestamp, srequest)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAfter > 0 {
+		envelope.ExpiresAfter = &expiresAfter
+	}
+	return envelope, nil
+}
+
+// BuildUserSignedEnvelope builds an envelope of a user signable action (withdrawals, transfers, ...).
+// nonce must be the nonce (or time) field of the action.
+func (api *ExchangeAPI) BuildUserSignedEnvelope(action any, nonce uint64, payloadTypes []apitypes.Type, primaryType string) (*ActionEnvelope, error) {
+	srequest, err := api.buildUserSignRequest(action, payloadTypes, primaryType)
+	if err != nil {
+		return nil, err
+	}
+	return newActionEnvelope(action, nonce, srequest)
+}
+
+// BuildOrderEnvelope builds an envelope of bulk orders
+func (api *ExchangeAPI) BuildOrderEnvelope(requests []OrderRequest, grouping Grouping, isSpot bool) (*ActionEnvelope, error) {
+	action, err := api.buildOrderAction(requests, grouping, isSpot)
+	if err != nil {
+		return nil, err
+	}
+	return api.BuildL1Envelope(action)
+}
+
+// BuildWithdrawEnvelope builds an envelope of a withdrawal
+func (api *ExchangeAPI) BuildWithdrawEnvelope(destination string, amount float64) (*ActionEnvelope, error) {
+	action := api.newWithdrawAction(destination, amount)
+	return api.BuildUserSignedEnvelope(action, action.Time, withdrawSignTypes, withdrawPrimaryType)
+}
+
+// SignEnvelope signs the typed data of envelope with the private key of the API.
+// It does not need a connection, so it can run on an offline machine.
+//
+// The machine that built the envelope is not trusted: the typed data is rebuilt from the
+// action, nonce, vault address and expiry of the envelope and nothing is signed unless both match.
+// The action must be one RecoverSigner knows.
+func (api *ExchangeAPI) SignEnvelope(envelope *ActionEnvelope) (RsvSignature, error) {
+	if envelope.IsMainnet != api.IsMainnet() {
+		return RsvSignature{}, APIError{Message: "Envelope was built for another network"}
+	}
+	if api.keyManager == nil {
+		return RsvSignature{}, APIError{Message: "API key not set"}
+	}
+	if err := envelope.verifyTypedData(); err != nil {
+		return RsvSignature{}, err
+	}
+	signer := NewSigner(api.keyManager)
+	v, r, s, err := signer.SignTypedData(envelope.TypedData)
+	if err != nil {
+		return RsvSignature{}, err
+	}
+	return ToTypedSig(r, s, v), nil
+}
+
+// verifyTypedData checks that the typed data of envelope is the one of its action
+func (envelope *ActionEnvelope) verifyTypedData() error {
+	if len(envelope.Action) == 0 {
+		return APIError{Message: "Envelope has no action"}
+	}
+	expected, err := requestTypedData(envelope.Request(RsvSignature{}), envelope.IsMainnet)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild envelope typed data: %w", err)
+	}
+	expectedHash, _, err := apitypes.TypedDataAndHash(expected)
+	if err != nil {
+		return fmt.Errorf("failed to hash envelope typed data: %w", err)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(envelope.TypedData)
+	if err != nil {
+		return fmt.Errorf("failed to hash envelope typed data: %w", err)
+	}
+	if !bytes.Equal(hash, expectedHash) {
+		return APIError{Message: "Envelope typed data does not match its action"}
+	}
+	return nil
+}
+
+// SubmitSigned sends the action of envelope with a signature produced elsewhere
+func SubmitSigned[T any](api *ExchangeAPI, envelope *ActionEnvelope, signature RsvSignature) (*T, error) {
+	if envelope.IsMainnet != api.IsMainnet() {
+		return nil, APIError{Message: "Envelope was built for another network"}
+	}
+	if len(envelope.Action) == 0 {
+		return nil, APIError{Message: "Envelope has no action"}
+	}
+	return MakeUniversalRequest[T](api, envelope.Request(signature))
+}
+
+func newActionEnvelope(action any, nonce uint64, srequest *SignRequest) (*ActionEnvelope, error) {
+	data, err := FastMarshal(action)
+	if err != nil {
+		return nil, fmt.Errorf("error while marshaling action: %w", err)
+	}
+	typedData := SignRequestToEIP712TypedData(srequest)
+	// Byte values are exported as hex so the typed data is valid eth_signTypedData input
+	message := make(apitypes.TypedDataMessage, len(typedData.Message))
+	for key, value := range typedData.Message {
+		if b, ok := value.([]byte); ok {
+			value = hexutil.Encode(b)
+		}
+		message[key] = value
+	}
+	typedData.Message = message
+	return &ActionEnvelope{
+		Action:    data,
+		Nonce:     nonce,
+		IsMainnet: srequest.IsMainNet,
+		TypedData: typedData,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package hyperliquid
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestActionEnvelope tests exporting L1 and user signed actions, signing them offline
+// after a JSON round trip and submitting them with the external signature
+func TestActionEnvelope(t *testing.T) {
+	payloads := make(chan map[string]interface{}, 10)
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		FastUnmarshal(message, &request)
+		if request.Method != "post" {
+			return
+		}
+		payloads <- request.Request.Payload.(map[string]interface{})
+		replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"default"}}`)
+	})
+	online := newTestExchangeAPI(t, ws)
+	offline := newTestExchangeAPI(t, nil)
+
+	roundTrip := func(envelope *ActionEnvelope) *ActionEnvelope {
+		data, err := FastMarshal(envelope)
+		if err != nil {
+			t.Fatalf("Failed to marshal envelope: %v", err)
+		}
+		var exported ActionEnvelope
+		if err := FastUnmarshal(data, &exported); err != nil {
+			t.Fatalf("Failed to unmarshal envelope: %v", err)
+		}
+		return &exported
+	}
+
+	order := OrderRequest{Coin: "BTC", IsBuy: true, Sz: 0.01, LimitPx: 50000, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}}
+	envelope, err := online.BuildOrderEnvelope([]OrderRequest{order}, GroupingNa, false)
+	if err != nil {
+		t.Fatalf("BuildOrderEnvelope failed: %v", err)
+	}
+	signature, err := offline.SignEnvelope(roundTrip(envelope))
+	if err != nil {
+		t.Fatalf("SignEnvelope failed: %v", err)
+	}
+	action, _ := online.buildOrderAction([]OrderRequest{order}, GroupingNa, false)
+	v, r, s, _ := offline.SignL1Action(action, envelope.Nonce)
+	if signature != ToTypedSig(r, s, v) {
+		t.Errorf("Offline signature %+v does not match the L1 signature", signature)
+	}
+	if _, err := SubmitSigned[DefaultExchangeResponse](online, envelope, signature); err != nil {
+		t.Fatalf("SubmitSigned failed: %v", err)
+	}
+	payload := <-payloads
+	var expected map[string]interface{}
+	FastUnmarshal(envelope.Action, &expected)
+	if !reflect.DeepEqual(payload["action"], expected) || payload["nonce"] != float64(envelope.Nonce) || payload["signature"].(map[string]interface{})["r"] != signature.R {
+		t.Errorf("Unexpected submitted request: %v", payload)
+	}
+
+	withdrawal, err := online.BuildWithdrawEnvelope("0x0000000000000000000000000000000000000001", 10)
+	if err != nil {
+		t.Fatalf("BuildWithdrawEnvelope failed: %v", err)
+	}
+	signature, err = offline.SignEnvelope(roundTrip(withdrawal))
+	if err != nil {
+		t.Fatalf("SignEnvelope failed: %v", err)
+	}
+	var withdrawAction WithdrawAction
+	FastUnmarshal(withdrawal.Action, &withdrawAction)
+	v, r, s, _ = offline.SignWithdrawAction(withdrawAction)
+	if signature != ToTypedSig(r, s, v) {
+		t.Errorf("Offline signature %+v does not match the withdraw signature", signature)
+	}
+	if _, err := SubmitSigned[WithdrawResponse](online, withdrawal, signature); err != nil {
+		t.Fatalf("SubmitSigned failed: %v", err)
+	}
+	if payload := <-payloads; payload["action"].(map[string]interface{})["type"] != "withdraw3" {
+		t.Errorf("Unexpected submitted withdrawal: %v", payload)
+	}
+
+	// Typed data that does not match the action is not signed
+	tampered := roundTrip(withdrawal)
+	tampered.TypedData.Message["destination"] = "0x0000000000000000000000000000000000000002"
+	if _, err := offline.SignEnvelope(tampered); err == nil {
+		t.Error("Expected error signing a tampered withdraw destination")
+	}
+	tampered = roundTrip(envelope)
+	tampered.Nonce++
+	if _, err := offline.SignEnvelope(tampered); err == nil {
+		t.Error("Expected error signing typed data of another nonce")
+	}
+
+	testnet := newTestExchangeAPI(t, nil)
+	testnet.Client = *NewClient(false)
+	testnet.SetPrivateKey(testPrivateKey)
+	if _, err := testnet.SignEnvelope(withdrawal); err == nil {
+		t.Error("Expected error for envelope of another network")
+	}
+}
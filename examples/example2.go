@@ -34,13 +34,10 @@ func main() {
 		log.Println("WebSocket connected successfully")
 	}
 
-	err = hl.WebSocketAPI.SubscribeUserFills(config.AccountAddress, func(data interface{}) {
-		// Try to assert the expected structure: map[string]interface{} with "isSnapshot" key
-		if m, ok := data.(map[string]interface{}); ok {
-			if isSnapshot, ok := m["isSnapshot"].(bool); ok && isSnapshot {
-				// Don't print snapshot
-				return
-			}
+	_, err = hl.WebSocketAPI.SubscribeUserFills(config.AccountAddress, func(data interface{}) {
+		if fills, ok := data.(hyperliquid.UserFills); ok && fills.IsSnapshot {
+			// Don't print snapshot
+			return
 		}
 		log.Printf("%+v\n-----\n", data)
 	})
@@ -1,33 +1,55 @@
 package hyperliquid
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // IExchangeAPI is an interface for the /exchange service.
 type IExchangeAPI interface {
 	// Open orders
-	BulkOrders(requests []OrderRequest, grouping Grouping) (*OrderResponse, error)
+	BulkOrders(requests []OrderRequest, grouping Grouping, isSpot bool) (*OrderResponse, error)
 	Order(request OrderRequest, grouping Grouping) (*OrderResponse, error)
 	MarketOrder(coin string, size float64, slippage *float64, clientOID ...string) (*OrderResponse, error)
-	LimitOrder(orderType string, coin string, size float64, px float64, isBuy bool, reduceOnly bool, clientOID ...string) (*OrderResponse, error)
+	LimitOrder(orderType string, coin string, size float64, px float64, reduceOnly bool, clientOID ...string) (*OrderResponse, error)
 
 	// Order management
-	CancelOrderByOID(coin string, orderID int) (any, error)
-	CancelOrderByCloid(coin string, clientOID string) (any, error)
-	BulkCancelOrders(cancels []CancelOidWire) (any, error)
-	CancelAllOrdersByCoin(coin string) (any, error)
-	CancelAllOrders() (any, error)
+	CancelOrderByOID(coin string, orderID int64) (*OrderResponse, error)
+	CancelOrderByCloid(coin string, clientOID string) (*OrderResponse, error)
+	BulkCancelOrdersByCloid(cancels []CancelCloidWire) (*OrderResponse, error)
+	BulkModifyOrders(modifyRequests []ModifyOrderRequest, isSpot bool) (*OrderResponse, error)
+	Replace(request OrderRequest) (*OrderResponse, error)
+	BulkReplace(requests []OrderRequest, isSpot bool) (*OrderResponse, error)
+	BulkCancelOrders(cancels []CancelOidWire) (*OrderResponse, error)
+	CancelAllOrdersByCoin(coin string) (*OrderResponse, error)
+	CancelAllOrders() (*OrderResponse, error)
 	ClosePosition(coin string) (*OrderResponse, error)
 
 	// Account management
 	Withdraw(destination string, amount float64) (*WithdrawResponse, error)
-	UpdateLeverage(coin string, isCross bool, leverage int) (any, error)
+	UpdateLeverage(coin string, isCross bool, leverage int) (*DefaultExchangeResponse, error)
+	UpdateIsolatedMargin(coin string, amount float64) (*DefaultExchangeResponse, error)
+	TopUpIsolatedOnlyMargin(coin string, leverage float64) (*DefaultExchangeResponse, error)
+	ScheduleCancel(at time.Time) (*DefaultExchangeResponse, error)
+	ApproveBuilderFee(builder string, maxFeeRate string) (*DefaultExchangeResponse, error)
+	GetApprovedBuilderFee(builder string) (*int, error)
+
+	// Offline signing
+	BuildL1Envelope(action any) (*ActionEnvelope, error)
+	BuildOrderEnvelope(requests []OrderRequest, grouping Grouping, isSpot bool) (*ActionEnvelope, error)
+	BuildWithdrawEnvelope(destination string, amount float64) (*ActionEnvelope, error)
+	SignEnvelope(envelope *ActionEnvelope) (RsvSignature, error)
 }
 
+var _ IExchangeAPI = (*ExchangeAPI)(nil)
+
 // Implement the IExchangeAPI interface.
 type ExchangeAPI struct {
 	Client
@@ -35,7 +57,10 @@ type ExchangeAPI struct {
 	baseEndpoint string
 	meta         map[string]AssetInfo
 	spotMeta     map[string]AssetInfo
-	webSocketAPI *WebSocketAPI // WebSocket API for automatic fallback
+	webSocketAPI *WebSocketAPI              // WebSocket API for automatic fallback
+	builder      *BuilderInfo               // Default builder for orders without one
+	expiresAfter time.Duration              // Time to live of L1 actions, 0 for no expiry
+	fastSigner   atomic.Pointer[FastSigner] // Signer of L1 actions, see l1Signer
 }
 
 // NewExchangeAPI creates a new default ExchangeAPI.
@@ -58,13 +83,13 @@ func NewExchangeAPI(isMainnet bool, accountAddress string, privateKey string) *E
 	// turn on debug mode if there is an error with /info service
 	meta, err := api.infoAPI.BuildMetaMap()
 	if err != nil {
-		api.debug("Error building meta map: %s", err)
+		api.Logger().Warn("Failed to build meta map", "err", err)
 	}
 	api.meta = meta
 
 	spotMeta, err := api.infoAPI.BuildSpotMetaMap()
 	if err != nil {
-		api.debug("Error building spot meta map: %s", err)
+		api.Logger().Warn("Failed to build spot meta map", "err", err)
 	}
 	api.spotMeta = spotMeta
 
@@ -77,6 +102,12 @@ func (api *ExchangeAPI) SetWebSocketAPI(wsAPI *WebSocketAPI) {
 	api.Client.SetWebSocketAPI(wsAPI)
 }
 
+// SetBuilder sets the builder attached to orders that do not set one. nil removes it.
+// The builder fee must be approved by the user with ApproveBuilderFee first.
+func (api *ExchangeAPI) SetBuilder(builder *BuilderInfo) {
+	api.builder = builder
+}
+
 //
 // Helpers
 //
@@ -89,7 +120,7 @@ func (api *ExchangeAPI) Endpoint() string {
 func (api *ExchangeAPI) SlippagePrice(coin string, isBuy bool, slippage float64) float64 {
 	marketPx, err := api.infoAPI.GetMartketPx(coin)
 	if err != nil {
-		api.debug("Error getting market price: %s", err)
+		api.Logger().Debug("Failed to get market price", "coin", coin, "err", err)
 		return 0.0
 	}
 	return CalculateSlippage(isBuy, marketPx, slippage)
@@ -99,7 +130,7 @@ func (api *ExchangeAPI) SlippagePrice(coin string, isBuy bool, slippage float64)
 func (api *ExchangeAPI) SlippagePriceSpot(coin string, isBuy bool, slippage float64) float64 {
 	marketPx, err := api.infoAPI.GetSpotMarketPx(coin)
 	if err != nil {
-		api.debug("Error getting market price: %s", err)
+		api.Logger().Debug("Failed to get market price", "coin", coin, "err", err)
 		return 0.0
 	}
 	slippagePrice := CalculateSlippage(isBuy, marketPx, slippage)
@@ -114,17 +145,50 @@ func (api *ExchangeAPI) getChainParams() (string, string) {
 	return "0x66eee", "Testnet"
 }
 
-// Build bulk orders EIP712 message
-func (api *ExchangeAPI) BuildBulkOrdersEIP712(requests []OrderRequest, grouping Grouping) (apitypes.TypedData, error) {
+// buildOrderAction converts requests to an order action with the builder of the requests,
+// or the default builder if none of them sets one
+func (api *ExchangeAPI) buildOrderAction(requests []OrderRequest, grouping Grouping, isSpot bool) (PlaceOrderAction, error) {
 	var wires []OrderWire
+	var meta map[string]AssetInfo
+	if isSpot {
+		meta = api.spotMeta
+	} else {
+		meta = api.meta
+	}
+	var builder *BuilderInfo
 	for _, req := range requests {
-		wires = append(wires, OrderRequestToWire(req, api.meta, false))
+		wires = append(wires, OrderRequestToWire(req, meta, isSpot))
+		if req.Builder == nil {
+			continue
+		}
+		if builder != nil && *builder != *req.Builder {
+			return PlaceOrderAction{}, APIError{Message: "All orders of a bulk request must use the same builder"}
+		}
+		builder = req.Builder
+	}
+	if builder == nil {
+		builder = api.builder
 	}
-	timestamp := GetNonce()
 	action := OrderWiresToOrderAction(wires, grouping)
+	if builder != nil {
+		action.Builder = &BuilderInfo{
+			Builder: strings.ToLower(builder.Builder),
+			Fee:     builder.Fee,
+		}
+	}
+	return action, nil
+}
+
+// Build bulk orders EIP712 message
+func (api *ExchangeAPI) BuildBulkOrdersEIP712(requests []OrderRequest, grouping Grouping) (apitypes.TypedData, error) {
+	action, err := api.buildOrderAction(requests, grouping, false)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	timestamp := GetNonce()
 	srequest, err := api.BuildEIP712Message(action, timestamp)
 	if err != nil {
-		api.debug("Error building EIP712 message: %s", err)
+		api.Logger().Debug("Failed to build EIP-712 message", "err", err)
 		return apitypes.TypedData{}, err
 	}
 	return SignRequestToEIP712TypedData(srequest), nil
@@ -142,153 +206,203 @@ func (api *ExchangeAPI) BuildOrderEIP712(request OrderRequest, grouping Grouping
 // Place orders in bulk
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#place-an-order
 func (api *ExchangeAPI) BulkOrders(requests []OrderRequest, grouping Grouping, isSpot bool) (*OrderResponse, error) {
-	var wires []OrderWire
-	var meta map[string]AssetInfo
-	if isSpot {
-		meta = api.spotMeta
-	} else {
-		meta = api.meta
-	}
-	for _, req := range requests {
-		wires = append(wires, OrderRequestToWire(req, meta, isSpot))
+	return api.BulkOrdersContext(context.Background(), requests, grouping, isSpot)
+}
+
+// BulkOrdersContext places orders in bulk in a span that is a child of the span in ctx.
+// The orderUpdates and userFills events of orders with cloid are traced as children of this span.
+func (api *ExchangeAPI) BulkOrdersContext(ctx context.Context, requests []OrderRequest, grouping Grouping, isSpot bool) (response *OrderResponse, err error) {
+	tracing := api.Tracing()
+	ctx, span := tracing.start(ctx, "hyperliquid.BulkOrders", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { endSpan(span, err) }()
+	if span.IsRecording() {
+		cloids := make([]string, 0, len(requests))
+		for _, request := range requests {
+			if request.Cloid != "" {
+				cloids = append(cloids, request.Cloid)
+			}
+		}
+		span.SetAttributes(AttrOrders.Int(len(requests)), AttrCloids.StringSlice(cloids))
 	}
-	timestamp := GetNonce()
-	action := OrderWiresToOrderAction(wires, grouping)
-	v, r, s, err := api.SignL1Action(action, timestamp)
+
+	action, err := api.buildOrderAction(requests, grouping, isSpot)
 	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
 		return nil, err
 	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
+	tracing.trackOrders(ctx, requests)
+	response, err = postL1ActionContext[OrderResponse](ctx, api, action)
+	api.logOrderStatuses(requests, response, err)
+	traceOrderStatuses(span, requests, response)
+	// Failed and rejected orders get no stream events
+	for i := range requests {
+		if err != nil || i >= len(response.Response.Data.Statuses) || response.Response.Data.Statuses[i].Error != "" {
+			tracing.forgetOrder(requests[i].Cloid)
+		}
 	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return response, err
 }
 
 // Cancel order(s)
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s
 func (api *ExchangeAPI) BulkCancelOrders(cancels []CancelOidWire) (*OrderResponse, error) {
-	timestamp := GetNonce()
 	action := CancelOidOrderAction{
 		Type:    "cancel",
 		Cancels: cancels,
 	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
-	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
-		return nil, err
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
-	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	response, err := postL1Action[OrderResponse](api, action)
+	api.logCancelStatuses(len(cancels), func(i int) []any { return []any{"asset", cancels[i].Asset, "oid", cancels[i].Oid} }, response, err)
+	return response, err
 }
 
 // Bulk modify orders
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-multiple-orders
+// Orders are addressed by OrderId, or by OrderCloid if it is set.
 func (api *ExchangeAPI) BulkModifyOrders(modifyRequests []ModifyOrderRequest, isSpot bool) (*OrderResponse, error) {
 	wires := []ModifyOrderWire{}
-
+	meta := api.meta
+	if isSpot {
+		meta = api.spotMeta
+	}
 	for _, req := range modifyRequests {
-		wires = append(wires, ModifyOrderRequestToWire(req, api.meta, isSpot))
+		wires = append(wires, ModifyOrderRequestToWire(req, meta, isSpot))
 	}
 	action := ModifyOrderAction{
 		Type:     "batchModify",
 		Modifies: wires,
 	}
-
-	timestamp := GetNonce()
-	vVal, rVal, sVal, signErr := api.SignL1Action(action, timestamp)
-	if signErr != nil {
-		return nil, signErr
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(rVal, sVal, vVal),
-		VaultAddress: nil,
-	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return postL1Action[OrderResponse](api, action)
 }
 
 // Cancel exact order by Client Order Id
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s-by-cloid
 func (api *ExchangeAPI) CancelOrderByCloid(coin string, clientOID string) (*OrderResponse, error) {
-	timestamp := GetNonce()
+	return api.BulkCancelOrdersByCloid([]CancelCloidWire{{Asset: api.meta[coin].AssetId, Cloid: clientOID}})
+}
+
+// Cancel order(s) by Client Order Id. The cancels can target different assets.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s-by-cloid
+func (api *ExchangeAPI) BulkCancelOrdersByCloid(cancels []CancelCloidWire) (*OrderResponse, error) {
 	action := CancelCloidOrderAction{
-		Type: "cancelByCloid",
-		Cancels: []CancelCloidWire{
-			{
-				Asset: api.meta[coin].AssetId,
-				Cloid: clientOID,
-			},
-		},
-	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
-	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
-		return nil, err
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
+		Type:    "cancelByCloid",
+		Cancels: cancels,
 	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	response, err := postL1Action[OrderResponse](api, action)
+	api.logCancelStatuses(len(cancels), func(i int) []any { return []any{"asset", cancels[i].Asset, "cloid", cancels[i].Cloid} }, response, err)
+	return response, err
 }
 
 // Update leverage for a coin
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#update-leverage
 func (api *ExchangeAPI) UpdateLeverage(coin string, isCross bool, leverage int) (*DefaultExchangeResponse, error) {
-	timestamp := GetNonce()
 	action := UpdateLeverageAction{
 		Type:     "updateLeverage",
 		Asset:    api.meta[coin].AssetId,
 		IsCross:  isCross,
 		Leverage: leverage,
 	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
+	return postL1Action[DefaultExchangeResponse](api, action)
+}
+
+// Add or remove margin from an isolated position.
+// A positive amount (in USD) adds margin, a negative amount removes it.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#update-isolated-margin
+func (api *ExchangeAPI) UpdateIsolatedMargin(coin string, amount float64) (*DefaultExchangeResponse, error) {
+	action := UpdateIsolatedMarginAction{
+		Type:  "updateIsolatedMargin",
+		Asset: api.meta[coin].AssetId,
+		IsBuy: true,
+		Ntli:  FloatToUsdInt(amount),
+	}
+	return postL1Action[DefaultExchangeResponse](api, action)
+}
+
+// Top up the margin of an isolated-only position so that its leverage does not exceed leverage
+func (api *ExchangeAPI) TopUpIsolatedOnlyMargin(coin string, leverage float64) (*DefaultExchangeResponse, error) {
+	action := TopUpIsolatedOnlyMarginAction{
+		Type:     "topUpIsolatedOnlyMargin",
+		Asset:    api.meta[coin].AssetId,
+		Leverage: FloatToWire(leverage, 8, 0),
+	}
+	return postL1Action[DefaultExchangeResponse](api, action)
+}
+
+// Schedule a cancel of all open orders at the given time (dead man's switch).
+// The time must be at least 5 seconds in the future. A zero time removes the scheduled cancel.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#schedule-cancel-dead-mans-switch
+func (api *ExchangeAPI) ScheduleCancel(at time.Time) (*DefaultExchangeResponse, error) {
+	action := ScheduleCancelAction{
+		Type: "scheduleCancel",
+	}
+	if !at.IsZero() {
+		cancelTime := uint64(at.UnixMilli())
+		action.Time = &cancelTime
+	}
+	return postL1Action[DefaultExchangeResponse](api, action)
+}
+
+// Approve a maximum fee rate for a builder, e.g. "0.01%".
+// Builders can only charge fees on orders of users that approved them.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#approve-a-builder-fee
+func (api *ExchangeAPI) ApproveBuilderFee(builder string, maxFeeRate string) (*DefaultExchangeResponse, error) {
+	if !strings.HasSuffix(maxFeeRate, "%") {
+		return nil, APIError{Message: fmt.Sprintf("Max fee rate must be a percentage, got %s", maxFeeRate)}
+	}
+	nonce := GetNonce()
+	action := ApproveBuilderFeeAction{
+		Type:       "approveBuilderFee",
+		MaxFeeRate: maxFeeRate,
+		Builder:    strings.ToLower(builder),
+		Nonce:      nonce,
+	}
+	signatureChainID, chainType := api.getChainParams()
+	action.HyperliquidChain = chainType
+	action.SignatureChainID = signatureChainID
+	v, r, s, err := api.SignApproveBuilderFeeAction(action)
 	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
+		api.Logger().Debug("Failed to sign approve builder fee action", "err", err)
 		return nil, err
 	}
 	request := ExchangeRequest{
 		Action:       action,
-		Nonce:        timestamp,
+		Nonce:        nonce,
 		Signature:    ToTypedSig(r, s, v),
 		VaultAddress: nil,
 	}
 	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
 }
 
+// Get the maximum builder fee approved by the account for builder, in tenths of a basis point
+func (api *ExchangeAPI) GetApprovedBuilderFee(builder string) (*int, error) {
+	if api.infoAPI == nil {
+		return nil, APIError{Message: "Info API not set"}
+	}
+	return api.infoAPI.GetMaxBuilderFee(api.AccountAddress(), strings.ToLower(builder))
+}
+
+func (api *ExchangeAPI) newWithdrawAction(destination string, amount float64) WithdrawAction {
+	signatureChainID, chainType := api.getChainParams()
+	return WithdrawAction{
+		Type:             "withdraw3",
+		Destination:      destination,
+		Amount:           SizeToWire(amount, USDC_SZ_DECIMALS),
+		Time:             GetNonce(),
+		HyperliquidChain: chainType,
+		SignatureChainID: signatureChainID,
+	}
+}
+
 // Initiate a withdraw request
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#initiate-a-withdrawal-request
 func (api *ExchangeAPI) Withdraw(destination string, amount float64) (*WithdrawResponse, error) {
-	nonce := GetNonce()
-	action := WithdrawAction{
-		Type:        "withdraw3",
-		Destination: destination,
-		Amount:      SizeToWire(amount, USDC_SZ_DECIMALS),
-		Time:        nonce,
-	}
-	signatureChainID, chainType := api.getChainParams()
-	action.HyperliquidChain = chainType
-	action.SignatureChainID = signatureChainID
+	action := api.newWithdrawAction(destination, amount)
 	v, r, s, err := api.SignWithdrawAction(action)
 	if err != nil {
-		api.debug("Error signing withdraw action: %s", err)
+		api.Logger().Debug("Failed to sign withdraw action", "err", err)
 		return nil, err
 	}
 	request := &ExchangeRequest{
 		Action:       action,
-		Nonce:        nonce,
+		Nonce:        action.Time,
 		Signature:    ToTypedSig(r, s, v),
 		VaultAddress: nil,
 	}
@@ -304,6 +418,11 @@ func (api *ExchangeAPI) Order(request OrderRequest, grouping Grouping) (*OrderRe
 	return api.BulkOrders([]OrderRequest{request}, grouping, false)
 }
 
+// Place single order in a span that is a child of the span in ctx
+func (api *ExchangeAPI) OrderContext(ctx context.Context, request OrderRequest, grouping Grouping) (*OrderResponse, error) {
+	return api.BulkOrdersContext(ctx, []OrderRequest{request}, grouping, false)
+}
+
 // Open a market order.
 // Limit order with TIF=IOC and px=market price * (1 +- slippage).
 // Size determines the amount of the coin to buy/sell.
@@ -396,7 +515,7 @@ func (api *ExchangeAPI) ClosePosition(coin string) (*OrderResponse, error) {
 	// Then just make MarketOpen with the reverse size
 	state, err := api.infoAPI.GetUserState(api.AccountAddress())
 	if err != nil {
-		api.debug("Error GetUserState: %s", err)
+		api.Logger().Debug("Failed to get user state", "coin", coin, "err", err)
 		return nil, err
 	}
 	positions := state.AssetPositions
@@ -430,6 +549,44 @@ func (api *ExchangeAPI) ClosePosition(coin string) (*OrderResponse, error) {
 	return nil, APIError{Message: fmt.Sprintf("No position found for %s", coin)}
 }
 
+// Replace atomically reprices or resizes the resting order with the cloid of request.
+// The new order keeps the same cloid, so it can be replaced again with the same id.
+func (api *ExchangeAPI) Replace(request OrderRequest) (*OrderResponse, error) {
+	return api.BulkReplace([]OrderRequest{request}, false)
+}
+
+// BulkReplace replaces every order addressed by the cloid of its request in a single batch modify
+func (api *ExchangeAPI) BulkReplace(requests []OrderRequest, isSpot bool) (*OrderResponse, error) {
+	modifies, err := replaceRequests(requests)
+	if err != nil {
+		return nil, err
+	}
+	return api.BulkModifyOrders(modifies, isSpot)
+}
+
+// replaceRequests converts requests to modifies of the orders with their cloids
+func replaceRequests(requests []OrderRequest) ([]ModifyOrderRequest, error) {
+	modifies := make([]ModifyOrderRequest, 0, len(requests))
+	for _, req := range requests {
+		if req.Cloid == "" {
+			return nil, APIError{Message: fmt.Sprintf("Replace requires a cloid for %s order", req.Coin)}
+		}
+		modifies = append(modifies, ModifyOrderRequest{
+			OrderCloid:   req.Cloid,
+			Coin:         req.Coin,
+			IsBuy:        req.IsBuy,
+			Sz:           req.Sz,
+			LimitPx:      req.LimitPx,
+			OrderType:    req.OrderType,
+			ReduceOnly:   req.ReduceOnly,
+			Cloid:        req.Cloid,
+			ExactSz:      req.ExactSz,
+			ExactLimitPx: req.ExactLimitPx,
+		})
+	}
+	return modifies, nil
+}
+
 // OrderSpot places a spot order
 func (api *ExchangeAPI) OrderSpot(request OrderRequest, grouping Grouping) (*OrderResponse, error) {
 	return api.BulkOrders([]OrderRequest{request}, grouping, true)
@@ -444,7 +601,7 @@ func (api *ExchangeAPI) CancelOrderByOID(coin string, orderID int64) (*OrderResp
 func (api *ExchangeAPI) CancelAllOrdersByCoin(coin string) (*OrderResponse, error) {
 	orders, err := api.infoAPI.GetOpenOrders(api.AccountAddress())
 	if err != nil {
-		api.debug("Error getting orders: %s", err)
+		api.Logger().Debug("Failed to get open orders", "err", err)
 		return nil, err
 	}
 	var cancels []CancelOidWire
@@ -461,7 +618,7 @@ func (api *ExchangeAPI) CancelAllOrdersByCoin(coin string) (*OrderResponse, erro
 func (api *ExchangeAPI) CancelAllOrders() (*OrderResponse, error) {
 	orders, err := api.infoAPI.GetOpenOrders(api.AccountAddress())
 	if err != nil {
-		api.debug("Error getting orders: %s", err)
+		api.Logger().Debug("Failed to get open orders", "err", err)
 		return nil, err
 	}
 	if len(*orders) == 0 {
@@ -0,0 +1,184 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// TestReplaceByCloid tests modifying and canceling orders that are addressed by cloid
+func TestReplaceByCloid(t *testing.T) {
+	const cloid = "0x1234567890abcdef1234567890abcdef"
+	actions := make(chan map[string]interface{}, 10)
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		FastUnmarshal(message, &request)
+		if request.Method != "post" {
+			return
+		}
+		actions <- request.Request.Payload.(map[string]interface{})["action"].(map[string]interface{})
+		replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"order","data":{"statuses":[{"resting":{"oid":2,"cloid":"`+cloid+`"}}]}}}`)
+	})
+	api := newTestExchangeAPI(t, ws)
+	api.meta["ETH"] = AssetInfo{SzDecimals: 4, AssetId: 1}
+
+	if _, err := api.Replace(OrderRequest{Coin: "BTC", IsBuy: true, Sz: 0.01, LimitPx: 50000}); err == nil {
+		t.Error("Expected error for replace without cloid")
+	}
+	response, err := api.Replace(OrderRequest{
+		Coin:      "BTC",
+		IsBuy:     true,
+		Sz:        0.02,
+		LimitPx:   50100,
+		OrderType: OrderType{Limit: &LimitOrderType{Tif: TifAlo}},
+		Cloid:     cloid,
+	})
+	if err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if response.Response.Data.Statuses[0].Resting.Cloid != cloid {
+		t.Errorf("Unexpected replace response: %+v", response)
+	}
+	action := <-actions
+	modify := action["modifies"].([]interface{})[0].(map[string]interface{})
+	order := modify["order"].(map[string]interface{})
+	if action["type"] != "batchModify" || modify["oid"] != cloid || order["c"] != cloid || order["p"] != "50100" || order["s"] != "0.02" {
+		t.Errorf("Unexpected batch modify action: %v", action)
+	}
+
+	_, err = api.BulkCancelOrdersByCloid([]CancelCloidWire{{Asset: 0, Cloid: cloid}, {Asset: 1, Cloid: cloid}})
+	if err != nil {
+		t.Fatalf("BulkCancelOrdersByCloid failed: %v", err)
+	}
+	action = <-actions
+	if cancels := action["cancels"].([]interface{}); action["type"] != "cancelByCloid" || len(cancels) != 2 {
+		t.Errorf("Unexpected cancel action: %v", action)
+	}
+
+	// Numeric oids keep their compact msgpack encoding used for the action hash
+	encoded, err := msgpack.Marshal(ModifyOrderRequestToWire(ModifyOrderRequest{OrderId: 5, Coin: "BTC", Sz: 1, LimitPx: 1}, api.meta, false))
+	if err != nil {
+		t.Fatalf("Failed to encode modify wire: %v", err)
+	}
+	if !bytes.Contains(encoded, []byte("\xa3oid\x05")) {
+		t.Errorf("Expected compact oid encoding, got %x", encoded)
+	}
+}
+
+// TestOrderBuilder tests attaching builder codes to orders and approving builder fees
+func TestOrderBuilder(t *testing.T) {
+	const builder = "0xABCDEF0000000000000000000000000000000001"
+	payloads := make(chan map[string]interface{}, 10)
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		FastUnmarshal(message, &request)
+		if request.Method != "post" {
+			return
+		}
+		payloads <- request.Request.Payload.(map[string]interface{})
+		replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"default"}}`)
+	})
+	api := newTestExchangeAPI(t, ws)
+	order := OrderRequest{Coin: "BTC", IsBuy: true, Sz: 0.01, LimitPx: 50000, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}}
+
+	action, err := api.buildOrderAction([]OrderRequest{order}, GroupingNa, false)
+	if err != nil {
+		t.Fatalf("buildOrderAction failed: %v", err)
+	}
+	encoded, _ := msgpack.Marshal(action)
+	if action.Builder != nil || bytes.Contains(encoded, []byte("builder")) {
+		t.Errorf("Expected no builder in action hash, got %x", encoded)
+	}
+
+	api.SetBuilder(&BuilderInfo{Builder: builder, Fee: 10})
+	withBuilder := order
+	withBuilder.Builder = &BuilderInfo{Builder: builder, Fee: 5}
+	action, err = api.buildOrderAction([]OrderRequest{order, withBuilder}, GroupingNa, false)
+	if err != nil {
+		t.Fatalf("buildOrderAction failed: %v", err)
+	}
+	if action.Builder == nil || action.Builder.Builder != strings.ToLower(builder) || action.Builder.Fee != 5 {
+		t.Errorf("Expected builder of the request, got %+v", action.Builder)
+	}
+	encoded, _ = msgpack.Marshal(action)
+	if !bytes.HasSuffix(encoded, append([]byte("\xa7builder\x82\xa1b\xd9\x2a"), []byte(strings.ToLower(builder)+"\xa1f\x05")...)) {
+		t.Errorf("Expected builder at the end of the action hash, got %x", encoded)
+	}
+	other := withBuilder
+	other.Builder = &BuilderInfo{Builder: builder, Fee: 1}
+	if _, err := api.BulkOrders([]OrderRequest{withBuilder, other}, GroupingNa, false); err == nil {
+		t.Error("Expected error for orders with different builders")
+	}
+
+	var apiErr APIError
+	if _, err := api.GetApprovedBuilderFee(builder); !errors.As(err, &apiErr) {
+		t.Errorf("Expected APIError without info API, got %v", err)
+	}
+
+	if _, err := api.ApproveBuilderFee(builder, "0.01"); err == nil {
+		t.Error("Expected error for fee rate without percent sign")
+	}
+	if _, err := api.ApproveBuilderFee(builder, "0.01%"); err != nil {
+		t.Fatalf("ApproveBuilderFee failed: %v", err)
+	}
+	payload := <-payloads
+	approval := payload["action"].(map[string]interface{})
+	if approval["type"] != "approveBuilderFee" || approval["builder"] != strings.ToLower(builder) || approval["maxFeeRate"] != "0.01%" ||
+		approval["hyperliquidChain"] != "Mainnet" || approval["nonce"] != payload["nonce"] {
+		t.Errorf("Unexpected approve builder fee action: %v", approval)
+	}
+}
+
+// TestExpiresAfter tests that the action expiry is part of the signed hash and the request
+func TestExpiresAfter(t *testing.T) {
+	action := UpdateLeverageAction{Type: "updateLeverage", Asset: 0, IsCross: true, Leverage: 10}
+	encoded, _ := msgpack.Marshal(action)
+	expected := append(encoded, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2)
+	hash, err := buildActionHash(action, "", 1, 2)
+	if err != nil {
+		t.Fatalf("buildActionHash failed: %v", err)
+	}
+	if hash != crypto.Keccak256Hash(expected) {
+		t.Errorf("Unexpected action hash with expiry: %s", hash.Hex())
+	}
+	hash, _ = buildActionHash(action, "", 1, 0)
+	if hash != crypto.Keccak256Hash(expected[:len(expected)-9]) {
+		t.Errorf("Unexpected action hash without expiry: %s", hash.Hex())
+	}
+
+	payloads := make(chan map[string]interface{}, 10)
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		FastUnmarshal(message, &request)
+		if request.Method != "post" {
+			return
+		}
+		payloads <- request.Request.Payload.(map[string]interface{})
+		replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"default"}}`)
+	})
+	api := newTestExchangeAPI(t, ws)
+
+	if _, err := api.UpdateLeverage("BTC", true, 10); err != nil {
+		t.Fatalf("UpdateLeverage failed: %v", err)
+	}
+	if payload := <-payloads; payload["expiresAfter"] != nil {
+		t.Errorf("Expected no expiry by default, got %v", payload["expiresAfter"])
+	}
+
+	api.SetExpiresAfter(time.Minute)
+	before := time.Now()
+	if _, err := api.UpdateLeverage("BTC", true, 10); err != nil {
+		t.Fatalf("UpdateLeverage failed: %v", err)
+	}
+	payload := <-payloads
+	expiresAfter, _ := payload["expiresAfter"].(float64)
+	if int64(expiresAfter) < before.Add(time.Minute).UnixMilli() || int64(expiresAfter) > time.Now().Add(time.Minute).UnixMilli() {
+		t.Errorf("Expected expiry one minute ahead, got %v", payload["expiresAfter"])
+	}
+}
@@ -1,54 +1,85 @@
 package hyperliquid
 
 import (
+	"context"
+	"time"
+
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func (api *ExchangeAPI) Sign(request *SignRequest) (byte, [32]byte, [32]byte, error) {
+	kind := SigningUser
+	if request.DomainName == "Exchange" {
+		kind = SigningL1
+	}
+	start := time.Now()
 	signer := NewSigner(api.keyManager)
 	v, r, s, err := signer.Sign(request)
+	api.Metrics().ObserveSigning(kind, time.Since(start))
 	if err != nil {
-		api.debug("Error SignInner: %s", err)
+		api.Logger().Debug("Failed to sign action", "primaryType", request.PrimaryType, "err", err)
 		return 0, [32]byte{}, [32]byte{}, err
 	}
 	return v, r, s, nil
 }
 
 func (api *ExchangeAPI) SignUserSignableAction(action any, payloadTypes []apitypes.Type, primaryType string) (byte, [32]byte, [32]byte, error) {
-	message, err := StructToMap(action)
+	signRequest, err := api.buildUserSignRequest(action, payloadTypes, primaryType)
 	if err != nil {
 		return 0, [32]byte{}, [32]byte{}, err
 	}
+	return api.Sign(signRequest)
+}
+
+func (api *ExchangeAPI) buildUserSignRequest(action any, payloadTypes []apitypes.Type, primaryType string) (*SignRequest, error) {
+	message, err := StructToMap(action)
+	if err != nil {
+		return nil, err
+	}
 	// Remove unnecessary fields for signing
 	delete(message, "type")
 	delete(message, "signatureChainId")
 
-	signRequest := &SignRequest{
+	return &SignRequest{
 		DomainName:  "HyperliquidSignTransaction",
 		PrimaryType: primaryType,
 		DType:       payloadTypes,
 		DTypeMsg:    message,
 		IsMainNet:   api.IsMainnet(),
-	}
-	return api.Sign(signRequest)
+	}, nil
 }
 
 func (api *ExchangeAPI) SignL1Action(action any, timestamp uint64) (byte, [32]byte, [32]byte, error) {
-	srequest, err := api.BuildEIP712Message(action, timestamp)
+	return api.SignL1ActionWithExpiry(action, timestamp, 0)
+}
+
+// SignL1ActionWithExpiry signs an L1 action that is rejected by the exchange after expiresAfter
+// (in milliseconds). Zero signs the action without expiry. The same expiresAfter must be sent
+// in the request.
+func (api *ExchangeAPI) SignL1ActionWithExpiry(action any, timestamp uint64, expiresAfter uint64) (byte, [32]byte, [32]byte, error) {
+	srequest, err := api.buildL1SignRequest(action, timestamp, expiresAfter)
 	if err != nil {
-		api.debug("Error building EIP712 message: %s", err)
 		return 0, [32]byte{}, [32]byte{}, err
 	}
 	return api.Sign(srequest)
 }
 
 func (api *ExchangeAPI) BuildEIP712Message(action any, timestamp uint64) (*SignRequest, error) {
-	hash, err := buildActionHash(action, "", timestamp)
+	return api.buildL1SignRequest(action, timestamp, 0)
+}
+
+func (api *ExchangeAPI) buildL1SignRequest(action any, timestamp uint64, expiresAfter uint64) (*SignRequest, error) {
+	hash, err := buildActionHash(action, "", timestamp, expiresAfter)
 	if err != nil {
 		return nil, err
 	}
-	message := buildMessage(hash.Bytes(), api.IsMainnet())
-	srequest := &SignRequest{
+	return newL1SignRequest(hash.Bytes(), api.IsMainnet()), nil
+}
+
+// newL1SignRequest returns the EIP-712 request of an L1 action hash
+func newL1SignRequest(hash []byte, isMainnet bool) *SignRequest {
+	return &SignRequest{
 		DomainName:  "Exchange",
 		PrimaryType: "Agent",
 		DType: []apitypes.Type{
@@ -61,14 +92,73 @@ func (api *ExchangeAPI) BuildEIP712Message(action any, timestamp uint64) (*SignR
 				Type: "bytes32",
 			},
 		},
-		DTypeMsg:  message,
-		IsMainNet: api.IsMainnet(),
+		DTypeMsg:  buildMessage(hash, isMainnet),
+		IsMainNet: isMainnet,
 	}
-	return srequest, nil
 }
 
-func (api *ExchangeAPI) SignWithdrawAction(action WithdrawAction) (byte, [32]byte, [32]byte, error) {
-	types := []apitypes.Type{
+// SetExpiresAfter sets the time to live of L1 actions. Actions that reach the exchange later
+// than ttl after they were signed are rejected instead of executed, so orders delayed by network
+// issues never fill at stale prices. Zero disables expiry.
+func (api *ExchangeAPI) SetExpiresAfter(ttl time.Duration) {
+	api.expiresAfter = ttl
+}
+
+// nextExpiry returns the expiry of an action signed now, 0 if expiry is disabled
+func (api *ExchangeAPI) nextExpiry() uint64 {
+	if api.expiresAfter <= 0 {
+		return 0
+	}
+	return uint64(time.Now().Add(api.expiresAfter).UnixMilli())
+}
+
+// newL1Request signs action with a fresh nonce and the configured expiry,
+// in a span that is a child of the span in ctx
+func (api *ExchangeAPI) newL1Request(ctx context.Context, action any) (*ExchangeRequest, error) {
+	timestamp := GetNonce()
+	request := &ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		VaultAddress: nil,
+	}
+	expiresAfter := api.nextExpiry()
+	if expiresAfter > 0 {
+		request.ExpiresAfter = &expiresAfter
+	}
+	signer, err := api.l1Signer()
+	if err != nil {
+		return nil, err
+	}
+	_, span := api.Tracing().start(ctx, "hyperliquid.sign", trace.WithAttributes(AttrSigning.String(SigningL1)))
+	start := time.Now()
+	v, r, s, err := signer.SignL1Action(action, timestamp, "", expiresAfter)
+	api.Metrics().ObserveSigning(SigningL1, time.Since(start))
+	endSpan(span, err)
+	if err != nil {
+		api.Logger().Debug("Failed to sign L1 action", "err", err)
+		return nil, err
+	}
+	request.Signature = ToTypedSig(r, s, v)
+	return request, nil
+}
+
+// postL1Action signs and sends an L1 action
+func postL1Action[T any](api *ExchangeAPI, action any) (*T, error) {
+	return postL1ActionContext[T](context.Background(), api, action)
+}
+
+// postL1ActionContext signs and sends an L1 action with spans that are children of the span in ctx
+func postL1ActionContext[T any](ctx context.Context, api *ExchangeAPI, action any) (*T, error) {
+	request, err := api.newL1Request(ctx, action)
+	if err != nil {
+		return nil, err
+	}
+	return makeUniversalRequestContext[T](ctx, api, *request)
+}
+
+// EIP-712 types of user signable actions
+var (
+	withdrawSignTypes = []apitypes.Type{
 		{
 			Name: "hyperliquidChain",
 			Type: "string",
@@ -86,5 +176,35 @@ func (api *ExchangeAPI) SignWithdrawAction(action WithdrawAction) (byte, [32]byt
 			Type: "uint64",
 		},
 	}
-	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:Withdraw")
+	approveBuilderFeeSignTypes = []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "maxFeeRate",
+			Type: "string",
+		},
+		{
+			Name: "builder",
+			Type: "address",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+)
+
+const (
+	withdrawPrimaryType          = "HyperliquidTransaction:Withdraw"
+	approveBuilderFeePrimaryType = "HyperliquidTransaction:ApproveBuilderFee"
+)
+
+func (api *ExchangeAPI) SignWithdrawAction(action WithdrawAction) (byte, [32]byte, [32]byte, error) {
+	return api.SignUserSignableAction(action, withdrawSignTypes, withdrawPrimaryType)
+}
+
+func (api *ExchangeAPI) SignApproveBuilderFeeAction(action ApproveBuilderFeeAction) (byte, [32]byte, [32]byte, error) {
+	return api.SignUserSignableAction(action, approveBuilderFeeSignTypes, approveBuilderFeePrimaryType)
 }
@@ -16,6 +16,7 @@ type ExchangeRequest struct {
 	Nonce        uint64       `json:"nonce"`
 	Signature    RsvSignature `json:"signature"`
 	VaultAddress *string      `json:"vaultAddress,omitempty" msgpack:",omitempty"`
+	ExpiresAfter *uint64      `json:"expiresAfter,omitempty" msgpack:",omitempty"`
 }
 
 type AssetInfo struct {
@@ -33,6 +34,18 @@ type OrderRequest struct {
 	OrderType  OrderType `json:"order_type"`
 	ReduceOnly bool      `json:"reduce_only"`
 	Cloid      string    `json:"cloid,omitempty"`
+	// Builder receiving a fee for the order. All orders of a bulk request must use the same builder.
+	Builder *BuilderInfo `json:"builder,omitempty"`
+	// Exact size and limit price, used instead of Sz and LimitPx when set
+	ExactSz      *Decimal `json:"exact_sz,omitempty"`
+	ExactLimitPx *Decimal `json:"exact_limit_px,omitempty"`
+}
+
+// BuilderInfo attaches a builder code to orders.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/trading/builder-codes
+type BuilderInfo struct {
+	Builder string `msgpack:"b" json:"b"` // Builder address
+	Fee     int    `msgpack:"f" json:"f"` // Fee in tenths of a basis point, e.g. 10 is 1bp
 }
 
 type OrderType struct {
@@ -85,7 +98,7 @@ type ModifyResponse struct {
 	Response OrderInnerResponse `json:"response"`
 }
 type ModifyOrderWire struct {
-	OrderId int       `msgpack:"oid" json:"oid"`
+	OrderId any       `msgpack:"oid" json:"oid"` // Oid (int) or cloid (string) of the modified order
 	Order   OrderWire `msgpack:"order" json:"order"`
 }
 type ModifyOrderAction struct {
@@ -95,6 +108,7 @@ type ModifyOrderAction struct {
 
 type ModifyOrderRequest struct {
 	OrderId    int       `json:"oid"`
+	OrderCloid string    `json:"order_cloid,omitempty"` // Addresses the order by cloid instead of OrderId
 	Coin       string    `json:"coin"`
 	IsBuy      bool      `json:"is_buy"`
 	Sz         float64   `json:"sz"`
@@ -102,6 +116,9 @@ type ModifyOrderRequest struct {
 	OrderType  OrderType `json:"order_type"`
 	ReduceOnly bool      `json:"reduce_only"`
 	Cloid      string    `json:"cloid,omitempty"`
+	// Exact size and limit price, used instead of Sz and LimitPx when set
+	ExactSz      *Decimal `json:"exact_sz,omitempty"`
+	ExactLimitPx *Decimal `json:"exact_limit_px,omitempty"`
 }
 
 type OrderTypeWire struct {
@@ -110,9 +127,10 @@ type OrderTypeWire struct {
 }
 
 type PlaceOrderAction struct {
-	Type     string      `msgpack:"type" json:"type"`
-	Orders   []OrderWire `msgpack:"orders" json:"orders"`
-	Grouping Grouping    `msgpack:"grouping" json:"grouping"`
+	Type     string       `msgpack:"type" json:"type"`
+	Orders   []OrderWire  `msgpack:"orders" json:"orders"`
+	Grouping Grouping     `msgpack:"grouping" json:"grouping"`
+	Builder  *BuilderInfo `msgpack:"builder,omitempty" json:"builder,omitempty"`
 }
 
 type OrderResponse struct {
@@ -216,6 +234,26 @@ type UpdateLeverageAction struct {
 	Leverage int    `msgpack:"leverage" json:"leverage"`
 }
 
+// Ntli is the margin delta in micro USD (USD * 1e6), negative to remove margin
+type UpdateIsolatedMarginAction struct {
+	Type  string `msgpack:"type" json:"type"`
+	Asset int    `msgpack:"asset" json:"asset"`
+	IsBuy bool   `msgpack:"isBuy" json:"isBuy"`
+	Ntli  int    `msgpack:"ntli" json:"ntli"`
+}
+
+type TopUpIsolatedOnlyMarginAction struct {
+	Type     string `msgpack:"type" json:"type"`
+	Asset    int    `msgpack:"asset" json:"asset"`
+	Leverage string `msgpack:"leverage" json:"leverage"`
+}
+
+// Time is omitted to remove a scheduled cancel
+type ScheduleCancelAction struct {
+	Type string  `msgpack:"type" json:"type"`
+	Time *uint64 `msgpack:"time,omitempty" json:"time,omitempty"`
+}
+
 type DefaultExchangeResponse struct {
 	Status   string `json:"status"`
 	Response struct {
@@ -225,13 +263,17 @@ type DefaultExchangeResponse struct {
 
 // Depending on Type this struct can has different non-nil fields
 type NonFundingDelta struct {
-	Type   string  `json:"type"`
-	Usdc   float64 `json:"usdc,string,omitempty"`
-	Amount float64 `json:"amount,string,omitempty"`
-	ToPerp bool    `json:"toPerp,omitempty"`
-	Token  string  `json:"token,omitempty"`
-	Fee    float64 `json:"fee,string,omitempty"`
-	Nonce  int64   `json:"nonce"`
+	Type            string  `json:"type"`
+	Usdc            float64 `json:"usdc,string,omitempty"`
+	Amount          float64 `json:"amount,string,omitempty"`
+	ToPerp          bool    `json:"toPerp,omitempty"`
+	Token           string  `json:"token,omitempty"`
+	Fee             float64 `json:"fee,string,omitempty"`
+	Nonce           int64   `json:"nonce"`
+	User            string  `json:"user,omitempty"`        // Sender of transfers
+	Destination     string  `json:"destination,omitempty"` // Receiver of transfers
+	Vault           string  `json:"vault,omitempty"`
+	NetWithdrawnUsd float64 `json:"netWithdrawnUsd,string,omitempty"` // For vaultWithdraw
 }
 
 type FundingDelta struct {
@@ -264,6 +306,15 @@ type WithdrawAction struct {
 	SignatureChainID string `msgpack:"signatureChainId" json:"signatureChainId"`
 }
 
+type ApproveBuilderFeeAction struct {
+	Type             string `msgpack:"type" json:"type"`
+	HyperliquidChain string `msgpack:"hyperliquidChain" json:"hyperliquidChain"`
+	SignatureChainID string `msgpack:"signatureChainId" json:"signatureChainId"`
+	MaxFeeRate       string `msgpack:"maxFeeRate" json:"maxFeeRate"` // Percentage, e.g. "0.001%"
+	Builder          string `msgpack:"builder" json:"builder"`
+	Nonce            uint64 `msgpack:"nonce" json:"nonce"`
+}
+
 type WithdrawResponse struct {
 	Status string `json:"status"`
 	Nonce  int64
@@ -0,0 +1,383 @@
+package hyperliquid
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// agentTypeHash is the EIP-712 type hash of the Agent struct signed for L1 actions
+var agentTypeHash = crypto.Keccak256Hash([]byte("Agent(string source,bytes32 connectionId)"))
+
+// FastSigner signs L1 actions without building EIP-712 typed data. The domain separator
+// and the Agent struct hashes are computed once per network, orders, cancels and modifies
+// are msgpack encoded by hand into reused buffers. Signatures are identical to
+// ExchangeAPI.SignL1Action.
+//
+// A FastSigner is safe for concurrent use.
+type FastSigner struct {
+	key             *ecdsa.PrivateKey
+	manager         *PKeyManager
+	isMainnet       bool
+	domainSeparator common.Hash
+	sourceHash      common.Hash
+	states          sync.Pool
+}
+
+// fastSignerState is the scratch space of one signature
+type fastSignerState struct {
+	buf    []byte
+	hasher crypto.KeccakState
+	digest [96]byte
+}
+
+// NewFastSigner creates a signer of L1 actions for mainnet or testnet
+func NewFastSigner(manager *PKeyManager, isMainnet bool) (*FastSigner, error) {
+	if manager == nil || manager.PrivateECDSA() == nil {
+		return nil, APIError{Message: "API key not set"}
+	}
+	request := newL1SignRequest(make([]byte, 32), isMainnet)
+	domain := SignRequestToEIP712TypedData(request)
+	separator, err := domain.HashStruct("EIP712Domain", domain.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+	signer := &FastSigner{
+		key:             manager.PrivateECDSA(),
+		manager:         manager,
+		isMainnet:       isMainnet,
+		domainSeparator: common.BytesToHash(separator),
+		sourceHash:      crypto.Keccak256Hash([]byte(getNetSource(isMainnet))),
+	}
+	signer.states.New = func() any {
+		return &fastSignerState{buf: make([]byte, 0, 512), hasher: crypto.NewKeccakState()}
+	}
+	return signer, nil
+}
+
+// SignL1Action signs action with its nonce, vault address and expiry, see SignL1ActionWithExpiry.
+// An empty vaultAddress signs for the account itself, an expiresAfter of zero signs without expiry.
+func (signer *FastSigner) SignL1Action(action any, nonce uint64, vaultAddress string, expiresAfter uint64) (byte, [32]byte, [32]byte, error) {
+	state := signer.states.Get().(*fastSignerState)
+	defer signer.states.Put(state)
+	digest, err := signer.digest(state, action, vaultAddress, nonce, expiresAfter)
+	if err != nil {
+		return 0, [32]byte{}, [32]byte{}, err
+	}
+	signature, err := crypto.Sign(digest[:], signer.key)
+	if err != nil {
+		return 0, [32]byte{}, [32]byte{}, err
+	}
+	return SignatureToVRS(signature)
+}
+
+// ActionHash returns the connection id of action, the hash signed as the Agent message
+func (signer *FastSigner) ActionHash(action any, vaultAddress string, nonce uint64, expiresAfter uint64) (common.Hash, error) {
+	state := signer.states.Get().(*fastSignerState)
+	defer signer.states.Put(state)
+	if err := state.encode(action, vaultAddress, nonce, expiresAfter); err != nil {
+		return common.Hash{}, err
+	}
+	return state.sum(state.buf), nil
+}
+
+// digest returns the EIP-712 hash of the Agent message of action
+func (signer *FastSigner) digest(state *fastSignerState, action any, vaultAddress string, nonce uint64, expiresAfter uint64) (common.Hash, error) {
+	if err := state.encode(action, vaultAddress, nonce, expiresAfter); err != nil {
+		return common.Hash{}, err
+	}
+	connectionId := state.sum(state.buf)
+
+	// hashStruct(Agent) = keccak256(typeHash ‖ keccak256(source) ‖ connectionId)
+	message := state.digest[:0]
+	message = append(message, agentTypeHash[:]...)
+	message = append(message, signer.sourceHash[:]...)
+	structHash := state.sum(append(message, connectionId[:]...))
+
+	message = append(state.digest[:0], 0x19, 0x01)
+	message = append(message, signer.domainSeparator[:]...)
+	return state.sum(append(message, structHash[:]...)), nil
+}
+
+func (state *fastSignerState) sum(data []byte) common.Hash {
+	var hash common.Hash
+	state.hasher.Reset()
+	state.hasher.Write(data)
+	state.hasher.Read(hash[:])
+	return hash
+}
+
+// encode writes the hashed bytes of an L1 action to the buffer, like buildActionHash
+func (state *fastSignerState) encode(action any, vaultAddress string, nonce uint64, expiresAfter uint64) error {
+	buf, err := appendActionMsgpack(state.buf[:0], action)
+	if err != nil {
+		return err
+	}
+	buf = binary.BigEndian.AppendUint64(buf, nonce)
+	if vaultAddress == "" {
+		buf = append(buf, 0x00)
+	} else {
+		buf = append(buf, 0x01)
+		buf = append(buf, HexToBytes(vaultAddress)...)
+	}
+	if expiresAfter > 0 {
+		buf = append(buf, 0x00)
+		buf = binary.BigEndian.AppendUint64(buf, expiresAfter)
+	}
+	state.buf = buf
+	return nil
+}
+
+// appendActionMsgpack appends the msgpack encoding of action to buf. Orders, cancels and
+// modifies are encoded by hand, other actions fall back to msgpack.Marshal.
+// The output is identical to msgpack.Marshal.
+func appendActionMsgpack(buf []byte, action any) ([]byte, error) {
+	switch action := action.(type) {
+	case PlaceOrderAction:
+		return appendPlaceOrderAction(buf, &action), nil
+	case *PlaceOrderAction:
+		return appendPlaceOrderAction(buf, action), nil
+	case CancelOidOrderAction:
+		return appendCancelOidOrderAction(buf, &action), nil
+	case *CancelOidOrderAction:
+		return appendCancelOidOrderAction(buf, action), nil
+	case CancelCloidOrderAction:
+		return appendCancelCloidOrderAction(buf, &action), nil
+	case *CancelCloidOrderAction:
+		return appendCancelCloidOrderAction(buf, action), nil
+	case ModifyOrderAction:
+		if encoded, ok := appendModifyOrderAction(buf, &action); ok {
+			return encoded, nil
+		}
+	case *ModifyOrderAction:
+		if encoded, ok := appendModifyOrderAction(buf, action); ok {
+			return encoded, nil
+		}
+	}
+	data, err := msgpack.Marshal(action)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, data...), nil
+}
+
+func appendPlaceOrderAction(buf []byte, action *PlaceOrderAction) []byte {
+	if action.Builder != nil {
+		buf = appendMapLen(buf, 4)
+	} else {
+		buf = appendMapLen(buf, 3)
+	}
+	buf = appendString(appendString(buf, "type"), action.Type)
+	buf = appendString(buf, "orders")
+	if action.Orders == nil {
+		buf = append(buf, 0xc0)
+	} else {
+		buf = appendArrayLen(buf, len(action.Orders))
+		for i := range action.Orders {
+			buf = appendOrderWire(buf, &action.Orders[i])
+		}
+	}
+	buf = appendString(appendString(buf, "grouping"), string(action.Grouping))
+	if action.Builder != nil {
+		buf = appendString(buf, "builder")
+		buf = appendMapLen(buf, 2)
+		buf = appendString(appendString(buf, "b"), action.Builder.Builder)
+		buf = appendInt(appendString(buf, "f"), int64(action.Builder.Fee))
+	}
+	return buf
+}
+
+func appendOrderWire(buf []byte, order *OrderWire) []byte {
+	if order.Cloid != "" {
+		buf = appendMapLen(buf, 7)
+	} else {
+		buf = appendMapLen(buf, 6)
+	}
+	buf = appendInt(appendString(buf, "a"), int64(order.Asset))
+	buf = appendBool(appendString(buf, "b"), order.IsBuy)
+	buf = appendString(appendString(buf, "p"), order.LimitPx)
+	buf = appendString(appendString(buf, "s"), order.SizePx)
+	buf = appendBool(appendString(buf, "r"), order.ReduceOnly)
+	buf = appendString(buf, "t")
+	fields := 0
+	if order.OrderType.Limit != nil {
+		fields++
+	}
+	if order.OrderType.Trigger != nil {
+		fields++
+	}
+	buf = appendMapLen(buf, fields)
+	if limit := order.OrderType.Limit; limit != nil {
+		buf = appendString(buf, "limit")
+		buf = appendMapLen(buf, 1)
+		buf = appendString(appendString(buf, "tif"), limit.Tif)
+	}
+	if trigger := order.OrderType.Trigger; trigger != nil {
+		buf = appendString(buf, "trigger")
+		buf = appendMapLen(buf, 3)
+		buf = appendBool(appendString(buf, "isMarket"), trigger.IsMarket)
+		buf = appendString(appendString(buf, "triggerPx"), trigger.TriggerPx)
+		buf = appendString(appendString(buf, "tpsl"), string(trigger.TpSl))
+	}
+	if order.Cloid != "" {
+		buf = appendString(appendString(buf, "c"), order.Cloid)
+	}
+	return buf
+}
+
+func appendCancelOidOrderAction(buf []byte, action *CancelOidOrderAction) []byte {
+	buf = appendMapLen(buf, 2)
+	buf = appendString(appendString(buf, "type"), action.Type)
+	buf = appendString(buf, "cancels")
+	if action.Cancels == nil {
+		return append(buf, 0xc0)
+	}
+	buf = appendArrayLen(buf, len(action.Cancels))
+	for _, cancel := range action.Cancels {
+		buf = appendMapLen(buf, 2)
+		buf = appendInt(appendString(buf, "a"), int64(cancel.Asset))
+		buf = appendInt(appendString(buf, "o"), int64(cancel.Oid))
+	}
+	return buf
+}
+
+func appendCancelCloidOrderAction(buf []byte, action *CancelCloidOrderAction) []byte {
+	buf = appendMapLen(buf, 2)
+	buf = appendString(appendString(buf, "type"), action.Type)
+	buf = appendString(buf, "cancels")
+	if action.Cancels == nil {
+		return append(buf, 0xc0)
+	}
+	buf = appendArrayLen(buf, len(action.Cancels))
+	for _, cancel := range action.Cancels {
+		buf = appendMapLen(buf, 2)
+		buf = appendInt(appendString(buf, "asset"), int64(cancel.Asset))
+		buf = appendString(appendString(buf, "cloid"), cancel.Cloid)
+	}
+	return buf
+}
+
+// appendModifyOrderAction encodes modifies of orders addressed by oid (int) or cloid (string).
+// It returns false for other order ids.
+func appendModifyOrderAction(buf []byte, action *ModifyOrderAction) ([]byte, bool) {
+	for _, modify := range action.Modifies {
+		switch modify.OrderId.(type) {
+		case int, string:
+		default:
+			return buf, false
+		}
+	}
+	buf = appendMapLen(buf, 2)
+	buf = appendString(appendString(buf, "type"), action.Type)
+	buf = appendString(buf, "modifies")
+	if action.Modifies == nil {
+		return append(buf, 0xc0), true
+	}
+	buf = appendArrayLen(buf, len(action.Modifies))
+	for i := range action.Modifies {
+		modify := &action.Modifies[i]
+		buf = appendMapLen(buf, 2)
+		buf = appendString(buf, "oid")
+		switch oid := modify.OrderId.(type) {
+		case int:
+			buf = appendInt(buf, int64(oid))
+		case string:
+			buf = appendString(buf, oid)
+		}
+		buf = appendOrderWire(appendString(buf, "order"), &modify.Order)
+	}
+	return buf, true
+}
+
+// Compact msgpack encoding, the same as msgpack.Encoder
+
+func appendMapLen(buf []byte, n int) []byte {
+	if n < 16 {
+		return append(buf, 0x80|byte(n))
+	}
+	if n <= math.MaxUint16 {
+		return binary.BigEndian.AppendUint16(append(buf, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(buf, 0xdf), uint32(n))
+}
+
+func appendArrayLen(buf []byte, n int) []byte {
+	if n < 16 {
+		return append(buf, 0x90|byte(n))
+	}
+	if n <= math.MaxUint16 {
+		return binary.BigEndian.AppendUint16(append(buf, 0xdc), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(buf, 0xdd), uint32(n))
+}
+
+func appendString(buf []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n < 256:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+func appendBool(buf []byte, b bool) []byte {
+	if b {
+		return append(buf, 0xc3)
+	}
+	return append(buf, 0xc2)
+}
+
+func appendInt(buf []byte, n int64) []byte {
+	if n >= 0 {
+		return appendUint(buf, uint64(n))
+	}
+	switch {
+	case n >= -32:
+		return append(buf, byte(n))
+	case n >= math.MinInt8:
+		return append(buf, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(n))
+}
+
+func appendUint(buf []byte, n uint64) []byte {
+	switch {
+	case n <= math.MaxInt8:
+		return append(buf, byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(buf, 0xcf), n)
+}
+
+// l1Signer returns the fast signer of the API key and network, creating it on first use
+func (api *ExchangeAPI) l1Signer() (*FastSigner, error) {
+	signer := api.fastSigner.Load()
+	if signer != nil && signer.manager == api.keyManager && signer.isMainnet == api.IsMainnet() {
+		return signer, nil
+	}
+	signer, err := NewFastSigner(api.keyManager, api.IsMainnet())
+	if err != nil {
+		return nil, err
+	}
+	api.fastSigner.Store(signer)
+	return signer, nil
+}
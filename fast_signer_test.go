@@ -0,0 +1,159 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func testFastSignerActions() []any {
+	limit := OrderTypeWire{Limit: &LimitOrderType{Tif: TifAlo}}
+	trigger := OrderTypeWire{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: "120000", TpSl: TriggerSl}}
+	orders := make([]OrderWire, 20)
+	for i := range orders {
+		orders[i] = OrderWire{Asset: i * 1000, IsBuy: i%2 == 0, LimitPx: "50000.5", SizePx: "0.001", OrderType: limit}
+	}
+	return []any{
+		PlaceOrderAction{Type: "order", Orders: orders[:1], Grouping: GroupingNa},
+		&PlaceOrderAction{Type: "order", Orders: orders, Grouping: GroupingNa},
+		PlaceOrderAction{Type: "order", Orders: []OrderWire{
+			{Asset: 10107, LimitPx: "1", SizePx: "1", ReduceOnly: true, OrderType: trigger, Cloid: GetRandomCloid()},
+			{Asset: 70000, OrderType: OrderTypeWire{}},
+		}, Grouping: GroupingTpSl, Builder: &BuilderInfo{Builder: "0x0000000000000000000000000000000000000002", Fee: 300}},
+		PlaceOrderAction{Type: "order", Grouping: GroupingNa},
+		PlaceOrderAction{Type: "order", Orders: []OrderWire{{LimitPx: strings.Repeat("1", 40), SizePx: strings.Repeat("2", 300)}}},
+		CancelOidOrderAction{Type: "cancel", Cancels: []CancelOidWire{{Asset: 0, Oid: 1}, {Asset: 200, Oid: 91490942000}, {Asset: -1, Oid: -40000}}},
+		&CancelCloidOrderAction{Type: "cancelByCloid", Cancels: []CancelCloidWire{{Asset: 3, Cloid: GetRandomCloid()}}},
+		ModifyOrderAction{Type: "batchModify", Modifies: []ModifyOrderWire{
+			{OrderId: 123456789, Order: orders[3]},
+			{OrderId: GetRandomCloid(), Order: orders[4]},
+		}},
+		ModifyOrderAction{Type: "batchModify", Modifies: []ModifyOrderWire{{OrderId: int64(5), Order: orders[5]}}},
+		UpdateLeverageAction{Type: "updateLeverage", Asset: 1, IsCross: true, Leverage: 20},
+	}
+}
+
+// TestFastSignerEncoding tests that the hand written msgpack encoding matches msgpack.Marshal
+func TestFastSignerEncoding(t *testing.T) {
+	for i, action := range testFastSignerActions() {
+		expected, err := msgpack.Marshal(action)
+		if err != nil {
+			t.Fatalf("msgpack.Marshal failed: %v", err)
+		}
+		encoded, err := appendActionMsgpack(nil, action)
+		if err != nil {
+			t.Fatalf("appendActionMsgpack failed: %v", err)
+		}
+		if !bytes.Equal(encoded, expected) {
+			t.Errorf("Action %d: expected encoding\n%x\ngot\n%x", i, expected, encoded)
+		}
+	}
+	for _, n := range []int64{0, 127, 128, 255, 256, 65535, 65536, 1 << 32, -1, -32, -33, -128, -129, -32768, -32769, -1 << 31, -1<<31 - 1} {
+		expected, _ := msgpack.Marshal(int(n))
+		if encoded := appendInt(nil, n); !bytes.Equal(encoded, expected) {
+			t.Errorf("Integer %d: expected %x, got %x", n, expected, encoded)
+		}
+	}
+}
+
+// TestFastSignerSignatures tests that the fast signer produces the signatures of the typed data path
+func TestFastSignerSignatures(t *testing.T) {
+	for _, isMainnet := range []bool{true, false} {
+		api := newTestExchangeAPI(t, nil)
+		api.Client = *NewClient(isMainnet)
+		api.SetPrivateKey(testPrivateKey)
+		signer, err := api.l1Signer()
+		if err != nil {
+			t.Fatalf("l1Signer failed: %v", err)
+		}
+		for i, action := range testFastSignerActions() {
+			for _, expiresAfter := range []uint64{0, 1700000060000} {
+				v, r, s, err := api.SignL1ActionWithExpiry(action, 1700000000000, expiresAfter)
+				if err != nil {
+					t.Fatalf("SignL1ActionWithExpiry failed: %v", err)
+				}
+				fastV, fastR, fastS, err := signer.SignL1Action(action, 1700000000000, "", expiresAfter)
+				if err != nil {
+					t.Fatalf("FastSigner.SignL1Action failed: %v", err)
+				}
+				if v != fastV || r != fastR || s != fastS {
+					t.Errorf("Action %d (mainnet %v, expiry %d): signatures differ", i, isMainnet, expiresAfter)
+				}
+			}
+		}
+		vault := "0x1719884eb866cb12b2287399b15f7db5e7d775ea"
+		expected, _ := buildActionHash(testFastSignerActions()[0], vault, 1, 0)
+		if hash, _ := signer.ActionHash(testFastSignerActions()[0], vault, 1, 0); hash != expected {
+			t.Errorf("Expected vault action hash %s, got %s", expected, hash)
+		}
+	}
+
+	api := newTestExchangeAPI(t, nil)
+	first, _ := api.l1Signer()
+	api.Client = *NewClient(false)
+	api.SetPrivateKey(testPrivateKey)
+	if second, _ := api.l1Signer(); second == first || second.isMainnet {
+		t.Error("Expected a new signer after changing the network and key")
+	}
+	if _, err := NewFastSigner(nil, true); err == nil {
+		t.Error("Expected error for a signer without key")
+	}
+}
+
+func benchmarkOrderAction() PlaceOrderAction {
+	orders := make([]OrderWire, 10)
+	for i := range orders {
+		orders[i] = OrderWire{
+			Asset: i, IsBuy: i%2 == 0, LimitPx: "50000.5", SizePx: "0.001",
+			OrderType: OrderTypeWire{Limit: &LimitOrderType{Tif: TifAlo}}, Cloid: GetRandomCloid(),
+		}
+	}
+	return PlaceOrderAction{Type: "order", Orders: orders, Grouping: GroupingNa}
+}
+
+func BenchmarkSignL1Action(b *testing.B) {
+	api := newTestExchangeAPI(b, nil)
+	action := benchmarkOrderAction()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := api.SignL1Action(action, uint64(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFastSignL1Action(b *testing.B) {
+	api := newTestExchangeAPI(b, nil)
+	signer, _ := api.l1Signer()
+	action := benchmarkOrderAction()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := signer.SignL1Action(action, uint64(i), "", 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkActionHash(b *testing.B) {
+	action := benchmarkOrderAction()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := buildActionHash(action, "", uint64(i), 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFastActionHash(b *testing.B) {
+	api := newTestExchangeAPI(b, nil)
+	signer, _ := api.l1Signer()
+	action := benchmarkOrderAction()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := signer.ActionHash(action, "", uint64(i), 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
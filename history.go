@@ -0,0 +1,192 @@
+package hyperliquid
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"sort"
+	"strconv"
+)
+
+// Maximum number of rows per response of the paged endpoints
+const (
+	fillsPageLimit  = 2000
+	ledgerPageLimit = 500
+)
+
+// ErrHistoryPageOverflow is returned when a single timestamp holds more rows than one response
+// can carry, so the rows past the first page can not be requested
+var ErrHistoryPageOverflow = errors.New("history page overflow")
+
+// IterUserFills streams all fills of a user between startTime and endTime (in milliseconds).
+// The range is paged by time until it is fully covered, fills are deduplicated by tid and oid.
+// If a single millisecond holds more fills than one response can carry, the iteration ends with ErrHistoryPageOverflow.
+//
+// Example:
+//
+//	for fill, err := range api.IterUserFills(address, start, end) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (api *InfoAPI) IterUserFills(address string, startTime int64, endTime int64) iter.Seq2[OrderFill, error] {
+	return pageByTime(
+		func(start, end int64) (*[]OrderFill, error) {
+			return api.GetUserFillsByTime(address, start, end)
+		},
+		func(fill OrderFill) int64 { return fill.Time },
+		func(fill OrderFill) string {
+			return strconv.FormatInt(fill.Tid, 10) + ":" + strconv.Itoa(fill.Oid)
+		},
+		fillsPageLimit, startTime, endTime,
+	)
+}
+
+// IterAccountFills is the same as IterUserFills but user is set to the account address
+func (api *InfoAPI) IterAccountFills(startTime int64, endTime int64) iter.Seq2[OrderFill, error] {
+	return api.IterUserFills(api.AccountAddress(), startTime, endTime)
+}
+
+// IterFundingUpdates streams all funding payments of a user between startTime and endTime (in milliseconds).
+// Funding updates are deduplicated by time and coin.
+func (api *InfoAPI) IterFundingUpdates(address string, startTime int64, endTime int64) iter.Seq2[FundingUpdate, error] {
+	return pageByTime(
+		func(start, end int64) (*[]FundingUpdate, error) {
+			return api.GetFundingUpdates(address, start, end)
+		},
+		func(update FundingUpdate) int64 { return update.Time },
+		func(update FundingUpdate) string {
+			return strconv.FormatInt(update.Time, 10) + ":" + update.Delta.Asset + ":" + update.Hash
+		},
+		ledgerPageLimit, startTime, endTime,
+	)
+}
+
+// IterAccountFundingUpdates is the same as IterFundingUpdates but user is set to the account address
+func (api *InfoAPI) IterAccountFundingUpdates(startTime int64, endTime int64) iter.Seq2[FundingUpdate, error] {
+	return api.IterFundingUpdates(api.AccountAddress(), startTime, endTime)
+}
+
+// IterNonFundingUpdates streams all non-funding ledger updates (deposits, withdrawals, transfers, ...)
+// of a user between startTime and endTime (in milliseconds). Updates are deduplicated by hash.
+func (api *InfoAPI) IterNonFundingUpdates(address string, startTime int64, endTime int64) iter.Seq2[NonFundingUpdate, error] {
+	return pageByTime(
+		func(start, end int64) (*[]NonFundingUpdate, error) {
+			return api.GetNonFundingUpdates(address, start, end)
+		},
+		func(update NonFundingUpdate) int64 { return update.Time },
+		func(update NonFundingUpdate) string {
+			return update.Hash + ":" + update.Delta.Type + ":" + strconv.FormatInt(update.Time, 10)
+		},
+		ledgerPageLimit, startTime, endTime,
+	)
+}
+
+// IterAccountNonFundingUpdates is the same as IterNonFundingUpdates but user is set to the account address
+func (api *InfoAPI) IterAccountNonFundingUpdates(startTime int64, endTime int64) iter.Seq2[NonFundingUpdate, error] {
+	return api.IterNonFundingUpdates(api.AccountAddress(), startTime, endTime)
+}
+
+// IterHistoricalFundingRates streams the funding rate history of a coin between startTime and endTime (in milliseconds)
+func (api *InfoAPI) IterHistoricalFundingRates(coin string, startTime int64, endTime int64) iter.Seq2[HistoricalFundingRate, error] {
+	return pageByTime(
+		func(start, end int64) (*[]HistoricalFundingRate, error) {
+			return api.GetHistoricalFundingRates(coin, start, end)
+		},
+		func(rate HistoricalFundingRate) int64 { return rate.Time },
+		func(rate HistoricalFundingRate) string {
+			return rate.Coin + ":" + strconv.FormatInt(rate.Time, 10)
+		},
+		ledgerPageLimit, startTime, endTime,
+	)
+}
+
+// CollectHistory drains a history iterator into a slice, stopping at the first error
+func CollectHistory[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var result []T
+	for item, err := range seq {
+		if err != nil {
+			return result, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// pageByTime pages a time ranged endpoint whose responses are capped in size.
+// Each request starts at the latest time seen so far, so rows sharing that timestamp
+// are requested twice and dropped by key. Paging stops once a request returns nothing new.
+// A single timestamp holding limit rows or more can not be paged past its first response,
+// in that case ErrHistoryPageOverflow is yielded instead of skipping its remaining rows.
+func pageByTime[T any](
+	fetch func(startTime int64, endTime int64) (*[]T, error),
+	timeOf func(T) int64,
+	keyOf func(T) string,
+	limit int,
+	startTime int64,
+	endTime int64,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		from := startTime
+		// Keys of the rows at the last seen timestamp
+		boundary := make(map[string]struct{})
+
+		for from <= endTime {
+			page, err := fetch(from, endTime)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if page == nil || len(*page) == 0 {
+				return
+			}
+			rows := *page
+			sort.SliceStable(rows, func(i, j int) bool {
+				return timeOf(rows[i]) < timeOf(rows[j])
+			})
+
+			lastTime := timeOf(rows[len(rows)-1])
+			nextBoundary := make(map[string]struct{})
+			newRows := 0
+			for _, row := range rows {
+				key := keyOf(row)
+				if timeOf(row) == lastTime {
+					nextBoundary[key] = struct{}{}
+				}
+				if _, seen := boundary[key]; seen {
+					continue
+				}
+				newRows++
+				if !yield(row, nil) {
+					return
+				}
+			}
+			if lastTime == from {
+				for key := range boundary {
+					nextBoundary[key] = struct{}{}
+				}
+			}
+			boundary = nextBoundary
+
+			if newRows == 0 {
+				if len(rows) >= limit {
+					// A full page of rows sharing a single timestamp, its other rows are out of reach
+					var zero T
+					yield(zero, fmt.Errorf("%w: %d or more rows at time %d", ErrHistoryPageOverflow, limit, lastTime))
+					return
+				}
+				if lastTime >= endTime || lastTime < from {
+					return
+				}
+				from = lastTime + 1
+				continue
+			}
+			if lastTime >= endTime {
+				return
+			}
+			from = lastTime
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package hyperliquid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestPageByTime tests paging over a capped endpoint with rows sharing boundary timestamps
+func TestPageByTime(t *testing.T) {
+	pageSize := 4
+	all := []OrderFill{
+		{Tid: 1, Time: 10}, {Tid: 2, Time: 20}, {Tid: 3, Time: 30}, {Tid: 4, Time: 30},
+		{Tid: 5, Time: 30}, {Tid: 6, Time: 40}, {Tid: 7, Time: 40}, {Tid: 8, Time: 50},
+	}
+	requests := 0
+	fetch := func(start, end int64) (*[]OrderFill, error) {
+		requests++
+		var page []OrderFill
+		for _, fill := range all {
+			if fill.Time >= start && fill.Time <= end && len(page) < pageSize {
+				page = append(page, fill)
+			}
+		}
+		return &page, nil
+	}
+
+	seq := pageByTime(fetch,
+		func(fill OrderFill) int64 { return fill.Time },
+		func(fill OrderFill) string { return string(rune('a' + fill.Tid)) },
+		pageSize, 0, 45,
+	)
+	fills, err := CollectHistory(seq)
+	if err != nil {
+		t.Fatalf("CollectHistory failed: %v", err)
+	}
+	if len(fills) != 7 {
+		t.Fatalf("Expected 7 fills, got %d: %+v", len(fills), fills)
+	}
+	for i, fill := range fills {
+		if fill.Tid != int64(i+1) {
+			t.Errorf("Unexpected fill at %d: %+v", i, fill)
+		}
+	}
+	if requests > 10 {
+		t.Errorf("Too many requests: %d", requests)
+	}
+
+	// A full page sharing one timestamp can not be paged past
+	all = []OrderFill{{Tid: 1, Time: 10}, {Tid: 2, Time: 30}, {Tid: 3, Time: 30}, {Tid: 4, Time: 30}, {Tid: 5, Time: 30}, {Tid: 6, Time: 40}}
+	pageSize = 3
+	overflowing := pageByTime(fetch,
+		func(fill OrderFill) int64 { return fill.Time },
+		func(fill OrderFill) string { return string(rune('a' + fill.Tid)) },
+		pageSize, 0, 45,
+	)
+	fills, err = CollectHistory(overflowing)
+	if !errors.Is(err, ErrHistoryPageOverflow) || !strings.Contains(err.Error(), "time 30") {
+		t.Errorf("Expected page overflow at time 30, got %v", err)
+	}
+	if len(fills) != 4 {
+		t.Errorf("Expected the 4 fills before the overflow, got %+v", fills)
+	}
+
+	failing := pageByTime(func(start, end int64) (*[]OrderFill, error) {
+		return nil, errors.New("boom")
+	}, func(fill OrderFill) int64 { return fill.Time }, func(fill OrderFill) string { return "" }, 1, 0, 1)
+	if _, err := CollectHistory(failing); err == nil {
+		t.Error("Expected error to be returned")
+	}
+}
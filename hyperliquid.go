@@ -1,5 +1,11 @@
 package hyperliquid
 
+import (
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
 // IHyperliquid is the main interface that embeds all other APIs
 type IHyperliquid interface {
 	IExchangeAPI
@@ -22,6 +28,9 @@ type HyperliquidClientConfig struct {
 	IsMainnet      bool
 	AccountAddress string
 	PrivateKey     string
+	Metrics        MetricsHook          // Optional hook for SDK metrics, e.g. PrometheusMetrics
+	Logger         *slog.Logger         // Optional structured logger, signatures and keys are redacted
+	TracerProvider trace.TracerProvider // Optional OpenTelemetry provider, the global one if nil
 }
 
 func NewHyperliquid(config *HyperliquidClientConfig) *Hyperliquid {
@@ -45,11 +54,21 @@ func NewHyperliquid(config *HyperliquidClientConfig) *Hyperliquid {
 	exchangeAPI.SetWebSocketAPI(webSocketAPI)
 	infoAPI.SetWebSocketAPI(webSocketAPI)
 
-	return &Hyperliquid{
+	h := &Hyperliquid{
 		ExchangeAPI:  exchangeAPI,
 		InfoAPI:      infoAPI,
 		WebSocketAPI: webSocketAPI,
 	}
+	if defaultConfig.Metrics != nil {
+		h.SetMetrics(defaultConfig.Metrics)
+	}
+	if defaultConfig.Logger != nil {
+		h.SetLogger(defaultConfig.Logger)
+	}
+	if defaultConfig.TracerProvider != nil {
+		h.SetTracing(NewTracing(defaultConfig.TracerProvider))
+	}
+	return h
 }
 
 // AccountAddress returns the account address
@@ -61,3 +80,39 @@ func (h *Hyperliquid) AccountAddress() string {
 func (h *Hyperliquid) IsMainnet() bool {
 	return h.ExchangeAPI.IsMainnet()
 }
+
+// SetTelemetry sets the Telemetry of all APIs
+func (h *Hyperliquid) SetTelemetry(telemetry *Telemetry) {
+	h.ExchangeAPI.SetTelemetry(telemetry)
+	h.InfoAPI.SetTelemetry(telemetry)
+	h.WebSocketAPI.SetTelemetry(telemetry)
+}
+
+// SetLogger sets the structured logger of all APIs
+func (h *Hyperliquid) SetLogger(logger *slog.Logger) {
+	h.ExchangeAPI.SetLogger(logger)
+	h.ExchangeAPI.infoAPI.SetLogger(logger)
+	h.InfoAPI.SetLogger(logger)
+	h.WebSocketAPI.SetLogger(logger)
+}
+
+// SetMetrics sets the MetricsHook of all APIs
+func (h *Hyperliquid) SetMetrics(metrics MetricsHook) {
+	h.ExchangeAPI.SetMetrics(metrics)
+	h.InfoAPI.SetMetrics(metrics)
+	h.WebSocketAPI.SetMetrics(metrics)
+}
+
+// SetTracing sets the Tracing of all APIs
+func (h *Hyperliquid) SetTracing(tracing *Tracing) {
+	h.ExchangeAPI.SetTracing(tracing)
+	h.ExchangeAPI.infoAPI.SetTracing(tracing)
+	h.InfoAPI.SetTracing(tracing)
+	h.WebSocketAPI.SetTracing(tracing)
+}
+
+// Stats returns the latency measurements and the clock offset estimate of the WebSocket API.
+// All APIs share DefaultTelemetry unless set otherwise.
+func (h *Hyperliquid) Stats() TelemetryStats {
+	return h.WebSocketAPI.Telemetry().Stats()
+}
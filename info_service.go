@@ -1,7 +1,6 @@
 package hyperliquid
 
 import (
-	"fmt"
 	"strconv"
 )
 
@@ -9,23 +8,52 @@ import (
 type IInfoAPI interface {
 	// INFO API ENDPOINTS
 	GetAllMids() (*map[string]string, error)
+	GetSpotMetaAndAssetCtxs() (*SpotMetaAndAssetCtxsResponse, error)
+	GetAllSpotPrices() (*map[string]string, error)
 	GetOpenOrders(address string) (*[]Order, error)
 	GetAccountOpenOrders() (*[]Order, error)
+	GetFrontendOpenOrders(address string) (*[]Order, error)
+	GetAccountFrontendOpenOrders() (*[]Order, error)
 	GetUserFills(address string) (*[]OrderFill, error)
 	GetAccountFills() (*[]OrderFill, error)
-	GetUserRateLimits(address string) (*float64, error)
+	GetUserFillsByTime(address string, startTime int64, endTime int64) (*[]OrderFill, error)
+	GetAccountFillsByTime(startTime int64, endTime int64) (*[]OrderFill, error)
+	GetOrderStatus(address string, oid int64) (*OrderStatusResponse, error)
+	GetAccountOrderStatus(oid int64) (*OrderStatusResponse, error)
+	GetOrderStatusByCloid(address string, cloid string) (*OrderStatusResponse, error)
+	GetAccountOrderStatusByCloid(cloid string) (*OrderStatusResponse, error)
+	GetUserRateLimits(address string) (*RatesLimits, error)
+	GetAccountRateLimits() (*RatesLimits, error)
 	GetL2BookSnapshot(coin string) (*L2BookSnapshot, error)
-	GetCandleSnapshot(coin string, interval string, startTime int64, endTime int64) (*CandleSnapshot, error)
+	GetCandleSnapshot(coin string, interval string, startTime int64, endTime int64) (*[]CandleSnapshot, error)
+	GetUserFees(address string) (*UserFees, error)
+	GetAccountFees() (*UserFees, error)
+	GetPortfolio(address string) (*Portfolio, error)
+	GetAccountPortfolio() (*Portfolio, error)
+	GetSubAccounts(address string) (*[]SubAccount, error)
+	GetAccountSubAccounts() (*[]SubAccount, error)
+	GetVaultDetails(vaultAddress string, user string) (*VaultDetails, error)
+	GetAccountVaultDetails(vaultAddress string) (*VaultDetails, error)
+	GetUserVaultEquities(address string) (*[]VaultEquity, error)
+	GetAccountVaultEquities() (*[]VaultEquity, error)
+	GetReferral(address string) (*Referral, error)
+	GetAccountReferral() (*Referral, error)
+	GetDelegations(address string) (*[]Delegation, error)
+	GetAccountDelegations() (*[]Delegation, error)
+	GetMaxBuilderFee(address string, builder string) (*int, error)
+	GetAccountMaxBuilderFee(builder string) (*int, error)
 
 	// PERPETUALS INFO API ENDPOINTS
 	GetMeta() (*Meta, error)
+	GetMetaAndAssetCtxs() (*MetaAndAssetCtxs, error)
 	GetUserState(address string) (*UserState, error)
 	GetAccountState() (*UserState, error)
 	GetFundingUpdates(address string, startTime int64, endTime int64) (*[]FundingUpdate, error)
 	GetAccountFundingUpdates(startTime int64, endTime int64) (*[]FundingUpdate, error)
 	GetNonFundingUpdates(address string, startTime int64, endTime int64) (*[]NonFundingUpdate, error)
 	GetAccountNonFundingUpdates(startTime int64, endTime int64) (*[]NonFundingUpdate, error)
-	GetHistoricalFundingRates() (*[]HistoricalFundingRate, error)
+	GetHistoricalFundingRates(coin string, startTime int64, endTime int64) (*[]HistoricalFundingRate, error)
+	GetPredictedFundings() (*[]CoinPredictedFundings, error)
 
 	// Additional helper functions
 	GetMartketPx(coin string) (float64, error)
@@ -34,6 +62,8 @@ type IInfoAPI interface {
 	GetAccountWithdrawals() (*[]Withdrawal, error)
 }
 
+var _ IInfoAPI = (*InfoAPI)(nil)
+
 type InfoAPI struct {
 	Client
 	baseEndpoint string
@@ -61,7 +91,7 @@ func NewInfoAPI(isMainnet bool, accountAddress string, privateKey string) *InfoA
 
 	spotMeta, err := api.BuildSpotMetaMap()
 	if err != nil {
-		api.debug("Error building meta map: %s", err)
+		api.Logger().Warn("Failed to build spot meta map", "err", err)
 	}
 	api.spotMeta = spotMeta
 	return &api
@@ -82,34 +112,26 @@ func (api *InfoAPI) GetAllMids() (*map[string]string, error) {
 
 // Retrieve spot meta and asset contexts
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/spot#retrieve-spot-asset-contexts
-func (api *InfoAPI) GetAllSpotPrices() (*map[string]string, error) {
+func (api *InfoAPI) GetSpotMetaAndAssetCtxs() (*SpotMetaAndAssetCtxsResponse, error) {
 	request := InfoRequest{
 		Typez: "spotMetaAndAssetCtxs",
 	}
-	response, err := MakeUniversalRequest[SpotMetaAndAssetCtxsResponse](api, request)
-	if err != nil {
-		return nil, err
-	}
-
-	marketsData, ok := response[1].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid markets data format")
-	}
-
-	result := make(map[string]string)
+	return MakeUniversalRequest[SpotMetaAndAssetCtxsResponse](api, request)
+}
 
-	marketBytes, err := FastMarshal(marketsData)
+// Retrieve mids of all spot pairs keyed by pair name, pairs with an empty book are left out
+func (api *InfoAPI) GetAllSpotPrices() (*map[string]string, error) {
+	response, err := api.GetSpotMetaAndAssetCtxs()
 	if err != nil {
 		return nil, err
 	}
 
-	var markets []Market
-	if err := FastUnmarshal(marketBytes, &markets); err != nil {
-		return nil, err
-	}
-
-	for _, market := range markets {
-		result[market.Coin] = market.MidPx
+	result := make(map[string]string, len(response.AssetCtxs))
+	for _, ctx := range response.AssetCtxs {
+		if ctx.MidPx == 0 {
+			continue
+		}
+		result[ctx.Coin] = strconv.FormatFloat(ctx.MidPx, 'f', -1, 64)
 	}
 
 	return &result, nil
@@ -149,6 +171,78 @@ func (api *InfoAPI) GetAccountFills() (*[]OrderFill, error) {
 	return api.GetUserFills(api.AccountAddress())
 }
 
+// Retrieve a user's fills by time (at most 2000 fills per response)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-fills-by-time
+func (api *InfoAPI) GetUserFillsByTime(address string, startTime int64, endTime int64) (*[]OrderFill, error) {
+	request := InfoRequest{
+		User:      address,
+		Typez:     "userFillsByTime",
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+	return MakeUniversalRequest[[]OrderFill](api, request)
+}
+
+// Retrieve account's fills by time
+// The same as GetUserFillsByTime but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFillsByTime(startTime int64, endTime int64) (*[]OrderFill, error) {
+	return api.GetUserFillsByTime(api.AccountAddress(), startTime, endTime)
+}
+
+// Retrieve a user's open orders with additional frontend info
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-open-orders-with-additional-frontend-info
+func (api *InfoAPI) GetFrontendOpenOrders(address string) (*[]Order, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "frontendOpenOrders",
+	}
+	return MakeUniversalRequest[[]Order](api, request)
+}
+
+// Retrieve account's open orders with additional frontend info
+// The same as GetFrontendOpenOrders but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFrontendOpenOrders() (*[]Order, error) {
+	return api.GetFrontendOpenOrders(api.AccountAddress())
+}
+
+// Query order status by oid
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-order-status-by-oid-or-cloid
+func (api *InfoAPI) GetOrderStatus(address string, oid int64) (*OrderStatusResponse, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "orderStatus",
+		Oid:   oid,
+	}
+	return MakeUniversalRequest[OrderStatusResponse](api, request)
+}
+
+// Query account's order status by oid
+// The same as GetOrderStatus but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountOrderStatus(oid int64) (*OrderStatusResponse, error) {
+	return api.GetOrderStatus(api.AccountAddress(), oid)
+}
+
+// Query order status by cloid
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-order-status-by-oid-or-cloid
+func (api *InfoAPI) GetOrderStatusByCloid(address string, cloid string) (*OrderStatusResponse, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "orderStatus",
+		Oid:   cloid,
+	}
+	return MakeUniversalRequest[OrderStatusResponse](api, request)
+}
+
+// Query account's order status by cloid
+// The same as GetOrderStatusByCloid but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountOrderStatusByCloid(cloid string) (*OrderStatusResponse, error) {
+	return api.GetOrderStatusByCloid(api.AccountAddress(), cloid)
+}
+
 // Query user rate limits
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-user-rate-limits
 func (api *InfoAPI) GetUserRateLimits(address string) (*RatesLimits, error) {
@@ -200,6 +294,15 @@ func (api *InfoAPI) GetMeta() (*Meta, error) {
 	return MakeUniversalRequest[Meta](api, request)
 }
 
+// Retrieve perpetuals asset contexts (includes mark price, current funding, open interest, etc.)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-perpetuals-asset-contexts-includes-mark-price-current-funding-open-interest-etc
+func (api *InfoAPI) GetMetaAndAssetCtxs() (*MetaAndAssetCtxs, error) {
+	request := InfoRequest{
+		Typez: "metaAndAssetCtxs",
+	}
+	return MakeUniversalRequest[MetaAndAssetCtxs](api, request)
+}
+
 // Retrieve spot metadata
 func (api *InfoAPI) GetSpotMeta() (*SpotMeta, error) {
 	request := InfoRequest{
@@ -292,6 +395,153 @@ func (api *InfoAPI) GetHistoricalFundingRates(coin string, startTime int64, endT
 	return MakeUniversalRequest[[]HistoricalFundingRate](api, request)
 }
 
+// Retrieve predicted funding rates for different venues
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-predicted-funding-rates-for-different-venues
+func (api *InfoAPI) GetPredictedFundings() (*[]CoinPredictedFundings, error) {
+	request := InfoRequest{
+		Typez: "predictedFundings",
+	}
+	return MakeUniversalRequest[[]CoinPredictedFundings](api, request)
+}
+
+// Query user fees
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-fees
+func (api *InfoAPI) GetUserFees(address string) (*UserFees, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "userFees",
+	}
+	return MakeUniversalRequest[UserFees](api, request)
+}
+
+// Query account fees
+// The same as GetUserFees but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFees() (*UserFees, error) {
+	return api.GetUserFees(api.AccountAddress())
+}
+
+// Query a user's portfolio (account value and pnl history)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-portfolio
+func (api *InfoAPI) GetPortfolio(address string) (*Portfolio, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "portfolio",
+	}
+	return MakeUniversalRequest[Portfolio](api, request)
+}
+
+// Query account's portfolio
+// The same as GetPortfolio but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountPortfolio() (*Portfolio, error) {
+	return api.GetPortfolio(api.AccountAddress())
+}
+
+// Retrieve a user's subaccounts
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-subaccounts
+func (api *InfoAPI) GetSubAccounts(address string) (*[]SubAccount, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "subAccounts",
+	}
+	return MakeUniversalRequest[[]SubAccount](api, request)
+}
+
+// Retrieve account's subaccounts
+// The same as GetSubAccounts but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountSubAccounts() (*[]SubAccount, error) {
+	return api.GetSubAccounts(api.AccountAddress())
+}
+
+// Retrieve details for a vault, followerState is set for user if it is not empty
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-details-for-a-vault
+func (api *InfoAPI) GetVaultDetails(vaultAddress string, user string) (*VaultDetails, error) {
+	request := InfoRequest{
+		User:         user,
+		Typez:        "vaultDetails",
+		VaultAddress: vaultAddress,
+	}
+	return MakeUniversalRequest[VaultDetails](api, request)
+}
+
+// Retrieve details for a vault
+// The same as GetVaultDetails but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountVaultDetails(vaultAddress string) (*VaultDetails, error) {
+	return api.GetVaultDetails(vaultAddress, api.AccountAddress())
+}
+
+// Retrieve a user's vault deposits
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-vault-deposits
+func (api *InfoAPI) GetUserVaultEquities(address string) (*[]VaultEquity, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "userVaultEquities",
+	}
+	return MakeUniversalRequest[[]VaultEquity](api, request)
+}
+
+// Retrieve account's vault deposits
+// The same as GetUserVaultEquities but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountVaultEquities() (*[]VaultEquity, error) {
+	return api.GetUserVaultEquities(api.AccountAddress())
+}
+
+// Query a user's referral information
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-referral-information
+func (api *InfoAPI) GetReferral(address string) (*Referral, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "referral",
+	}
+	return MakeUniversalRequest[Referral](api, request)
+}
+
+// Query account's referral information
+// The same as GetReferral but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountReferral() (*Referral, error) {
+	return api.GetReferral(api.AccountAddress())
+}
+
+// Query a user's staking delegations
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-staking-delegations
+func (api *InfoAPI) GetDelegations(address string) (*[]Delegation, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "delegations",
+	}
+	return MakeUniversalRequest[[]Delegation](api, request)
+}
+
+// Query account's staking delegations
+// The same as GetDelegations but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountDelegations() (*[]Delegation, error) {
+	return api.GetDelegations(api.AccountAddress())
+}
+
+// Check builder fee approval, the result is the max approved fee in tenths of a basis point
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#check-builder-fee-approval
+func (api *InfoAPI) GetMaxBuilderFee(address string, builder string) (*int, error) {
+	request := InfoRequest{
+		User:    address,
+		Typez:   "maxBuilderFee",
+		Builder: builder,
+	}
+	return MakeUniversalRequest[int](api, request)
+}
+
+// Check account's builder fee approval
+// The same as GetMaxBuilderFee but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountMaxBuilderFee(builder string) (*int, error) {
+	return api.GetMaxBuilderFee(api.AccountAddress(), builder)
+}
+
 // Helper function to get the market price of a given coin
 // The coin parameter is the name of the coin
 //
@@ -0,0 +1,124 @@
+package hyperliquid
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestInfoAPI returns an InfoAPI whose requests are answered by handler
+func newTestInfoAPI(t *testing.T, handler func(body []byte) string) *InfoAPI {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte(handler(body)))
+	}))
+	t.Cleanup(server.Close)
+
+	api := &InfoAPI{
+		Client:       *NewClient(true),
+		baseEndpoint: "/info",
+	}
+	api.baseUrl = server.URL
+	return api
+}
+
+// TestInfoRequestTypes tests request encoding and response decoding of the info endpoints with custom formats
+func TestInfoRequestTypes(t *testing.T) {
+	var lastBody string
+	response := ""
+	api := newTestInfoAPI(t, func(body []byte) string {
+		lastBody = string(body)
+		return response
+	})
+
+	response = `{"status":"order","order":{"order":{"coin":"BTC","side":"B","limitPx":"100.0","sz":"0.1","oid":42,"timestamp":1,"origSz":"0.1","cloid":"0x1"},"status":"open","statusTimestamp":2}}`
+	status, err := api.GetOrderStatus("0x1", 42)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if lastBody != `{"user":"0x1","type":"orderStatus","oid":42}` {
+		t.Errorf("Unexpected request: %s", lastBody)
+	}
+	if status.Order == nil || status.Order.Order.Oid != 42 || status.Order.Status != "open" {
+		t.Errorf("Unexpected order status: %+v", status)
+	}
+	if _, err := api.GetOrderStatusByCloid("0x1", "0xabc"); err != nil {
+		t.Fatalf("GetOrderStatusByCloid failed: %v", err)
+	}
+	if lastBody != `{"user":"0x1","type":"orderStatus","oid":"0xabc"}` {
+		t.Errorf("Unexpected request: %s", lastBody)
+	}
+
+	response = `[["BTC",[["BinPerp",{"fundingRate":"0.0001","nextFundingTime":1733961600000}],["HlPerp",{"fundingRate":"-0.00002","nextFundingTime":1733958000000,"fundingIntervalHours":1}],["BybitPerp",null]]]]`
+	fundings, err := api.GetPredictedFundings()
+	if err != nil {
+		t.Fatalf("GetPredictedFundings failed: %v", err)
+	}
+	if len(*fundings) != 1 || (*fundings)[0].Coin != "BTC" || len((*fundings)[0].Venues) != 2 {
+		t.Fatalf("Unexpected predicted fundings: %+v", fundings)
+	}
+	if venue := (*fundings)[0].Venues[1]; venue.Venue != "HlPerp" || venue.FundingRate != -0.00002 || venue.FundingIntervalHours != 1 {
+		t.Errorf("Unexpected venue: %+v", venue)
+	}
+
+	response = `[["day",{"accountValueHistory":[[1700000000000,"100.5"]],"pnlHistory":[[1700000000000,"-1.5"]],"vlm":"10.0"}],["allTime",{"accountValueHistory":[],"pnlHistory":[],"vlm":"0.0"}]]`
+	portfolio, err := api.GetPortfolio("0x1")
+	if err != nil {
+		t.Fatalf("GetPortfolio failed: %v", err)
+	}
+	day := (*portfolio)["day"]
+	if len(*portfolio) != 2 || len(day.AccountValueHistory) != 1 || day.AccountValueHistory[0].Value != 100.5 || day.PnlHistory[0].Time != 1700000000000 {
+		t.Errorf("Unexpected portfolio: %+v", portfolio)
+	}
+
+	response = `1`
+	fee, err := api.GetMaxBuilderFee("0x1", "0x2")
+	if err != nil || *fee != 1 {
+		t.Fatalf("GetMaxBuilderFee failed: %v", err)
+	}
+	if lastBody != `{"user":"0x1","type":"maxBuilderFee","builder":"0x2"}` {
+		t.Errorf("Unexpected request: %s", lastBody)
+	}
+}
+
+// TestAssetCtxs tests decoding and joining of perp and spot asset contexts
+func TestAssetCtxs(t *testing.T) {
+	response := ""
+	api := newTestInfoAPI(t, func(body []byte) string { return response })
+
+	response = `[{"universe":[{"name":"BTC","szDecimals":5,"maxLeverage":50},{"name":"ETH","szDecimals":4,"maxLeverage":25}]},` +
+		`[{"dayNtlVlm":"1169046.29406","funding":"0.0000125","impactPxs":["14.3047","14.3444"],"markPx":"14.3161","midPx":"14.314","openInterest":"688.11","oraclePx":"14.32","premium":"0.00031774","prevDayPx":"15.322"},` +
+		`{"dayNtlVlm":"0.0","funding":"-0.0001","impactPxs":null,"markPx":"2000.5","midPx":null,"openInterest":"1.0","oraclePx":"2001","premium":null,"prevDayPx":"1990"}]]`
+	perps, err := api.GetMetaAndAssetCtxs()
+	if err != nil {
+		t.Fatalf("GetMetaAndAssetCtxs failed: %v", err)
+	}
+	markets := perps.ByCoin()
+	btc, eth := markets["BTC"], markets["ETH"]
+	if btc.AssetId != 0 || btc.MaxLeverage != 50 || btc.Ctx.Funding != 0.0000125 || len(btc.Ctx.ImpactPxs) != 2 || btc.Ctx.ImpactPxs[1] != 14.3444 {
+		t.Errorf("Unexpected BTC market: %+v", btc)
+	}
+	if eth.AssetId != 1 || eth.Ctx.MidPx != 0 || eth.Ctx.Premium != 0 || eth.Ctx.ImpactPxs != nil || eth.Ctx.MarkPx != 2000.5 {
+		t.Errorf("Unexpected ETH market: %+v", eth)
+	}
+
+	response = `[{"universe":[{"tokens":[1,0],"name":"PURR/USDC","index":0,"isCanonical":true},{"tokens":[2,0],"name":"@1","index":1,"isCanonical":false}],"tokens":[]},` +
+		`[{"prevDayPx":"0.2","dayNtlVlm":"100","markPx":"0.21","midPx":"0.2105","circulatingSupply":"1000","coin":"PURR/USDC","totalSupply":"2000","dayBaseVlm":"500"},` +
+		`{"prevDayPx":"1","dayNtlVlm":"0","markPx":"1","midPx":null,"circulatingSupply":"1","coin":"@1","totalSupply":"1","dayBaseVlm":"0"}]]`
+	spot, err := api.GetSpotMetaAndAssetCtxs()
+	if err != nil {
+		t.Fatalf("GetSpotMetaAndAssetCtxs failed: %v", err)
+	}
+	if purr := spot.ByCoin()["PURR/USDC"]; purr.AssetId != 10000 || purr.Ctx.CirculatingSupply != 1000 {
+		t.Errorf("Unexpected PURR market: %+v", purr)
+	}
+	prices, err := api.GetAllSpotPrices()
+	if err != nil {
+		t.Fatalf("GetAllSpotPrices failed: %v", err)
+	}
+	if len(*prices) != 1 || (*prices)["PURR/USDC"] != "0.2105" {
+		t.Errorf("Unexpected spot prices: %v", *prices)
+	}
+}
@@ -1,13 +1,17 @@
 package hyperliquid
 
+import jsoniter "github.com/json-iterator/go"
+
 // Base request for /info
 type InfoRequest struct {
-	User      string `json:"user,omitempty"`
-	Typez     string `json:"type"`
-	Oid       string `json:"oid,omitempty"`
-	Coin      string `json:"coin,omitempty"`
-	StartTime int64  `json:"startTime,omitempty"`
-	EndTime   int64  `json:"endTime,omitempty"`
+	User         string `json:"user,omitempty"`
+	Typez        string `json:"type"`
+	Oid          any    `json:"oid,omitempty"` // Order id (int64) or client order id (string)
+	Coin         string `json:"coin,omitempty"`
+	StartTime    int64  `json:"startTime,omitempty"`
+	EndTime      int64  `json:"endTime,omitempty"`
+	VaultAddress string `json:"vaultAddress,omitempty"`
+	Builder      string `json:"builder,omitempty"`
 }
 
 type UserStateRequest struct {
@@ -52,6 +56,7 @@ type Position struct {
 		SinceOpne float64 `json:"sinceOpen,string"`
 		SinceChan float64 `json:"sinceChange,string"`
 	} `json:"cumFunding"`
+	exact positionDecimals // Exact values of the decoded strings
 }
 
 type UserStateSpot struct {
@@ -67,22 +72,23 @@ type SpotAssetPosition struct {
 }
 
 type Order struct {
-	Children         []any   `json:"children,omitempty"`
-	Cloid            string  `json:"cloid,omitempty"`
-	Coin             string  `json:"coin"`
-	IsPositionTpsl   bool    `json:"isPositionTpsl,omitempty"`
-	IsTrigger        bool    `json:"isTrigger,omitempty"`
-	LimitPx          float64 `json:"limitPx,string,omitempty"`
-	Oid              int64   `json:"oid"`
-	OrderType        string  `json:"orderType,omitempty"`
-	OrigSz           float64 `json:"origSz,string,omitempty"`
-	ReduceOnly       bool    `json:"reduceOnly,omitempty"`
-	Side             string  `json:"side"`
-	Sz               float64 `json:"sz,string,omitempty"`
-	Tif              string  `json:"tif,omitempty"`
-	Timestamp        int64   `json:"timestamp"`
-	TriggerCondition string  `json:"triggerCondition,omitempty"`
-	TriggerPx        float64 `json:"triggerPx,string,omitempty"`
+	Children         []any         `json:"children,omitempty"`
+	Cloid            string        `json:"cloid,omitempty"`
+	Coin             string        `json:"coin"`
+	IsPositionTpsl   bool          `json:"isPositionTpsl,omitempty"`
+	IsTrigger        bool          `json:"isTrigger,omitempty"`
+	LimitPx          float64       `json:"limitPx,string,omitempty"`
+	Oid              int64         `json:"oid"`
+	OrderType        string        `json:"orderType,omitempty"`
+	OrigSz           float64       `json:"origSz,string,omitempty"`
+	ReduceOnly       bool          `json:"reduceOnly,omitempty"`
+	Side             string        `json:"side"`
+	Sz               float64       `json:"sz,string,omitempty"`
+	Tif              string        `json:"tif,omitempty"`
+	Timestamp        int64         `json:"timestamp"`
+	TriggerCondition string        `json:"triggerCondition,omitempty"`
+	TriggerPx        float64       `json:"triggerPx,string,omitempty"`
+	exact            orderDecimals // Exact values of the decoded strings
 }
 
 type Leverage struct {
@@ -137,18 +143,69 @@ type OrderFill struct {
 	Tid           int64        `json:"tid"`
 	Time          int64        `json:"time"`
 	Liquidation   *Liquidation `json:"liquidation"`
+	exact         fillDecimals // Exact values of the decoded strings
+}
+
+// Perpetual asset context as returned by metaAndAssetCtxs and the activeAssetCtx subscription
+type PerpAssetCtx struct {
+	Funding      float64      `json:"funding,string"`
+	OpenInterest float64      `json:"openInterest,string"`
+	PrevDayPx    float64      `json:"prevDayPx,string"`
+	DayNtlVlm    float64      `json:"dayNtlVlm,string"`
+	DayBaseVlm   float64      `json:"dayBaseVlm,string"`
+	Premium      float64      `json:"premium,string"` // 0 if there is no premium
+	OraclePx     float64      `json:"oraclePx,string"`
+	MarkPx       float64      `json:"markPx,string"`
+	MidPx        float64      `json:"midPx,string"` // 0 if the book is empty
+	ImpactPxs    StringFloats `json:"impactPxs"`    // Impact bid and ask prices, nil if the book is too thin
+}
+
+// Spot asset context as returned by spotMetaAndAssetCtxs
+type SpotAssetCtx struct {
+	Coin              string  `json:"coin"`
+	PrevDayPx         float64 `json:"prevDayPx,string"`
+	DayNtlVlm         float64 `json:"dayNtlVlm,string"`
+	DayBaseVlm        float64 `json:"dayBaseVlm,string"`
+	MarkPx            float64 `json:"markPx,string"`
+	MidPx             float64 `json:"midPx,string"` // 0 if the book is empty
+	CirculatingSupply float64 `json:"circulatingSupply,string"`
+	TotalSupply       float64 `json:"totalSupply,string"`
+}
+
+// Perpetuals meta and asset contexts, the API returns [meta, [ctx, ...]]
+type MetaAndAssetCtxs struct {
+	Meta      Meta           `json:"meta"`
+	AssetCtxs []PerpAssetCtx `json:"assetCtxs"`
 }
 
-type Context struct {
-	DayNtlVlm    string   `json:"dayNtlVlm"`
-	Funding      string   `json:"funding"`
-	ImpactPxs    []string `json:"impactPxs"`
-	MarkPx       string   `json:"markPx"`
-	MidPx        string   `json:"midPx"`
-	OpenInterest string   `json:"openInterest"`
-	OraclePx     string   `json:"oraclePx"`
-	Premium      string   `json:"premium"`
-	PrevDayPx    string   `json:"prevDayPx"`
+func (m *MetaAndAssetCtxs) UnmarshalJSON(data []byte) error {
+	var raw [2]jsoniter.RawMessage
+	if err := fastJSON.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := fastJSON.Unmarshal(raw[0], &m.Meta); err != nil {
+		return err
+	}
+	return fastJSON.Unmarshal(raw[1], &m.AssetCtxs)
+}
+
+// Perpetual asset meta joined with its context
+type PerpMarket struct {
+	Asset
+	AssetId int          `json:"assetId"`
+	Ctx     PerpAssetCtx `json:"ctx"`
+}
+
+// ByCoin joins the meta and contexts by coin name
+func (m *MetaAndAssetCtxs) ByCoin() map[string]PerpMarket {
+	markets := make(map[string]PerpMarket, len(m.Meta.Universe))
+	for i, asset := range m.Meta.Universe {
+		if i >= len(m.AssetCtxs) {
+			break
+		}
+		markets[asset.Name] = PerpMarket{Asset: asset, AssetId: i, Ctx: m.AssetCtxs[i]}
+	}
+	return markets
 }
 
 type HistoricalFundingRate struct {
@@ -181,8 +238,8 @@ type CandleSnapshotRequest struct {
 }
 
 type CandleSnapshot struct {
-	CloseTime int64   `json:"t"`
-	OpenTime  int64   `json:"T"`
+	OpenTime  int64   `json:"t"`
+	CloseTime int64   `json:"T"`
 	Symbol    string  `json:"s"`
 	Interval  string  `json:"i"`
 	Open      float64 `json:"o,string"`
@@ -211,15 +268,292 @@ type RatesLimits struct {
 	NRequestsCap  int     `json:"nRequestsCap"`
 }
 
-type SpotMetaAndAssetCtxsResponse [2]interface{} // Array of exactly 2 elements
+// Spot meta and asset contexts, the API returns [spotMeta, [ctx, ...]]
+type SpotMetaAndAssetCtxsResponse struct {
+	SpotMeta  SpotMeta       `json:"spotMeta"`
+	AssetCtxs []SpotAssetCtx `json:"assetCtxs"`
+}
+
+func (m *SpotMetaAndAssetCtxsResponse) UnmarshalJSON(data []byte) error {
+	var raw [2]jsoniter.RawMessage
+	if err := fastJSON.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := fastJSON.Unmarshal(raw[0], &m.SpotMeta); err != nil {
+		return err
+	}
+	return fastJSON.Unmarshal(raw[1], &m.AssetCtxs)
+}
+
+// Spot pair meta joined with its context
+type SpotMarket struct {
+	Name    string       `json:"name"` // e.g. "PURR/USDC" or "@107"
+	Index   int          `json:"index"`
+	AssetId int          `json:"assetId"` // 10000 + index
+	Tokens  []int        `json:"tokens"`  // Base and quote token index
+	Ctx     SpotAssetCtx `json:"ctx"`
+}
+
+// ByCoin joins the spot pairs and contexts by pair name
+func (m *SpotMetaAndAssetCtxsResponse) ByCoin() map[string]SpotMarket {
+	ctxs := make(map[string]SpotAssetCtx, len(m.AssetCtxs))
+	for _, ctx := range m.AssetCtxs {
+		ctxs[ctx.Coin] = ctx
+	}
+	markets := make(map[string]SpotMarket, len(m.SpotMeta.Universe))
+	for _, pair := range m.SpotMeta.Universe {
+		ctx, exists := ctxs[pair.Name]
+		if !exists {
+			continue
+		}
+		markets[pair.Name] = SpotMarket{
+			Name:    pair.Name,
+			Index:   pair.Index,
+			AssetId: 10000 + pair.Index,
+			Tokens:  pair.Tokens,
+			Ctx:     ctx,
+		}
+	}
+	return markets
+}
+
+type OrderStatusResponse struct {
+	Status string           `json:"status"` // "order" or "unknownOid"
+	Order  *OrderStatusInfo `json:"order,omitempty"`
+}
+
+type OrderStatusInfo struct {
+	Order           Order  `json:"order"`
+	Status          string `json:"status"` // "open", "filled", "canceled", "triggered", "rejected", "marginCanceled", ...
+	StatusTimestamp int64  `json:"statusTimestamp"`
+}
+
+type UserFees struct {
+	DailyUserVlm           []DailyUserVolume `json:"dailyUserVlm"`
+	FeeSchedule            FeeSchedule       `json:"feeSchedule"`
+	UserCrossRate          float64           `json:"userCrossRate,string"`
+	UserAddRate            float64           `json:"userAddRate,string"`
+	UserSpotCrossRate      float64           `json:"userSpotCrossRate,string"`
+	UserSpotAddRate        float64           `json:"userSpotAddRate,string"`
+	ActiveReferralDiscount float64           `json:"activeReferralDiscount,string"`
+	ActiveStakingDiscount  struct {
+		BpsOfMaxSupply float64 `json:"bpsOfMaxSupply,string"`
+		Discount       float64 `json:"discount,string"`
+	} `json:"activeStakingDiscount"`
+}
+
+type DailyUserVolume struct {
+	Date      string  `json:"date"`
+	UserCross float64 `json:"userCross,string"`
+	UserAdd   float64 `json:"userAdd,string"`
+	Exchange  float64 `json:"exchange,string"`
+}
+
+type FeeSchedule struct {
+	Cross            float64 `json:"cross,string"`
+	Add              float64 `json:"add,string"`
+	SpotCross        float64 `json:"spotCross,string"`
+	SpotAdd          float64 `json:"spotAdd,string"`
+	ReferralDiscount float64 `json:"referralDiscount,string"`
+	Tiers            struct {
+		Vip []struct {
+			NtlCutoff float64 `json:"ntlCutoff,string"`
+			Cross     float64 `json:"cross,string"`
+			Add       float64 `json:"add,string"`
+			SpotCross float64 `json:"spotCross,string"`
+			SpotAdd   float64 `json:"spotAdd,string"`
+		} `json:"vip"`
+		Mm []struct {
+			MakerFractionCutoff float64 `json:"makerFractionCutoff,string"`
+			Add                 float64 `json:"add,string"`
+		} `json:"mm"`
+	} `json:"tiers"`
+}
+
+// Predicted funding of a coin on a single venue
+type PredictedFunding struct {
+	Venue                string  `json:"venue"` // "HlPerp", "BinPerp", "BybitPerp", ...
+	FundingRate          float64 `json:"fundingRate,string"`
+	NextFundingTime      int64   `json:"nextFundingTime"`
+	FundingIntervalHours int     `json:"fundingIntervalHours,omitempty"`
+}
+
+// Predicted fundings of a coin on all venues
+// The API returns [coin, [[venue, {fundingRate, nextFundingTime}], ...]]
+type CoinPredictedFundings struct {
+	Coin   string             `json:"coin"`
+	Venues []PredictedFunding `json:"venues"`
+}
+
+func (c *CoinPredictedFundings) UnmarshalJSON(data []byte) error {
+	var raw [2]jsoniter.RawMessage
+	if err := fastJSON.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := fastJSON.Unmarshal(raw[0], &c.Coin); err != nil {
+		return err
+	}
+	var venues [][2]jsoniter.RawMessage
+	if err := fastJSON.Unmarshal(raw[1], &venues); err != nil {
+		return err
+	}
+	c.Venues = make([]PredictedFunding, 0, len(venues))
+	for _, venue := range venues {
+		var funding PredictedFunding
+		// Venues without a prediction have a null entry
+		if len(venue[1]) == 0 || string(venue[1]) == "null" {
+			continue
+		}
+		if err := fastJSON.Unmarshal(venue[1], &funding); err != nil {
+			return err
+		}
+		if err := fastJSON.Unmarshal(venue[0], &funding.Venue); err != nil {
+			return err
+		}
+		c.Venues = append(c.Venues, funding)
+	}
+	return nil
+}
+
+// Portfolio history by period ("day", "week", "month", "allTime", "perpDay", ...)
+// The API returns [[period, {...}], ...]
+type Portfolio map[string]PortfolioPeriod
+
+func (p *Portfolio) UnmarshalJSON(data []byte) error {
+	var raw [][2]jsoniter.RawMessage
+	if err := fastJSON.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	portfolio := make(Portfolio, len(raw))
+	for _, entry := range raw {
+		var period string
+		if err := fastJSON.Unmarshal(entry[0], &period); err != nil {
+			return err
+		}
+		var history PortfolioPeriod
+		if err := fastJSON.Unmarshal(entry[1], &history); err != nil {
+			return err
+		}
+		portfolio[period] = history
+	}
+	*p = portfolio
+	return nil
+}
+
+type PortfolioPeriod struct {
+	AccountValueHistory []TimeValue `json:"accountValueHistory"`
+	PnlHistory          []TimeValue `json:"pnlHistory"`
+	Vlm                 float64     `json:"vlm,string"`
+}
+
+// Point of a time series, the API returns [time, "value"]
+type TimeValue struct {
+	Time  int64   `json:"time"`
+	Value float64 `json:"value"`
+}
+
+func (v *TimeValue) UnmarshalJSON(data []byte) error {
+	var raw [2]jsoniter.Number
+	if err := fastJSON.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	time, err := raw[0].Int64()
+	if err != nil {
+		return err
+	}
+	value, err := raw[1].Float64()
+	if err != nil {
+		return err
+	}
+	v.Time, v.Value = time, value
+	return nil
+}
+
+type SubAccount struct {
+	Name               string        `json:"name"`
+	SubAccountUser     string        `json:"subAccountUser"`
+	Master             string        `json:"master"`
+	ClearinghouseState UserState     `json:"clearinghouseState"`
+	SpotState          UserStateSpot `json:"spotState"`
+}
+
+type VaultDetails struct {
+	Name                  string          `json:"name"`
+	VaultAddress          string          `json:"vaultAddress"`
+	Leader                string          `json:"leader"`
+	Description           string          `json:"description"`
+	Portfolio             Portfolio       `json:"portfolio"`
+	Apr                   float64         `json:"apr"`
+	FollowerState         *VaultFollower  `json:"followerState"` // nil if the user does not follow the vault
+	LeaderFraction        float64         `json:"leaderFraction"`
+	LeaderCommission      float64         `json:"leaderCommission"`
+	Followers             []VaultFollower `json:"followers"`
+	MaxDistributable      float64         `json:"maxDistributable"`
+	MaxWithdrawable       float64         `json:"maxWithdrawable"`
+	IsClosed              bool            `json:"isClosed"`
+	AllowDeposits         bool            `json:"allowDeposits"`
+	AlwaysCloseOnWithdraw bool            `json:"alwaysCloseOnWithdraw"`
+	Relationship          struct {
+		Type string `json:"type"` // "normal", "parent" or "child"
+		Data struct {
+			ChildAddresses []string `json:"childAddresses,omitempty"`
+		} `json:"data"`
+	} `json:"relationship"`
+}
+
+type VaultFollower struct {
+	User           string  `json:"user"`
+	VaultEquity    float64 `json:"vaultEquity,string"`
+	Pnl            float64 `json:"pnl,string"`
+	AllTimePnl     float64 `json:"allTimePnl,string"`
+	DaysFollowing  int     `json:"daysFollowing"`
+	VaultEntryTime int64   `json:"vaultEntryTime"`
+	LockupUntil    int64   `json:"lockupUntil"`
+}
+
+type VaultEquity struct {
+	VaultAddress         string  `json:"vaultAddress"`
+	Equity               float64 `json:"equity,string"`
+	LockedUntilTimestamp int64   `json:"lockedUntilTimestamp"`
+}
+
+type Referral struct {
+	ReferredBy *struct {
+		Referrer string `json:"referrer"`
+		Code     string `json:"code"`
+	} `json:"referredBy"`
+	CumVlm           float64 `json:"cumVlm,string"`
+	UnclaimedRewards float64 `json:"unclaimedRewards,string"`
+	ClaimedRewards   float64 `json:"claimedRewards,string"`
+	BuilderRewards   float64 `json:"builderRewards,string"`
+	ReferrerState    struct {
+		Stage string `json:"stage"` // "ready", "needToCreateCode" or "needToTrade"
+		Data  struct {
+			Code           string          `json:"code,omitempty"`
+			ReferralStates []ReferralState `json:"referralStates,omitempty"`
+			Required       float64         `json:"required,string,omitempty"` // Volume required to create a code
+		} `json:"data"`
+	} `json:"referrerState"`
+	RewardHistory []ReferralReward `json:"rewardHistory"`
+}
+
+type ReferralState struct {
+	User                         string  `json:"user"`
+	CumVlm                       float64 `json:"cumVlm,string"`
+	CumRewardedFeesSinceReferred float64 `json:"cumRewardedFeesSinceReferred,string"`
+	CumFeesRewardedToReferrer    float64 `json:"cumFeesRewardedToReferrer,string"`
+	TimeJoined                   int64   `json:"timeJoined"`
+}
+
+type ReferralReward struct {
+	Earned      float64 `json:"earned,string"`
+	Vlm         float64 `json:"vlm,string"`
+	ReferralVlm float64 `json:"referralVlm,string"`
+	Time        int64   `json:"time"`
+}
 
-type Market struct {
-	PrevDayPx         string `json:"prevDayPx,omitempty"`
-	DayNtlVlm         string `json:"dayNtlVlm,omitempty"`
-	MarkPx            string `json:"markPx,omitempty"`
-	MidPx             string `json:"midPx,omitempty"`
-	CirculatingSupply string `json:"circulatingSupply,omitempty"`
-	Coin              string `json:"coin,omitempty"`
-	TotalSupply       string `json:"totalSupply,omitempty"`
-	DayBaseVlm        string `json:"dayBaseVlm,omitempty"`
+type Delegation struct {
+	Validator            string  `json:"validator"`
+	Amount               float64 `json:"amount,string"`
+	LockedUntilTimestamp int64   `json:"lockedUntilTimestamp"`
 }
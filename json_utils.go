@@ -128,3 +128,28 @@ func (p *PreMarshaledMessage) Bytes() []byte {
 func (p *PreMarshaledMessage) String() string {
 	return string(p.data)
 }
+
+// StringFloats is a list of numbers the API encodes as JSON strings, e.g. ["1.5","2"]
+type StringFloats []float64
+
+// UnmarshalJSON accepts both string and number elements
+func (f *StringFloats) UnmarshalJSON(data []byte) error {
+	var raw []jsoniter.Number
+	if err := fastJSON.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*f = nil
+		return nil
+	}
+	values := make(StringFloats, len(raw))
+	for i, number := range raw {
+		value, err := number.Float64()
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+	*f = values
+	return nil
+}
@@ -0,0 +1,385 @@
+package hyperliquid
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CoinLedger summarizes the account activity of a single coin
+type CoinLedger struct {
+	Coin            string             `json:"coin"`
+	Fills           int                `json:"fills"`
+	Volume          float64            `json:"volume"` // Traded notional
+	RealizedPnl     float64            `json:"realizedPnl"`
+	Fees            float64            `json:"fees"`                // Fees paid in FeeToken
+	FeeToken        string             `json:"feeToken"`            // Fee token of the first fill
+	OtherFees       map[string]float64 `json:"otherFees,omitempty"` // Fees paid in other tokens, e.g. the bought token of spot buys
+	FundingPaid     float64            `json:"fundingPaid"`         // Funding paid, as a positive amount
+	FundingReceived float64            `json:"fundingReceived"`     // Funding received, as a positive amount
+	NetFunding      float64            `json:"netFunding"`
+	NetPnl          float64            `json:"netPnl"` // RealizedPnl - USDC fees + NetFunding
+}
+
+// DailyLedger summarizes the account activity of a single UTC day
+type DailyLedger struct {
+	Date         string  `json:"date"` // YYYY-MM-DD
+	RealizedPnl  float64 `json:"realizedPnl"`
+	Fees         float64 `json:"fees"`
+	NetFunding   float64 `json:"netFunding"`
+	NetTransfers float64 `json:"netTransfers"`
+	NetPnl       float64 `json:"netPnl"`
+}
+
+// AccountLedger combines fills, funding and ledger updates of an account into
+// per coin and per day summaries. Amounts are in USDC unless stated otherwise.
+//
+// Totals, Daily and Reconcile cover the perp account. Spot fills ("PURR/USDC", "@107") are
+// listed in Coins and summed in SpotTotals, they do not change the perp account value.
+type AccountLedger struct {
+	Address   string `json:"address"`
+	StartTime int64  `json:"startTime"`
+	EndTime   int64  `json:"endTime"`
+
+	Coins      map[string]*CoinLedger  `json:"coins"`
+	Daily      map[string]*DailyLedger `json:"daily"`
+	Totals     CoinLedger              `json:"totals"`     // Perp coins, fees only include fees paid in USDC
+	SpotTotals CoinLedger              `json:"spotTotals"` // Spot coins, fees only include fees paid in USDC
+
+	Deposits         []Deposit    `json:"deposits"`
+	Withdrawals      []Withdrawal `json:"withdrawals"`
+	TotalDeposits    float64      `json:"totalDeposits"`
+	TotalWithdrawals float64      `json:"totalWithdrawals"`
+	WithdrawalFees   float64      `json:"withdrawalFees"`
+	OtherTransfers   float64      `json:"otherTransfers"` // Internal, sub account, spot/perp class and vault transfers
+	NetTransfers     float64      `json:"netTransfers"`   // Net USDC moved in (+) or out (-) of the perp account
+	Unclassified     []string     `json:"unclassified,omitempty"`
+}
+
+// LedgerReconciliation compares the equity computed from the ledger with the account value reported by the exchange
+type LedgerReconciliation struct {
+	OpeningEquity        float64 `json:"openingEquity"`
+	OpeningUnrealizedPnl float64 `json:"openingUnrealizedPnl"`
+	RealizedPnl          float64 `json:"realizedPnl"`
+	Fees                 float64 `json:"fees"`
+	NetFunding           float64 `json:"netFunding"`
+	NetTransfers         float64 `json:"netTransfers"`
+	UnrealizedPnl        float64 `json:"unrealizedPnl"`
+	ComputedEquity       float64 `json:"computedEquity"`
+	AccountValue         float64 `json:"accountValue"`
+	Difference           float64 `json:"difference"` // AccountValue - ComputedEquity
+}
+
+// NewAccountLedger returns an empty ledger for address covering startTime to endTime (in milliseconds)
+func NewAccountLedger(address string, startTime int64, endTime int64) *AccountLedger {
+	return &AccountLedger{
+		Address:     address,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Coins:       make(map[string]*CoinLedger),
+		Daily:       make(map[string]*DailyLedger),
+		Totals:      CoinLedger{Coin: "TOTAL", FeeToken: "USDC"},
+		SpotTotals:  CoinLedger{Coin: "SPOT", FeeToken: "USDC"},
+		Deposits:    []Deposit{},
+		Withdrawals: []Withdrawal{},
+	}
+}
+
+// BuildAccountLedger pulls the complete fill, funding and ledger history of address
+// between startTime and endTime (in milliseconds) and combines it into an AccountLedger
+func (api *InfoAPI) BuildAccountLedger(address string, startTime int64, endTime int64) (*AccountLedger, error) {
+	ledger := NewAccountLedger(address, startTime, endTime)
+	for fill, err := range api.IterUserFills(address, startTime, endTime) {
+		if err != nil {
+			return nil, err
+		}
+		ledger.AddFill(fill)
+	}
+	for update, err := range api.IterFundingUpdates(address, startTime, endTime) {
+		if err != nil {
+			return nil, err
+		}
+		ledger.AddFunding(update)
+	}
+	for update, err := range api.IterNonFundingUpdates(address, startTime, endTime) {
+		if err != nil {
+			return nil, err
+		}
+		ledger.AddNonFunding(update)
+	}
+	return ledger, nil
+}
+
+// BuildAccountLedgerForAccount is the same as BuildAccountLedger but user is set to the account address
+func (api *InfoAPI) BuildAccountLedgerForAccount(startTime int64, endTime int64) (*AccountLedger, error) {
+	return api.BuildAccountLedger(api.AccountAddress(), startTime, endTime)
+}
+
+func (ledger *AccountLedger) coin(name string) *CoinLedger {
+	entry, exists := ledger.Coins[name]
+	if !exists {
+		entry = &CoinLedger{Coin: name}
+		ledger.Coins[name] = entry
+	}
+	return entry
+}
+
+func (ledger *AccountLedger) day(timestamp int64) *DailyLedger {
+	date := time.UnixMilli(timestamp).UTC().Format(time.DateOnly)
+	entry, exists := ledger.Daily[date]
+	if !exists {
+		entry = &DailyLedger{Date: date}
+		ledger.Daily[date] = entry
+	}
+	return entry
+}
+
+// isSpotCoin reports whether coin is a spot pair, named "@<index>" or "BASE/QUOTE" in fills
+func isSpotCoin(coin string) bool {
+	return strings.HasPrefix(coin, "@") || strings.Contains(coin, "/")
+}
+
+// AddFill adds a fill to the ledger
+func (ledger *AccountLedger) AddFill(fill OrderFill) {
+	// Fees of spot buys are paid in the bought token and do not reduce USDC equity
+	usdcFee := 0.0
+	if fill.FeeToken == "" || fill.FeeToken == "USDC" {
+		usdcFee = fill.Fee
+	}
+
+	entry := ledger.coin(fill.Coin)
+	entry.Fills++
+	entry.Volume += fill.Px * fill.Sz
+	entry.RealizedPnl += fill.ClosedPnl
+	if entry.FeeToken == "" {
+		entry.FeeToken = fill.FeeToken
+	}
+	if fill.FeeToken == entry.FeeToken {
+		entry.Fees += fill.Fee
+	} else {
+		if entry.OtherFees == nil {
+			entry.OtherFees = make(map[string]float64)
+		}
+		entry.OtherFees[fill.FeeToken] += fill.Fee
+	}
+	entry.NetPnl += fill.ClosedPnl - usdcFee
+
+	if isSpotCoin(fill.Coin) {
+		ledger.SpotTotals.Fills++
+		ledger.SpotTotals.Volume += fill.Px * fill.Sz
+		ledger.SpotTotals.RealizedPnl += fill.ClosedPnl
+		ledger.SpotTotals.Fees += usdcFee
+		ledger.SpotTotals.NetPnl += fill.ClosedPnl - usdcFee
+		return
+	}
+
+	ledger.Totals.Fills++
+	ledger.Totals.Volume += fill.Px * fill.Sz
+	ledger.Totals.RealizedPnl += fill.ClosedPnl
+	ledger.Totals.Fees += usdcFee
+	ledger.Totals.NetPnl += fill.ClosedPnl - usdcFee
+
+	day := ledger.day(fill.Time)
+	day.RealizedPnl += fill.ClosedPnl
+	day.Fees += usdcFee
+	day.NetPnl += fill.ClosedPnl - usdcFee
+}
+
+// AddFunding adds a funding payment to the ledger.
+// Negative amounts were paid by the account, positive amounts were received.
+func (ledger *AccountLedger) AddFunding(update FundingUpdate) {
+	amount, err := strconv.ParseFloat(update.Delta.UsdcAmount, 64)
+	if err != nil {
+		ledger.Unclassified = append(ledger.Unclassified, "funding:"+update.Hash)
+		return
+	}
+	for _, entry := range []*CoinLedger{ledger.coin(update.Delta.Asset), &ledger.Totals} {
+		if amount < 0 {
+			entry.FundingPaid -= amount
+		} else {
+			entry.FundingReceived += amount
+		}
+		entry.NetFunding += amount
+		entry.NetPnl += amount
+	}
+	day := ledger.day(update.Time)
+	day.NetFunding += amount
+	day.NetPnl += amount
+}
+
+// AddNonFunding adds a deposit, withdrawal or transfer to the ledger.
+// Only movements in and out of the perp account are counted in NetTransfers.
+func (ledger *AccountLedger) AddNonFunding(update NonFundingUpdate) {
+	delta := update.Delta
+	outgoing := strings.EqualFold(delta.User, ledger.Address)
+
+	var amount float64
+	switch delta.Type {
+	case "deposit":
+		amount = delta.Usdc
+		ledger.TotalDeposits += amount
+		ledger.Deposits = append(ledger.Deposits, Deposit{
+			Hash:   update.Hash,
+			Time:   update.Time,
+			Amount: delta.Usdc,
+		})
+	case "withdraw":
+		// The withdrawn usdc amount already includes the fee
+		amount = -delta.Usdc
+		ledger.TotalWithdrawals += delta.Usdc
+		ledger.WithdrawalFees += delta.Fee
+		ledger.Withdrawals = append(ledger.Withdrawals, Withdrawal{
+			Time:   update.Time,
+			Hash:   update.Hash,
+			Amount: delta.Usdc,
+			Fee:    delta.Fee,
+			Nonce:  delta.Nonce,
+		})
+	case "internalTransfer", "subAccountTransfer":
+		if outgoing {
+			amount = -(delta.Usdc + delta.Fee)
+		} else {
+			amount = delta.Usdc
+		}
+		ledger.OtherTransfers += amount
+	case "accountClassTransfer":
+		amount = delta.Usdc
+		if !delta.ToPerp {
+			amount = -amount
+		}
+		ledger.OtherTransfers += amount
+	case "vaultDeposit":
+		amount = -delta.Usdc
+		ledger.OtherTransfers += amount
+	case "vaultWithdraw":
+		amount = delta.NetWithdrawnUsd
+		ledger.OtherTransfers += amount
+	default:
+		// Spot transfers, rewards, liquidations, ... do not move perp USDC or are covered by fills
+		ledger.Unclassified = append(ledger.Unclassified, delta.Type+":"+update.Hash)
+		return
+	}
+	ledger.NetTransfers += amount
+	ledger.day(update.Time).NetTransfers += amount
+}
+
+// Reconcile computes the expected equity at the end of the ledger and compares it with
+// state.MarginSummary.AccountValue. openingEquity and openingUnrealizedPnl describe the account
+// at the ledger start time (both zero if the ledger starts before the first deposit).
+func (ledger *AccountLedger) Reconcile(openingEquity float64, openingUnrealizedPnl float64, state *UserState) LedgerReconciliation {
+	var unrealizedPnl float64
+	for _, position := range state.AssetPositions {
+		unrealizedPnl += position.Position.UnrealizedPnl
+	}
+	result := LedgerReconciliation{
+		OpeningEquity:        openingEquity,
+		OpeningUnrealizedPnl: openingUnrealizedPnl,
+		RealizedPnl:          ledger.Totals.RealizedPnl,
+		Fees:                 ledger.Totals.Fees,
+		NetFunding:           ledger.Totals.NetFunding,
+		NetTransfers:         ledger.NetTransfers,
+		UnrealizedPnl:        unrealizedPnl,
+		AccountValue:         state.MarginSummary.AccountValue,
+	}
+	result.ComputedEquity = openingEquity + result.RealizedPnl - result.Fees + result.NetFunding +
+		result.NetTransfers + (unrealizedPnl - openingUnrealizedPnl)
+	result.Difference = result.AccountValue - result.ComputedEquity
+	return result
+}
+
+// WriteJSON writes the ledger as JSON
+func (ledger *AccountLedger) WriteJSON(w io.Writer) error {
+	// The standard library compatible config sorts map keys, which keeps reports stable
+	data, err := CompatibleMarshal(ledger)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteCSV writes one row per coin followed by the TOTAL row of the perp coins
+// and the SPOT row of the spot coins
+func (ledger *AccountLedger) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"coin", "fills", "volume", "realized_pnl", "fees", "fee_token", "funding_paid", "funding_received", "net_funding", "net_pnl"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	coins := make([]string, 0, len(ledger.Coins))
+	for coin := range ledger.Coins {
+		coins = append(coins, coin)
+	}
+	sort.Strings(coins)
+
+	rows := make([]*CoinLedger, 0, len(coins)+1)
+	for _, coin := range coins {
+		rows = append(rows, ledger.Coins[coin])
+	}
+	rows = append(rows, &ledger.Totals, &ledger.SpotTotals)
+
+	for _, entry := range rows {
+		err := writer.Write([]string{
+			entry.Coin,
+			strconv.Itoa(entry.Fills),
+			formatLedgerAmount(entry.Volume),
+			formatLedgerAmount(entry.RealizedPnl),
+			formatLedgerAmount(entry.Fees),
+			entry.FeeToken,
+			formatLedgerAmount(entry.FundingPaid),
+			formatLedgerAmount(entry.FundingReceived),
+			formatLedgerAmount(entry.NetFunding),
+			formatLedgerAmount(entry.NetPnl),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteDailyCSV writes one row per UTC day in chronological order
+func (ledger *AccountLedger) WriteDailyCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "realized_pnl", "fees", "net_funding", "net_transfers", "net_pnl"}); err != nil {
+		return err
+	}
+
+	dates := make([]string, 0, len(ledger.Daily))
+	for date := range ledger.Daily {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		day := ledger.Daily[date]
+		err := writer.Write([]string{
+			day.Date,
+			formatLedgerAmount(day.RealizedPnl),
+			formatLedgerAmount(day.Fees),
+			formatLedgerAmount(day.NetFunding),
+			formatLedgerAmount(day.NetTransfers),
+			formatLedgerAmount(day.NetPnl),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatLedgerAmount formats an amount rounded to 8 decimals without trailing zeros
+func formatLedgerAmount(x float64) string {
+	rounded := math.Round(x*1e8) / 1e8
+	if rounded == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
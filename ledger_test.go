@@ -0,0 +1,103 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAccountLedger tests combining fills, funding and transfers and reconciling the result
+func TestAccountLedger(t *testing.T) {
+	const address = "0x0000000000000000000000000000000000000001"
+	day := (24 * time.Hour).Milliseconds()
+	ledger := NewAccountLedger(address, 0, 2*day)
+
+	ledger.AddNonFunding(NonFundingUpdate{Hash: "0x1", Time: 1, Delta: NonFundingDelta{Type: "deposit", Usdc: 1000}})
+	ledger.AddFill(OrderFill{Coin: "BTC", Px: 100, Sz: 2, Fee: 0.5, FeeToken: "USDC", Tid: 1, Time: 10})
+	ledger.AddFill(OrderFill{Coin: "BTC", Px: 110, Sz: 2, Fee: 0.5, FeeToken: "USDC", ClosedPnl: 20, Tid: 2, Time: day + 10})
+	ledger.AddFill(OrderFill{Coin: "PURR/USDC", Px: 1, Sz: 10, Fee: 0.1, FeeToken: "PURR", Tid: 3, Time: 20})
+	ledger.AddFunding(FundingUpdate{Hash: "0x2", Time: 30, Delta: FundingDelta{Asset: "BTC", UsdcAmount: "-1.5"}})
+	ledger.AddFunding(FundingUpdate{Hash: "0x3", Time: day + 30, Delta: FundingDelta{Asset: "BTC", UsdcAmount: "0.5"}})
+	ledger.AddNonFunding(NonFundingUpdate{Hash: "0x4", Time: day + 40, Delta: NonFundingDelta{Type: "withdraw", Usdc: 100, Fee: 1}})
+	ledger.AddNonFunding(NonFundingUpdate{Hash: "0x5", Time: day + 50, Delta: NonFundingDelta{
+		Type: "internalTransfer", Usdc: 50, Fee: 1, User: address, Destination: "0x2",
+	}})
+	ledger.AddNonFunding(NonFundingUpdate{Hash: "0x6", Time: day + 60, Delta: NonFundingDelta{Type: "spotGenesis"}})
+
+	btc := ledger.Coins["BTC"]
+	if btc.Fills != 2 || btc.Volume != 420 || btc.RealizedPnl != 20 || btc.Fees != 1 {
+		t.Errorf("Unexpected BTC ledger: %+v", btc)
+	}
+	if btc.FundingPaid != 1.5 || btc.FundingReceived != 0.5 || btc.NetFunding != -1 || btc.NetPnl != 18 {
+		t.Errorf("Unexpected BTC funding: %+v", btc)
+	}
+	// PURR fees are paid in PURR and do not count towards USDC totals
+	if ledger.Totals.Fees != 1 || ledger.Coins["PURR/USDC"].Fees != 0.1 {
+		t.Errorf("Unexpected fee totals: %+v", ledger.Totals)
+	}
+	if ledger.NetTransfers != 1000-100-51 || ledger.TotalDeposits != 1000 || ledger.TotalWithdrawals != 100 {
+		t.Errorf("Unexpected transfers: net %v deposits %v withdrawals %v", ledger.NetTransfers, ledger.TotalDeposits, ledger.TotalWithdrawals)
+	}
+	if len(ledger.Unclassified) != 1 {
+		t.Errorf("Expected 1 unclassified update, got %v", ledger.Unclassified)
+	}
+	if len(ledger.Daily) != 2 || ledger.Daily["1970-01-02"].NetPnl != 20-0.5+0.5 {
+		t.Errorf("Unexpected daily summaries: %+v", ledger.Daily)
+	}
+
+	state := &UserState{
+		AssetPositions: []AssetPosition{{Position: Position{Coin: "BTC", UnrealizedPnl: 5}}},
+		MarginSummary:  MarginSummary{AccountValue: 872},
+	}
+	result := ledger.Reconcile(0, 0, state)
+	// 1000 - 151 + 20 - 1 - 1 + 5
+	if math.Abs(result.ComputedEquity-872) > 1e-9 || math.Abs(result.Difference) > 1e-9 {
+		t.Errorf("Unexpected reconciliation: %+v", result)
+	}
+
+	var report bytes.Buffer
+	if err := ledger.WriteCSV(&report); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[1], "BTC,2,420,20,1,USDC,1.5,0.5,-1,18") || !strings.HasPrefix(lines[3], "TOTAL,") || !strings.HasPrefix(lines[4], "SPOT,1,10,") {
+		t.Errorf("Unexpected CSV report:\n%s", report.String())
+	}
+	report.Reset()
+	if err := ledger.WriteJSON(&report); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if !strings.Contains(report.String(), `"netTransfers":849`) {
+		t.Errorf("Unexpected JSON report: %s", report.String())
+	}
+}
+
+// TestAccountLedgerSpot tests that spot fills are kept out of the perp reconciliation
+func TestAccountLedgerSpot(t *testing.T) {
+	ledger := NewAccountLedger("0x0000000000000000000000000000000000000001", 0, 1000)
+	ledger.AddNonFunding(NonFundingUpdate{Hash: "0x1", Time: 1, Delta: NonFundingDelta{Type: "deposit", Usdc: 1000}})
+	ledger.AddFill(OrderFill{Coin: "ETH", Px: 2000, Sz: 1, Fee: 1, FeeToken: "USDC", ClosedPnl: 10, Time: 2})
+	// Spot buy pays its fee in PURR, the sell in USDC
+	ledger.AddFill(OrderFill{Coin: "PURR/USDC", Px: 1, Sz: 100, Fee: 0.05, FeeToken: "PURR", Time: 3})
+	ledger.AddFill(OrderFill{Coin: "PURR/USDC", Px: 1.2, Sz: 99, Fee: 0.03, FeeToken: "USDC", ClosedPnl: 19.8, Time: 4})
+	ledger.AddFill(OrderFill{Coin: "@107", Px: 30, Sz: 1, Fee: 0.01, FeeToken: "USDC", Time: 5})
+
+	purr := ledger.Coins["PURR/USDC"]
+	if purr.FeeToken != "PURR" || purr.Fees != 0.05 || purr.OtherFees["USDC"] != 0.03 {
+		t.Errorf("Expected fees per token, got %+v", purr)
+	}
+	if ledger.Totals.Fills != 1 || ledger.Totals.Volume != 2000 || ledger.Totals.RealizedPnl != 10 || ledger.Totals.Fees != 1 {
+		t.Errorf("Expected perp totals without spot fills, got %+v", ledger.Totals)
+	}
+	spot := ledger.SpotTotals
+	if spot.Fills != 3 || math.Abs(spot.Volume-248.8) > 1e-9 || spot.RealizedPnl != 19.8 || math.Abs(spot.Fees-0.04) > 1e-9 {
+		t.Errorf("Unexpected spot totals: %+v", spot)
+	}
+
+	state := &UserState{MarginSummary: MarginSummary{AccountValue: 1009}}
+	if result := ledger.Reconcile(0, 0, state); math.Abs(result.Difference) > 1e-9 {
+		t.Errorf("Expected spot activity not to affect the perp reconciliation, got %+v", result)
+	}
+}
@@ -0,0 +1,194 @@
+package hyperliquid
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// REDACTED replaces sensitive values in log records
+const REDACTED = "[REDACTED]"
+
+// Attribute keys of sensitive values, compared case-insensitively
+var sensitiveLogKeys = map[string]bool{
+	"signature":   true,
+	"privatekey":  true,
+	"private_key": true,
+	"secret":      true,
+}
+
+// sensitiveJSONPattern matches signatures and keys in logged JSON payloads
+var sensitiveJSONPattern = regexp.MustCompile(`"(signature|privateKey|private_key|secret)"\s*:\s*(\{[^{}]*\}|"[^"]*")`)
+
+// debugLogger is used by clients in debug mode without a logger set with SetLogger
+var debugLogger = slog.New(NewRedactingHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+// defaultLogger is used by clients without a logger set with SetLogger, it logs to slog.Default
+var defaultLogger = slog.New(NewRedactingHandler(defaultHandler{}))
+
+// defaultHandler forwards records to the handler of slog.Default at the time they are logged
+type defaultHandler struct{}
+
+func (defaultHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+func (defaultHandler) Handle(ctx context.Context, record slog.Record) error {
+	return slog.Default().Handler().Handle(ctx, record)
+}
+
+func (h defaultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return slog.Default().Handler().WithAttrs(attrs)
+}
+
+func (h defaultHandler) WithGroup(name string) slog.Handler {
+	return slog.Default().Handler().WithGroup(name)
+}
+
+// redactingHandler removes signatures and private keys from log records before passing
+// them to the next handler
+type redactingHandler struct {
+	next slog.Handler
+}
+
+// NewRedactingHandler returns a handler that passes records to next with signatures and keys
+// redacted: attributes named signature, privateKey or secret, values of type RsvSignature,
+// *PKeyManager or *ecdsa.PrivateKey, and the same fields in JSON payloads logged as strings or bytes.
+// Loggers set with SetLogger are wrapped automatically.
+func NewRedactingHandler(next slog.Handler) slog.Handler {
+	if h, ok := next.(*redactingHandler); ok {
+		return h
+	}
+	return &redactingHandler{next: next}
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, redactJSON(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name)}
+}
+
+// redactAttr returns attr with sensitive values replaced by REDACTED
+func redactAttr(attr slog.Attr) slog.Attr {
+	if sensitiveLogKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, REDACTED)
+	}
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, redactJSON(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = redactAttr(member)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		switch v := value.Any().(type) {
+		case RsvSignature, *RsvSignature, *PKeyManager, PKeyManager, *ecdsa.PrivateKey:
+			return slog.String(attr.Key, REDACTED)
+		case []byte:
+			return slog.String(attr.Key, redactJSON(string(v)))
+		case error:
+			return slog.String(attr.Key, redactJSON(v.Error()))
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// redactJSON replaces signatures and keys in a JSON payload
+func redactJSON(s string) string {
+	if !strings.Contains(s, `"`) {
+		return s
+	}
+	return sensitiveJSONPattern.ReplaceAllString(s, `"$1":"`+REDACTED+`"`)
+}
+
+// clientLogger returns logger, or the logger of clients without one
+func clientLogger(logger *slog.Logger, debug bool) *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	if debug {
+		return debugLogger
+	}
+	return defaultLogger
+}
+
+// redactingLogger returns logger with a redacting handler, nil for nil
+func redactingLogger(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return nil
+	}
+	if _, ok := logger.Handler().(*redactingHandler); ok {
+		return logger
+	}
+	return slog.New(NewRedactingHandler(logger.Handler()))
+}
+
+// logOrderStatuses logs the outcome of every order of a bulk order request
+func (api *ExchangeAPI) logOrderStatuses(requests []OrderRequest, response *OrderResponse, err error) {
+	logger := api.Logger()
+	if err != nil {
+		logger.Warn("Order request failed", "orders", len(requests), "err", err)
+		return
+	}
+	for i, status := range response.Response.Data.Statuses {
+		if i >= len(requests) {
+			break
+		}
+		attrs := []any{"coin", requests[i].Coin, "cloid", requests[i].Cloid}
+		switch {
+		case status.Error != "":
+			logger.Warn("Order rejected", append(attrs, "err", status.Error)...)
+		case status.Filled.OrderId != 0:
+			logger.Debug("Order filled", append(attrs, "oid", status.Filled.OrderId,
+				"avgPx", status.Filled.AvgPx, "totalSz", status.Filled.TotalSz)...)
+		case status.Resting.OrderId != 0:
+			logger.Debug("Order resting", append(attrs, "oid", status.Resting.OrderId)...)
+		}
+	}
+}
+
+// logCancelStatuses logs the outcome of every cancel of a bulk cancel request,
+// attrs returns the attributes identifying cancel i
+func (api *ExchangeAPI) logCancelStatuses(count int, attrs func(i int) []any, response *OrderResponse, err error) {
+	logger := api.Logger()
+	if err != nil {
+		logger.Warn("Cancel request failed", "cancels", count, "err", err)
+		return
+	}
+	for i, status := range response.Response.Data.Statuses {
+		if i >= count {
+			break
+		}
+		if status.Error != "" {
+			logger.Warn("Cancel rejected", append(attrs(i), "err", status.Error)...)
+		} else {
+			logger.Debug("Order canceled", attrs(i)...)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestLogger returns a JSON logger writing debug messages to the returned buffer
+func newTestLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+// TestLogRedaction tests that signatures and keys never reach the log output
+func TestLogRedaction(t *testing.T) {
+	logger, buf := newTestLogger()
+	client := NewClient(true)
+	client.SetPrivateKey(testPrivateKey)
+	client.SetLogger(logger)
+	payload := []byte(`{"action":{"type":"order"},"nonce":1,"signature":{"r":"0xaaaa","s":"0xbbbb","v":27}}`)
+	client.Logger().With("privateKey", testPrivateKey).Info("test",
+		"payload", payload,
+		"request", `{"privateKey": "`+testPrivateKey+`"}`,
+		"sig", RsvSignature{R: "0xcccc", S: "0xdddd", V: 28},
+		"manager", client.KeyManager(),
+		"err", errors.New(`bad request {"signature":"0xeeee"}`),
+		slog.Group("nested", "Signature", "0xffff"),
+	)
+
+	output := buf.String()
+	for _, secret := range []string{testPrivateKey, "0xaaaa", "0xbbbb", "0xcccc", "0xdddd", "0xeeee", "0xffff"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Secret %s was logged: %s", secret, output)
+		}
+	}
+	if !strings.Contains(output, `"nonce\":1`) {
+		t.Errorf("Expected the rest of the payload to be logged: %s", output)
+	}
+	if count := strings.Count(output, REDACTED); count != 7 {
+		t.Errorf("Expected 7 redacted values, got %d: %s", count, output)
+	}
+}
+
+// TestStructuredLogging tests the fields of request and order logs
+func TestStructuredLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	logger, buf := newTestLogger()
+	client := NewClient(true)
+	client.baseUrl = server.URL
+	client.SetLogger(logger)
+	client.Request("/info", map[string]string{"type": "meta"})
+	if output := buf.String(); !strings.Contains(output, `"msg":"HTTP request","endpoint":"/info","transport":"http","latency":`) {
+		t.Errorf("Expected structured HTTP request log, got %s", output)
+	}
+
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		if FastUnmarshal(message, &request); request.Method == "post" {
+			replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"order","data":{"statuses":[{"resting":{"oid":42}},{"error":"Insufficient margin"}]}}}`)
+		}
+	})
+	api := newTestExchangeAPI(t, ws)
+	buf.Reset()
+	api.SetLogger(logger)
+	ws.SetLogger(logger)
+	cloid := GetRandomCloid()
+	orderType := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	_, err := api.BulkOrders([]OrderRequest{
+		{Coin: "BTC", IsBuy: true, Sz: 0.001, LimitPx: 50000, OrderType: orderType, Cloid: cloid},
+		{Coin: "BTC", IsBuy: true, Sz: 10, LimitPx: 50000, OrderType: orderType},
+	}, GroupingNa, false)
+	if err != nil {
+		t.Fatalf("BulkOrders failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, `"level":"DEBUG","msg":"Order resting","coin":"BTC","cloid":"`+cloid+`","oid":42`) {
+		t.Errorf("Expected resting order log, got %s", output)
+	}
+	if !strings.Contains(output, `"level":"WARN","msg":"Order rejected","coin":"BTC","cloid":"","err":"Insufficient margin"`) {
+		t.Errorf("Expected rejected order log, got %s", output)
+	}
+	if !strings.Contains(output, `"msg":"Sending post request"`) || strings.Contains(output, `\"r\":\"0x`) {
+		t.Errorf("Expected post request log without signature, got %s", output)
+	}
+}
@@ -0,0 +1,219 @@
+package hyperliquid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Default time AutoMargin waits before adjusting the same position again,
+// so the next update already includes the previous adjustment
+const AUTO_MARGIN_COOLDOWN = 5 * time.Second
+
+// AutoMargin keeps isolated positions away from liquidation by moving margin.
+// When the distance to liquidation of an isolated position drops below the minimum distance,
+// margin is added until the distance is back at the target distance. If a maximum distance is set,
+// margin of positions above it is removed down to the target distance. Cross positions are not touched.
+//
+// Positions are watched with a RiskCalculator, fed either by the webData2 stream (Follow)
+// or by polling GetUserState (Poll):
+//
+//	auto, _ := NewAutoMargin(client.ExchangeAPI, NewRiskCalculator(meta), 0.1, 0.2)
+//	auto.OnError(func(coin string, err error) { log.Println(coin, err) })
+//	handle, _ := auto.Follow(client.WebSocketAPI, address)
+type AutoMargin struct {
+	api            *ExchangeAPI
+	calc           *RiskCalculator
+	minDistance    float64
+	targetDistance float64
+	maxDistance    float64
+	cooldown       time.Duration
+
+	mu           sync.Mutex
+	lastAdjusted map[string]time.Time
+	onAdjust     func(coin string, amount float64)
+	onError      func(coin string, err error)
+}
+
+// NewAutoMargin creates an auto margin helper. Distances are relative price moves to the
+// liquidation price, e.g. 0.1 for 10%. targetDistance must not be below minDistance.
+func NewAutoMargin(api *ExchangeAPI, calc *RiskCalculator, minDistance float64, targetDistance float64) (*AutoMargin, error) {
+	if minDistance <= 0 || targetDistance < minDistance {
+		return nil, fmt.Errorf("invalid liquidation distances: min %v, target %v", minDistance, targetDistance)
+	}
+	return &AutoMargin{
+		api:            api,
+		calc:           calc,
+		minDistance:    minDistance,
+		targetDistance: targetDistance,
+		cooldown:       AUTO_MARGIN_COOLDOWN,
+		lastAdjusted:   make(map[string]time.Time),
+	}, nil
+}
+
+// SetMaxDistance enables removing excess margin from positions further than distance from liquidation.
+// Zero disables it.
+func (a *AutoMargin) SetMaxDistance(distance float64) error {
+	if distance != 0 && distance < a.targetDistance {
+		return fmt.Errorf("max distance %v is below target distance %v", distance, a.targetDistance)
+	}
+	a.mu.Lock()
+	a.maxDistance = distance
+	a.mu.Unlock()
+	return nil
+}
+
+// SetCooldown sets the minimum time between two adjustments of the same position
+func (a *AutoMargin) SetCooldown(cooldown time.Duration) {
+	a.mu.Lock()
+	a.cooldown = cooldown
+	a.mu.Unlock()
+}
+
+// OnAdjust sets a callback for every margin change, amount is negative if margin was removed
+func (a *AutoMargin) OnAdjust(handler func(coin string, amount float64)) {
+	a.mu.Lock()
+	a.onAdjust = handler
+	a.mu.Unlock()
+}
+
+// OnError sets a callback for failed adjustments and polls. coin is empty if the error is not
+// related to a single position.
+func (a *AutoMargin) OnError(handler func(coin string, err error)) {
+	a.mu.Lock()
+	a.onError = handler
+	a.mu.Unlock()
+}
+
+// Follow adjusts margin on every webData2 update of user
+func (a *AutoMargin) Follow(ws *WebSocketAPI, user string) (*SubscriptionHandle, error) {
+	return a.calc.Follow(ws, user, func(report *AccountRisk) {
+		a.Adjust(report)
+	})
+}
+
+// Poll fetches the user state and mid prices every interval and adjusts margin
+// until ctx is done. It returns the context error.
+func (a *AutoMargin) Poll(ctx context.Context, user string, interval time.Duration) error {
+	if a.api.infoAPI == nil {
+		return errors.New("exchange API has no info API")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := a.poll(user); err != nil {
+			a.reportError("", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *AutoMargin) poll(user string) error {
+	state, err := a.api.infoAPI.GetUserState(user)
+	if err != nil {
+		return err
+	}
+	mids, err := a.api.infoAPI.GetAllMids()
+	if err != nil {
+		return err
+	}
+	a.calc.Update(state, *mids)
+	report, err := a.calc.Report()
+	if err != nil {
+		return err
+	}
+	a.Adjust(report)
+	return nil
+}
+
+// Adjust moves margin of the isolated positions in report that are outside the configured distances.
+// Failed adjustments are reported to the OnError callback and returned joined.
+func (a *AutoMargin) Adjust(report *AccountRisk) error {
+	var errs []error
+	for i := range report.Positions {
+		position := &report.Positions[i]
+		amount := a.MarginAdjustment(position)
+		if amount == 0 || !a.reserve(position.Coin) {
+			continue
+		}
+		if _, err := a.api.UpdateIsolatedMargin(position.Coin, amount); err != nil {
+			a.release(position.Coin)
+			err = fmt.Errorf("update isolated margin of %s: %w", position.Coin, err)
+			a.reportError(position.Coin, err)
+			errs = append(errs, err)
+			continue
+		}
+		a.mu.Lock()
+		handler := a.onAdjust
+		a.mu.Unlock()
+		if handler != nil {
+			handler(position.Coin, amount)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MarginAdjustment returns the margin in USD to add (positive) or remove (negative) to move
+// position back to the target distance, or 0 if it is within the configured distances.
+func (a *AutoMargin) MarginAdjustment(position *PositionRisk) float64 {
+	if position.IsCross || position.Szi == 0 || position.PositionValue <= 0 {
+		return 0
+	}
+	a.mu.Lock()
+	maxDistance := a.maxDistance
+	a.mu.Unlock()
+	if position.DistanceToLiquidation >= a.minDistance && (maxDistance == 0 || position.DistanceToLiquidation <= maxDistance) {
+		return 0
+	}
+
+	// The distance is (margin - maintenance margin) / (position value * (1 - mmr * side))
+	side := 1.0
+	if position.Szi < 0 {
+		side = -1
+	}
+	maintenanceRate := position.MaintenanceMargin / position.PositionValue
+	required := a.targetDistance*position.PositionValue*(1-maintenanceRate*side) + position.MaintenanceMargin
+	amount := required - position.MarginUsed
+	if amount > 0 {
+		return math.Ceil(amount*100) / 100
+	}
+	// Margin can not be removed below the initial margin of the position
+	if position.Leverage > 0 {
+		initialMargin := position.PositionValue / float64(position.Leverage)
+		amount = math.Max(amount, initialMargin-position.MarginUsed)
+	}
+	return math.Min(0, math.Ceil(amount*100)/100)
+}
+
+// reserve marks coin as adjusted unless it was adjusted within the cooldown
+func (a *AutoMargin) reserve(coin string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if last, exists := a.lastAdjusted[coin]; exists && time.Since(last) < a.cooldown {
+		return false
+	}
+	a.lastAdjusted[coin] = time.Now()
+	return true
+}
+
+func (a *AutoMargin) release(coin string) {
+	a.mu.Lock()
+	delete(a.lastAdjusted, coin)
+	a.mu.Unlock()
+}
+
+func (a *AutoMargin) reportError(coin string, err error) {
+	a.mu.Lock()
+	handler := a.onError
+	a.mu.Unlock()
+	if handler != nil {
+		handler(coin, err)
+	}
+}
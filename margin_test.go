@@ -0,0 +1,67 @@
+package hyperliquid
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestAutoMargin tests that isolated positions are moved back to the target liquidation distance
+func TestAutoMargin(t *testing.T) {
+	actions := make(chan map[string]interface{}, 10)
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		FastUnmarshal(message, &request)
+		if request.Method != "post" {
+			return
+		}
+		actions <- request.Request.Payload.(map[string]interface{})["action"].(map[string]interface{})
+		replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"default"}}`)
+	})
+	api := newTestExchangeAPI(t, ws)
+	api.meta["ETH"] = AssetInfo{SzDecimals: 4, AssetId: 1}
+
+	calc := testRiskCalculator()
+	auto, err := NewAutoMargin(api, calc, 0.1, 0.2)
+	if err != nil {
+		t.Fatalf("NewAutoMargin failed: %v", err)
+	}
+	var adjusted float64
+	auto.OnAdjust(func(coin string, amount float64) { adjusted = amount })
+
+	report, _ := calc.Report()
+	eth := *report.Position("ETH")
+	amount := auto.MarginAdjustment(&eth)
+	if amount <= 0 {
+		t.Fatalf("Expected margin to be added to ETH, got %v", amount)
+	}
+	eth.MarginUsed += amount
+	if _, distance := liquidationPx(&eth, eth.MarginUsed-eth.MaintenanceMargin, 0.02); distance < 0.2 || distance > 0.201 {
+		t.Errorf("Expected distance at target after adjustment, got %v", distance)
+	}
+
+	if err := auto.Adjust(report); err != nil {
+		t.Fatalf("Adjust failed: %v", err)
+	}
+	action := <-actions
+	if action["type"] != "updateIsolatedMargin" || action["asset"] != float64(1) || action["ntli"] != float64(FloatToUsdInt(amount)) {
+		t.Errorf("Unexpected action: %v", action)
+	}
+	if adjusted != amount {
+		t.Errorf("Expected OnAdjust with %v, got %v", amount, adjusted)
+	}
+	// The position is not adjusted again within the cooldown
+	if err := auto.Adjust(report); err != nil || len(actions) != 0 {
+		t.Errorf("Expected no adjustment within cooldown, got %v", err)
+	}
+
+	// Excess margin is only removed down to the target distance
+	far := &PositionRisk{Coin: "SOL", Szi: 1, MarkPx: 100, PositionValue: 100, Leverage: 10, MarginUsed: 80, MaintenanceMargin: 1, DistanceToLiquidation: 0.79}
+	if amount := auto.MarginAdjustment(far); amount != 0 {
+		t.Errorf("Expected no adjustment without max distance, got %v", amount)
+	}
+	if err := auto.SetMaxDistance(0.5); err != nil {
+		t.Fatalf("SetMaxDistance failed: %v", err)
+	}
+	assertClose(t, "removed margin", auto.MarginAdjustment(far), -59.2)
+}
@@ -0,0 +1,54 @@
+package hyperliquid
+
+import (
+	"strings"
+	"time"
+)
+
+// Transports of the requests reported to MetricsHook.ObserveRequest
+const (
+	TransportHTTP = "http"
+	TransportWS   = "ws"
+)
+
+// Kinds of signatures reported to MetricsHook.ObserveSigning
+const (
+	SigningL1   = "l1"   // L1 actions (orders, cancels, leverage, ...)
+	SigningUser = "user" // User signed actions (withdrawals, builder fee approvals, ...)
+)
+
+// MetricsHook receives events of the SDK for monitoring, see PrometheusMetrics.
+// Implementations must be safe for concurrent use and should not block.
+type MetricsHook interface {
+	// ObserveRequest is called after every request to endpoint ("info" or "exchange").
+	// err is nil for successful requests.
+	ObserveRequest(endpoint string, transport string, duration time.Duration, err error)
+	// SetWSConnected is called when the WebSocket connects or disconnects
+	SetWSConnected(connected bool)
+	// WSReconnect is called after every reconnection attempt, err is nil if it succeeded
+	WSReconnect(err error)
+	// ResubscribeFailed is called for every subscription that could not be renewed after a reconnect
+	ResubscribeFailed(channel string)
+	// MessageDropped is called for every subscription message dropped because a listener was full
+	MessageDropped(channel string)
+	// PostTimeout is called for every WebSocket post request without response in time
+	PostTimeout(requestType string)
+	// ObserveSigning is called after signing an action of kind, SigningL1 or SigningUser
+	ObserveSigning(kind string, duration time.Duration)
+}
+
+// NopMetrics is a MetricsHook that ignores all events. It is used when no hook is set.
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveRequest(string, string, time.Duration, error) {}
+func (NopMetrics) SetWSConnected(bool)                                 {}
+func (NopMetrics) WSReconnect(error)                                   {}
+func (NopMetrics) ResubscribeFailed(string)                            {}
+func (NopMetrics) MessageDropped(string)                               {}
+func (NopMetrics) PostTimeout(string)                                  {}
+func (NopMetrics) ObserveSigning(string, time.Duration)                {}
+
+// requestEndpoint returns the endpoint name of a request path, e.g. "info" for "/info"
+func requestEndpoint(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,157 @@
+package hyperliquid
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PROMETHEUS_NAMESPACE is the namespace of the metrics of PrometheusMetrics
+const PROMETHEUS_NAMESPACE = "hyperliquid"
+
+// PrometheusMetrics is a MetricsHook that exports the SDK events as Prometheus metrics:
+//
+//	hyperliquid_requests_total{endpoint,transport,status}
+//	hyperliquid_request_duration_seconds{endpoint,transport}
+//	hyperliquid_ws_connected
+//	hyperliquid_ws_reconnects_total{status}
+//	hyperliquid_ws_resubscribe_failures_total{channel}
+//	hyperliquid_ws_dropped_messages_total{channel}
+//	hyperliquid_ws_post_timeouts_total{type}
+//	hyperliquid_signing_duration_seconds{kind}
+type PrometheusMetrics struct {
+	RequestsTotal       *prometheus.CounterVec
+	RequestDuration     *prometheus.HistogramVec
+	WSConnected         prometheus.Gauge
+	WSReconnects        *prometheus.CounterVec
+	ResubscribeFailures *prometheus.CounterVec
+	DroppedMessages     *prometheus.CounterVec
+	PostTimeouts        *prometheus.CounterVec
+	SigningDuration     *prometheus.HistogramVec
+}
+
+// NewPrometheusMetrics creates the metrics and registers them with registerer,
+// prometheus.DefaultRegisterer if nil
+func NewPrometheusMetrics(registerer prometheus.Registerer) (*PrometheusMetrics, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	m := &PrometheusMetrics{
+		RequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "requests_total",
+				Help:      "Total number of API requests",
+			},
+			[]string{"endpoint", "transport", "status"},
+		),
+		RequestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "request_duration_seconds",
+				Help:      "API request duration in seconds",
+				Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+			},
+			[]string{"endpoint", "transport"},
+		),
+		WSConnected: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "ws_connected",
+				Help:      "Whether the WebSocket is connected (1) or not (0)",
+			},
+		),
+		WSReconnects: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "ws_reconnects_total",
+				Help:      "Total number of WebSocket reconnection attempts",
+			},
+			[]string{"status"},
+		),
+		ResubscribeFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "ws_resubscribe_failures_total",
+				Help:      "Total number of subscriptions that failed to resubscribe after a reconnect",
+			},
+			[]string{"channel"},
+		),
+		DroppedMessages: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "ws_dropped_messages_total",
+				Help:      "Total number of subscription messages dropped because a listener was full",
+			},
+			[]string{"channel"},
+		),
+		PostTimeouts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "ws_post_timeouts_total",
+				Help:      "Total number of WebSocket post requests without response in time",
+			},
+			[]string{"type"},
+		),
+		SigningDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: PROMETHEUS_NAMESPACE,
+				Name:      "signing_duration_seconds",
+				Help:      "Action signing duration in seconds",
+				Buckets:   []float64{.00005, .0001, .00025, .0005, .001, .0025, .005, .01},
+			},
+			[]string{"kind"},
+		),
+	}
+	collectors := []prometheus.Collector{
+		m.RequestsTotal, m.RequestDuration, m.WSConnected, m.WSReconnects,
+		m.ResubscribeFailures, m.DroppedMessages, m.PostTimeouts, m.SigningDuration,
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// metricsStatus returns the status label of a result
+func metricsStatus(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func (m *PrometheusMetrics) ObserveRequest(endpoint string, transport string, duration time.Duration, err error) {
+	m.RequestsTotal.WithLabelValues(endpoint, transport, metricsStatus(err)).Inc()
+	m.RequestDuration.WithLabelValues(endpoint, transport).Observe(duration.Seconds())
+}
+
+func (m *PrometheusMetrics) SetWSConnected(connected bool) {
+	if connected {
+		m.WSConnected.Set(1)
+	} else {
+		m.WSConnected.Set(0)
+	}
+}
+
+func (m *PrometheusMetrics) WSReconnect(err error) {
+	m.WSReconnects.WithLabelValues(metricsStatus(err)).Inc()
+}
+
+func (m *PrometheusMetrics) ResubscribeFailed(channel string) {
+	m.ResubscribeFailures.WithLabelValues(channel).Inc()
+}
+
+func (m *PrometheusMetrics) MessageDropped(channel string) {
+	m.DroppedMessages.WithLabelValues(channel).Inc()
+}
+
+func (m *PrometheusMetrics) PostTimeout(requestType string) {
+	m.PostTimeouts.WithLabelValues(requestType).Inc()
+}
+
+func (m *PrometheusMetrics) ObserveSigning(kind string, duration time.Duration) {
+	m.SigningDuration.WithLabelValues(kind).Observe(duration.Seconds())
+}
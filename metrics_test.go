@@ -0,0 +1,96 @@
+package hyperliquid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestPrometheusMetrics tests that requests, signatures and WebSocket events are exported
+func TestPrometheusMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewPrometheusMetrics(registry)
+	if err != nil {
+		t.Fatalf("NewPrometheusMetrics failed: %v", err)
+	}
+	if _, err := NewPrometheusMetrics(registry); err == nil {
+		t.Error("Expected error when registering the metrics twice")
+	}
+
+	// HTTP requests
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(true)
+	client.baseUrl = server.URL
+	client.SetMetrics(metrics)
+	client.Request("/info", map[string]string{"type": "meta"})
+	status = http.StatusInternalServerError
+	client.Request("/info", map[string]string{"type": "meta"})
+	if ok := testutil.ToFloat64(metrics.RequestsTotal.WithLabelValues("info", TransportHTTP, "ok")); ok != 1 {
+		t.Errorf("Expected 1 successful HTTP request, got %v", ok)
+	}
+	if failed := testutil.ToFloat64(metrics.RequestsTotal.WithLabelValues("info", TransportHTTP, "error")); failed != 1 {
+		t.Errorf("Expected 1 failed HTTP request, got %v", failed)
+	}
+
+	// WebSocket posts, the second one is never answered
+	posts := 0
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		if FastUnmarshal(message, &request); request.Method == "post" {
+			if posts++; posts == 1 {
+				replyToPost(t, conn, message, "info", `{"type":"meta","data":{}}`)
+			}
+		}
+	})
+	ws.SetMetrics(metrics)
+	client.SetWebSocketAPI(ws)
+	client.Request("/info", map[string]string{"type": "meta"})
+	if _, err := ws.PostRequestWithTimeout("info", map[string]string{"type": "meta"}, 50*time.Millisecond); !errors.Is(err, ErrPostTimeout) {
+		t.Errorf("Expected post timeout, got %v", err)
+	}
+	if ok := testutil.ToFloat64(metrics.RequestsTotal.WithLabelValues("info", TransportWS, "ok")); ok != 1 {
+		t.Errorf("Expected 1 successful WebSocket request, got %v", ok)
+	}
+	if timeouts := testutil.ToFloat64(metrics.PostTimeouts.WithLabelValues("info")); timeouts != 1 {
+		t.Errorf("Expected 1 post timeout, got %v", timeouts)
+	}
+
+	// Messages for a blocked listener are dropped
+	release := make(chan struct{})
+	defer close(release)
+	if _, err := ws.SubscribeBbo("BTC", func(data interface{}) { <-release }); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		ws.processJSONMessage([]byte(`{"channel":"bbo","data":{"coin":"BTC","time":1,"bbo":[null,null]}}`))
+	}
+	if dropped := testutil.ToFloat64(metrics.DroppedMessages.WithLabelValues("bbo")); dropped < 9 {
+		t.Errorf("Expected at least 9 dropped messages, got %v", dropped)
+	}
+
+	ws.Disconnect()
+	if connected := testutil.ToFloat64(metrics.WSConnected); connected != 0 {
+		t.Errorf("Expected disconnected gauge, got %v", connected)
+	}
+
+	// Signing
+	api := newTestExchangeAPI(t, nil)
+	api.SetMetrics(metrics)
+	api.newL1Request(context.Background(), UpdateLeverageAction{Type: "updateLeverage", Asset: 0, IsCross: true, Leverage: 10})
+	api.SignWithdrawAction(api.newWithdrawAction("0x0000000000000000000000000000000000000001", 1))
+	if count := testutil.CollectAndCount(metrics.SigningDuration); count != 2 {
+		t.Errorf("Expected signing durations of 2 kinds, got %d", count)
+	}
+}
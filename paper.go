@@ -0,0 +1,1234 @@
+package hyperliquid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fee rates of the paper exchange, the base tier of the exchange
+const (
+	PAPER_TAKER_FEE = 0.00045
+	PAPER_MAKER_FEE = 0.00015
+)
+
+// Sizes below paperDust are treated as zero to absorb floating point residue
+const paperDust = 1e-9
+
+var ErrPaperSigning = errors.New("paper exchange does not sign actions")
+
+var _ IExchangeAPI = (*PaperExchange)(nil)
+
+// PaperConfig configures a PaperExchange
+type PaperConfig struct {
+	Balance  float64 // Starting USDC balance
+	Meta     *Meta   // Max leverage of the perp assets, DEFAULT_LEVERAGE for all assets if nil
+	TakerFee float64 // Fee rate of taker fills, PAPER_TAKER_FEE if 0
+	MakerFee float64 // Fee rate of maker fills, PAPER_MAKER_FEE if 0
+}
+
+// PaperExchange simulates the /exchange service for paper trading and backtests. It implements
+// IExchangeAPI, so strategies written against the interface switch between real and simulated
+// trading without code changes.
+//
+// Orders are matched against the market data fed with UpdateBook, AddTrades and UpdateAssetCtx,
+// live with Follow or from a recording. Incoming orders take liquidity from the latest l2Book
+// snapshot at the taker fee. Resting orders are filled at their limit price at the maker fee when
+// a trade prints at or through it, or when the book moves through it; the queue position is not
+// modeled. Trigger orders fire on the mark price, the mark of activeAssetCtx or else the mid of
+// the book. Funding is settled at every full hour of market data time with the latest
+// activeAssetCtx funding rate. Margin, liquidation prices and account values follow the rules
+// of RiskCalculator; liquidations are not simulated. Only perp orders are supported.
+//
+//	paper := NewPaperExchange(PaperConfig{Balance: 10000, Meta: meta})
+//	paper.Follow(client.WebSocketAPI, "BTC", "ETH")
+//	var exchange IExchangeAPI = paper // or client.ExchangeAPI
+//	exchange.LimitOrder(TifGtc, "BTC", 0.01, 60000, false)
+//	state, _ := paper.GetAccountState()
+type PaperExchange struct {
+	mu            sync.Mutex
+	takerFee      float64
+	makerFee      float64
+	calc          *RiskCalculator // Asset meta and margin valuation
+	assetIds      map[string]int
+	balance       float64 // Cross account value excluding unrealized pnl of cross positions
+	positions     map[string]*paperPosition
+	leverage      map[string]Leverage // Leverage settings for coins without a position
+	allTimeFunds  map[string]float64  // Funding paid per coin since the start
+	books         map[string]*paperBook
+	marks         map[string]float64
+	fundingRates  map[string]float64
+	orders        []*paperOrder // Open orders in placement order
+	fills         []OrderFill
+	builderFees   map[string]int
+	cancelAt      int64 // Time of the scheduled cancel in ms, 0 if none
+	now           int64 // Time of the latest market data in ms
+	fundingHour   int64 // Hour of the last funding settlement
+	nextOid       int64
+	nextTid       int64
+	onFill        func(fill OrderFill)
+	onOrderUpdate func(update OrderUpdate)
+	fillEvents    []OrderFill   // Events collected under the lock, see unlock
+	updateEvents  []OrderUpdate // Events collected under the lock, see unlock
+}
+
+// paperPosition is a simulated position
+type paperPosition struct {
+	riskPosition
+	sinceOpen   float64 // Funding paid since the position was opened
+	sinceChange float64 // Funding paid since the last fill
+}
+
+// paperLevel is a price level of a simulated order book
+type paperLevel struct {
+	px float64
+	sz float64
+}
+
+// paperBook is the latest l2Book snapshot of a coin, with the liquidity taken by simulated orders removed
+type paperBook struct {
+	bids []paperLevel
+	asks []paperLevel
+}
+
+// paperOrder is an open simulated order, Sz of the request is the remaining size
+type paperOrder struct {
+	oid       int64
+	request   OrderRequest
+	origSz    float64
+	timestamp int64
+	closed    bool // Removed from the open orders
+}
+
+// NewPaperExchange creates a paper exchange with the balance of config
+func NewPaperExchange(config PaperConfig) *PaperExchange {
+	p := &PaperExchange{
+		takerFee:     config.TakerFee,
+		makerFee:     config.MakerFee,
+		calc:         NewRiskCalculator(config.Meta),
+		assetIds:     make(map[string]int),
+		balance:      config.Balance,
+		positions:    make(map[string]*paperPosition),
+		leverage:     make(map[string]Leverage),
+		allTimeFunds: make(map[string]float64),
+		books:        make(map[string]*paperBook),
+		marks:        make(map[string]float64),
+		fundingRates: make(map[string]float64),
+		builderFees:  make(map[string]int),
+		nextOid:      1,
+		nextTid:      1,
+	}
+	if p.takerFee == 0 {
+		p.takerFee = PAPER_TAKER_FEE
+	}
+	if p.makerFee == 0 {
+		p.makerFee = PAPER_MAKER_FEE
+	}
+	if config.Meta != nil {
+		for i, asset := range config.Meta.Universe {
+			p.assetIds[asset.Name] = i
+		}
+	}
+	return p
+}
+
+// OnFill sets a handler called with every simulated fill, like the userFills stream
+func (p *PaperExchange) OnFill(handler func(fill OrderFill)) {
+	p.mu.Lock()
+	p.onFill = handler
+	p.mu.Unlock()
+}
+
+// OnOrderUpdate sets a handler called with every status change of an order, like the orderUpdates stream
+func (p *PaperExchange) OnOrderUpdate(handler func(update OrderUpdate)) {
+	p.mu.Lock()
+	p.onOrderUpdate = handler
+	p.mu.Unlock()
+}
+
+// unlock releases the lock and then calls the handlers with the events collected under it,
+// so handlers can place orders
+func (p *PaperExchange) unlock() {
+	fills, updates := p.fillEvents, p.updateEvents
+	p.fillEvents, p.updateEvents = nil, nil
+	onFill, onOrderUpdate := p.onFill, p.onOrderUpdate
+	p.mu.Unlock()
+	if onOrderUpdate != nil {
+		for _, update := range updates {
+			onOrderUpdate(update)
+		}
+	}
+	if onFill != nil {
+		for _, fill := range fills {
+			onFill(fill)
+		}
+	}
+}
+
+//
+// Market data
+//
+
+// Follow feeds the l2Book, trades and activeAssetCtx streams of coins into the exchange.
+// The WebSocketAPI can also be replaying a recording.
+func (p *PaperExchange) Follow(ws *WebSocketAPI, coins ...string) ([]*SubscriptionHandle, error) {
+	var handles []*SubscriptionHandle
+	subscribe := func(handle *SubscriptionHandle, err error) error {
+		if err != nil {
+			for _, h := range handles {
+				h.Close()
+			}
+			return err
+		}
+		handles = append(handles, handle)
+		return nil
+	}
+	for _, coin := range coins {
+		err := subscribe(ws.SubscribeOrderbook(coin, func(data interface{}) {
+			if book, err := convertWSData[L2BookSnapshot](data); err == nil {
+				p.UpdateBook(*book)
+			}
+		}))
+		if err == nil {
+			err = subscribe(ws.SubscribeTrades(coin, func(data interface{}) {
+				if trades, err := convertWSData[[]Trade](data); err == nil {
+					p.AddTrades(*trades)
+				}
+			}))
+		}
+		if err == nil {
+			err = subscribe(ws.SubscribeActiveAssetCtx(coin, func(data interface{}) {
+				if ctx, err := convertWSData[ActiveAssetCtx](data); err == nil {
+					p.UpdateAssetCtx(*ctx)
+				}
+			}))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return handles, nil
+}
+
+// UpdateBook replaces the order book of a coin and fills the resting orders it moved through
+func (p *PaperExchange) UpdateBook(book L2BookSnapshot) {
+	p.mu.Lock()
+	defer p.unlock()
+	p.advance(book.Time)
+
+	b := &paperBook{}
+	if len(book.Levels) > 0 {
+		for _, level := range book.Levels[0] {
+			b.bids = append(b.bids, paperLevel{px: level.Px, sz: level.Sz})
+		}
+	}
+	if len(book.Levels) > 1 {
+		for _, level := range book.Levels[1] {
+			b.asks = append(b.asks, paperLevel{px: level.Px, sz: level.Sz})
+		}
+	}
+	p.books[book.Coin] = b
+	if len(b.bids) > 0 && len(b.asks) > 0 {
+		p.setMark(book.Coin, (b.bids[0].px+b.asks[0].px)/2)
+	}
+
+	for _, order := range p.restingOrders(book.Coin) {
+		levels := &b.asks
+		if !order.request.IsBuy {
+			levels = &b.bids
+		}
+		for !order.closed && order.request.Sz > paperDust && len(*levels) > 0 && crosses(order.request.IsBuy, order.request.LimitPx, (*levels)[0].px) {
+			sz := p.fill(order, order.request.LimitPx, math.Min(order.request.Sz, (*levels)[0].sz), false)
+			if sz == 0 {
+				break
+			}
+			if (*levels)[0].sz -= sz; (*levels)[0].sz <= paperDust {
+				*levels = (*levels)[1:]
+			}
+		}
+		p.closeIfFilled(order)
+	}
+}
+
+// AddTrades fills the resting orders the trades printed at or through
+func (p *PaperExchange) AddTrades(trades []Trade) {
+	p.mu.Lock()
+	defer p.unlock()
+	for _, trade := range trades {
+		p.advance(trade.Time)
+		if _, hasBook := p.books[trade.Coin]; !hasBook {
+			p.setMark(trade.Coin, trade.Px)
+		}
+		// A buying taker (side B) fills resting sells, a selling taker resting buys
+		remaining := trade.Sz
+		for _, order := range p.restingOrders(trade.Coin) {
+			if remaining <= paperDust {
+				break
+			}
+			if order.closed || order.request.IsBuy == (trade.Side == "B") || !crosses(order.request.IsBuy, order.request.LimitPx, trade.Px) {
+				continue
+			}
+			remaining -= p.fill(order, order.request.LimitPx, math.Min(order.request.Sz, remaining), false)
+			p.closeIfFilled(order)
+		}
+	}
+}
+
+// UpdateAssetCtx updates the mark price and funding rate of a coin
+func (p *PaperExchange) UpdateAssetCtx(ctx ActiveAssetCtx) {
+	p.mu.Lock()
+	defer p.unlock()
+	p.fundingRates[ctx.Coin] = ctx.Ctx.Funding
+	if ctx.Ctx.MarkPx > 0 {
+		p.setMark(ctx.Coin, ctx.Ctx.MarkPx)
+	}
+}
+
+// ApplyFunding settles a funding payment of all positions in coin at rate and the current mark price.
+// Longs pay shorts if rate is positive.
+func (p *PaperExchange) ApplyFunding(coin string, rate float64) {
+	p.mu.Lock()
+	defer p.unlock()
+	p.applyFunding(coin, rate)
+}
+
+// applyFunding settles a funding payment of the position in coin
+func (p *PaperExchange) applyFunding(coin string, rate float64) {
+	position, exists := p.positions[coin]
+	if !exists {
+		return
+	}
+	payment := position.szi * position.markPx * rate
+	if position.isCross {
+		p.balance -= payment
+	} else {
+		// Isolated positions pay funding from their own margin
+		position.isolatedMargin -= payment
+	}
+	position.sinceOpen += payment
+	position.sinceChange += payment
+	p.allTimeFunds[coin] += payment
+}
+
+// advance moves the simulated clock to time in ms, settling hourly funding and the scheduled cancel
+func (p *PaperExchange) advance(time int64) {
+	if time <= p.now {
+		return
+	}
+	p.now = time
+	hour := time / 3_600_000
+	if p.fundingHour != 0 && hour > p.fundingHour {
+		coins := make([]string, 0, len(p.positions))
+		for coin := range p.positions {
+			coins = append(coins, coin)
+		}
+		sort.Strings(coins)
+		// One payment per elapsed hour, gaps in the market data skip no funding
+		for elapsed := p.fundingHour + 1; elapsed <= hour; elapsed++ {
+			for _, coin := range coins {
+				p.applyFunding(coin, p.fundingRates[coin])
+			}
+		}
+	}
+	p.fundingHour = hour
+	if p.cancelAt != 0 && time >= p.cancelAt {
+		p.cancelAt = 0
+		for len(p.orders) > 0 {
+			p.removeOrder(p.orders[0], "scheduledCancel")
+		}
+	}
+}
+
+// time returns the simulated time in ms, the wall clock before the first market data
+func (p *PaperExchange) time() int64 {
+	if p.now == 0 {
+		return time.Now().UnixMilli()
+	}
+	return p.now
+}
+
+// setMark updates the mark price of a coin and fires the trigger orders it crosses
+func (p *PaperExchange) setMark(coin string, px float64) {
+	p.marks[coin] = px
+	if position, exists := p.positions[coin]; exists {
+		position.markPx = px
+	}
+	for _, order := range append([]*paperOrder(nil), p.orders...) {
+		trigger := order.request.OrderType.Trigger
+		if order.request.Coin != coin || trigger == nil {
+			continue
+		}
+		triggerPx, err := strconv.ParseFloat(trigger.TriggerPx, 64)
+		if err != nil {
+			continue
+		}
+		// Take profits of longs and stop losses of shorts fire above the trigger price
+		above := (trigger.TpSl == TriggerTp) != order.request.IsBuy
+		if (above && px < triggerPx) || (!above && px > triggerPx) {
+			continue
+		}
+		p.removeOrder(order, "triggered")
+		request := order.request
+		request.OrderType = OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+		if trigger.IsMarket {
+			request.OrderType.Limit.Tif = TifIoc
+		}
+		p.execute(order.oid, request, order.origSz)
+	}
+}
+
+//
+// Matching
+//
+
+// crosses checks if an order at limitPx on the side of isBuy trades at px
+func crosses(isBuy bool, limitPx float64, px float64) bool {
+	if isBuy {
+		return px <= limitPx
+	}
+	return px >= limitPx
+}
+
+// restingOrders returns the untriggered limit orders of coin by price and time priority
+func (p *PaperExchange) restingOrders(coin string) []*paperOrder {
+	var orders []*paperOrder
+	for _, order := range p.orders {
+		if order.request.Coin == coin && order.request.OrderType.Trigger == nil {
+			orders = append(orders, order)
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		a, b := orders[i].request, orders[j].request
+		if a.IsBuy != b.IsBuy {
+			return a.IsBuy
+		}
+		if a.IsBuy {
+			return a.LimitPx > b.LimitPx
+		}
+		return a.LimitPx < b.LimitPx
+	})
+	return orders
+}
+
+// assetId returns the asset of coin, -1 if it is not in the meta
+func (p *PaperExchange) assetId(coin string) int {
+	if id, exists := p.assetIds[coin]; exists {
+		return id
+	}
+	return -1
+}
+
+// leverageOf returns the leverage of the position in coin, or of new positions in coin
+func (p *PaperExchange) leverageOf(coin string) Leverage {
+	if position, exists := p.positions[coin]; exists {
+		leverageType := "isolated"
+		if position.isCross {
+			leverageType = "cross"
+		}
+		return Leverage{Type: leverageType, Value: position.leverage}
+	}
+	if leverage, exists := p.leverage[coin]; exists {
+		return leverage
+	}
+	return p.calc.defaultLeverage(coin)
+}
+
+// report values the account at the current mark prices
+func (p *PaperExchange) report() *AccountRisk {
+	coins := make([]string, 0, len(p.positions))
+	for coin := range p.positions {
+		coins = append(coins, coin)
+	}
+	sort.Strings(coins)
+	positions := make([]riskPosition, 0, len(coins))
+	for _, coin := range coins {
+		positions = append(positions, p.positions[coin].riskPosition)
+	}
+	p.calc.mu.RLock()
+	defer p.calc.mu.RUnlock()
+	return p.calc.evaluate(p.balance, positions)
+}
+
+// availableMargin returns the margin available to new orders, net of the margin of open orders
+func (p *PaperExchange) availableMargin() float64 {
+	available := p.report().AvailableMargin
+	for _, order := range p.orders {
+		if !order.request.ReduceOnly {
+			available -= order.request.Sz * order.request.LimitPx / float64(p.leverageOf(order.request.Coin).Value)
+		}
+	}
+	return available
+}
+
+// orderSize returns the size and limit price of request
+func orderSize(request OrderRequest) (float64, float64) {
+	sz, px := request.Sz, request.LimitPx
+	if request.ExactSz != nil {
+		sz = request.ExactSz.Float64()
+	}
+	if request.ExactLimitPx != nil {
+		px = request.ExactLimitPx.Float64()
+	}
+	return sz, px
+}
+
+// placeOrder validates and executes an order request
+func (p *PaperExchange) placeOrder(request OrderRequest, isSpot bool) StatusResponse {
+	asset := p.assetId(request.Coin)
+	request.Sz, request.LimitPx = orderSize(request)
+	request.ExactSz, request.ExactLimitPx = nil, nil
+	switch {
+	case isSpot:
+		return StatusResponse{Error: "Paper exchange does not support spot orders"}
+	case len(p.assetIds) > 0 && asset < 0:
+		return StatusResponse{Error: fmt.Sprintf("Unknown asset %s", request.Coin)}
+	case request.Sz <= 0:
+		return StatusResponse{Error: fmt.Sprintf("Order has invalid size. asset=%d", asset)}
+	case request.LimitPx <= 0:
+		return StatusResponse{Error: fmt.Sprintf("Order has invalid price. asset=%d", asset)}
+	case request.OrderType.Limit == nil && request.OrderType.Trigger == nil:
+		return StatusResponse{Error: fmt.Sprintf("Order has no order type. asset=%d", asset)}
+	}
+	if request.Cloid != "" {
+		for _, order := range p.orders {
+			if strings.EqualFold(order.request.Cloid, request.Cloid) {
+				return StatusResponse{Error: fmt.Sprintf("Duplicate cloid. asset=%d", asset)}
+			}
+		}
+	}
+	if request.Builder != nil {
+		if approved, exists := p.builderFees[strings.ToLower(request.Builder.Builder)]; !exists || approved < request.Builder.Fee {
+			return StatusResponse{Error: "Builder fee has not been approved."}
+		}
+	}
+
+	oid := p.nextOid
+	p.nextOid++
+	if request.OrderType.Trigger != nil {
+		if _, err := strconv.ParseFloat(request.OrderType.Trigger.TriggerPx, 64); err != nil {
+			return StatusResponse{Error: fmt.Sprintf("Order has invalid trigger price. asset=%d", asset)}
+		}
+		order := &paperOrder{oid: oid, request: request, origSz: request.Sz, timestamp: p.time()}
+		p.orders = append(p.orders, order)
+		p.orderUpdate(order, "open")
+		return StatusResponse{Resting: RestingStatus{OrderId: int(oid), Cloid: request.Cloid}}
+	}
+	return p.execute(oid, request, request.Sz)
+}
+
+// execute matches a limit order against the book and rests the remainder
+func (p *PaperExchange) execute(oid int64, request OrderRequest, origSz float64) StatusResponse {
+	asset := p.assetId(request.Coin)
+	order := &paperOrder{oid: oid, request: request, origSz: origSz, timestamp: p.time()}
+	szi := 0.0
+	if position, exists := p.positions[request.Coin]; exists {
+		szi = position.szi
+	}
+
+	if request.ReduceOnly {
+		if szi == 0 || (szi > 0) == request.IsBuy {
+			return p.reject(order, fmt.Sprintf("Reduce only order would increase position. asset=%d", asset))
+		}
+		order.request.Sz = math.Min(order.request.Sz, math.Abs(szi))
+	} else {
+		opening := order.request.Sz
+		if szi != 0 && (szi > 0) != request.IsBuy {
+			opening = math.Max(0, opening-math.Abs(szi))
+		}
+		required := opening * request.LimitPx / float64(p.leverageOf(request.Coin).Value)
+		if required > p.availableMargin()+paperDust {
+			return p.reject(order, fmt.Sprintf("Insufficient margin to place order. asset=%d", asset))
+		}
+	}
+
+	book := p.books[request.Coin]
+	if book == nil {
+		book = &paperBook{}
+	}
+	levels := &book.asks
+	if !request.IsBuy {
+		levels = &book.bids
+	}
+	tif := request.OrderType.Limit.Tif
+	if tif == TifAlo && len(*levels) > 0 && crosses(request.IsBuy, request.LimitPx, (*levels)[0].px) {
+		return p.reject(order, fmt.Sprintf("Post only order would have immediately matched. asset=%d", asset))
+	}
+
+	var filledSz, notional float64
+	for order.request.Sz > paperDust && len(*levels) > 0 && crosses(request.IsBuy, request.LimitPx, (*levels)[0].px) {
+		level := &(*levels)[0]
+		sz := p.fill(order, level.px, math.Min(order.request.Sz, level.sz), true)
+		if sz == 0 {
+			break
+		}
+		filledSz += sz
+		notional += sz * level.px
+		if level.sz -= sz; level.sz <= paperDust {
+			*levels = (*levels)[1:]
+		}
+	}
+
+	if order.request.Sz <= paperDust {
+		p.orderUpdate(order, "filled")
+		return StatusResponse{Filled: FilledStatus{OrderId: int(oid), AvgPx: notional / filledSz, TotalSz: filledSz, Cloid: request.Cloid}}
+	}
+	if tif == TifIoc {
+		if filledSz == 0 {
+			return p.reject(order, fmt.Sprintf("Order could not immediately match against any resting orders. asset=%d", asset))
+		}
+		p.orderUpdate(order, "canceled")
+		return StatusResponse{Filled: FilledStatus{OrderId: int(oid), AvgPx: notional / filledSz, TotalSz: filledSz, Cloid: request.Cloid}}
+	}
+	p.orders = append(p.orders, order)
+	p.orderUpdate(order, "open")
+	return StatusResponse{Resting: RestingStatus{OrderId: int(oid), Cloid: request.Cloid}}
+}
+
+// reject emits the rejection of order and returns its status
+func (p *PaperExchange) reject(order *paperOrder, message string) StatusResponse {
+	p.orderUpdate(order, "rejected")
+	return StatusResponse{Error: message}
+}
+
+// fill executes sz of order at px and records the fill. Reduce only orders are limited to the
+// current position, fill returns the executed size.
+func (p *PaperExchange) fill(order *paperOrder, px float64, sz float64, crossed bool) float64 {
+	coin := order.request.Coin
+	position, exists := p.positions[coin]
+	if order.request.ReduceOnly {
+		if !exists || (position.szi > 0) == order.request.IsBuy {
+			return 0
+		}
+		sz = math.Min(sz, math.Abs(position.szi))
+	}
+	if !exists {
+		leverage := p.leverageOf(coin)
+		position = &paperPosition{riskPosition: riskPosition{
+			coin:     coin,
+			isCross:  leverage.Type != "isolated",
+			leverage: leverage.Value,
+		}}
+		p.positions[coin] = position
+	}
+	position.markPx = px
+	if mark, exists := p.marks[coin]; exists {
+		position.markPx = mark
+	}
+
+	delta := sz
+	side := "B"
+	if !order.request.IsBuy {
+		delta, side = -sz, "A"
+	}
+	start := position.szi
+	oldMargin := position.initialMargin()
+	closedPnl := position.fill(delta, px)
+	if math.Abs(position.szi) < paperDust {
+		position.szi = 0
+	}
+	marginDelta := position.initialMargin() - oldMargin
+
+	rate := p.makerFee
+	if crossed {
+		rate = p.takerFee
+	}
+	if order.request.Builder != nil {
+		rate += float64(order.request.Builder.Fee) / 1e5
+	}
+	fee := px * sz * rate
+	p.balance += closedPnl - fee
+	if !position.isCross {
+		// Margin moves between the cross account and the isolated position
+		position.isolatedMargin += marginDelta
+		p.balance -= marginDelta
+	}
+	position.sinceChange = 0
+	if position.szi == 0 {
+		if !position.isCross {
+			p.balance += position.isolatedMargin
+		}
+		delete(p.positions, coin)
+	}
+	order.request.Sz -= sz
+	if position.szi == 0 || (start != 0 && (position.szi > 0) != (start > 0)) {
+		p.cancelReduceOnly(coin, position.szi)
+	}
+
+	fill := OrderFill{
+		Cloid:         order.request.Cloid,
+		ClosedPnl:     closedPnl,
+		Coin:          coin,
+		Crossed:       crossed,
+		Dir:           fillDirection(start, delta),
+		Fee:           fee,
+		FeeToken:      "USDC",
+		Oid:           int(order.oid),
+		Px:            px,
+		Side:          side,
+		StartPosition: strconv.FormatFloat(start, 'f', -1, 64),
+		Sz:            sz,
+		Tid:           p.nextTid,
+		Time:          p.time(),
+	}
+	p.nextTid++
+	p.fills = append(p.fills, fill)
+	p.fillEvents = append(p.fillEvents, fill)
+	return sz
+}
+
+// cancelReduceOnly cancels the resting reduce only orders of coin that would increase a position of szi
+func (p *PaperExchange) cancelReduceOnly(coin string, szi float64) {
+	for _, order := range p.restingOrders(coin) {
+		if order.request.ReduceOnly && order.request.Sz > paperDust && (szi == 0 || (szi > 0) == order.request.IsBuy) {
+			p.removeOrder(order, "reduceOnlyCanceled")
+		}
+	}
+}
+
+// fillDirection returns the direction of a fill of delta on a position of start, e.g. "Open Long"
+func fillDirection(start float64, delta float64) string {
+	switch {
+	case start >= 0 && delta > 0:
+		return "Open Long"
+	case start <= 0 && delta < 0:
+		return "Open Short"
+	case start > 0 && start+delta < -paperDust:
+		return "Long > Short"
+	case start < 0 && start+delta > paperDust:
+		return "Short > Long"
+	case start > 0:
+		return "Close Long"
+	default:
+		return "Close Short"
+	}
+}
+
+// closeIfFilled removes a resting order that has no remaining size
+func (p *PaperExchange) closeIfFilled(order *paperOrder) {
+	if order.request.Sz <= paperDust {
+		p.removeOrder(order, "filled")
+	}
+}
+
+// removeOrder removes an open order and emits its final status
+func (p *PaperExchange) removeOrder(order *paperOrder, status string) {
+	for i, open := range p.orders {
+		if open == order {
+			p.orders = append(p.orders[:i], p.orders[i+1:]...)
+			break
+		}
+	}
+	order.closed = true
+	p.orderUpdate(order, status)
+}
+
+// orderUpdate emits a status change of order
+func (p *PaperExchange) orderUpdate(order *paperOrder, status string) {
+	p.updateEvents = append(p.updateEvents, OrderUpdate{
+		Order:           order.info(),
+		Status:          status,
+		StatusTimestamp: p.time(),
+	})
+}
+
+// info returns the order as reported by openOrders
+func (o *paperOrder) info() Order {
+	order := Order{
+		Cloid:      o.request.Cloid,
+		Coin:       o.request.Coin,
+		LimitPx:    o.request.LimitPx,
+		Oid:        o.oid,
+		OrderType:  "Limit",
+		OrigSz:     o.origSz,
+		ReduceOnly: o.request.ReduceOnly,
+		Side:       "B",
+		Sz:         math.Max(0, o.request.Sz),
+		Timestamp:  o.timestamp,
+	}
+	if !o.request.IsBuy {
+		order.Side = "A"
+	}
+	if limit := o.request.OrderType.Limit; limit != nil {
+		order.Tif = limit.Tif
+	}
+	if trigger := o.request.OrderType.Trigger; trigger != nil {
+		order.IsTrigger = true
+		order.TriggerPx, _ = strconv.ParseFloat(trigger.TriggerPx, 64)
+		switch {
+		case trigger.TpSl == TriggerTp && trigger.IsMarket:
+			order.OrderType = "Take Profit Market"
+		case trigger.TpSl == TriggerTp:
+			order.OrderType = "Take Profit Limit"
+		case trigger.IsMarket:
+			order.OrderType = "Stop Market"
+		default:
+			order.OrderType = "Stop Limit"
+		}
+	}
+	return order
+}
+
+// findOrder returns the open order with oid, or with cloid if it is set
+func (p *PaperExchange) findOrder(oid int64, cloid string) *paperOrder {
+	for _, order := range p.orders {
+		if (cloid == "" && order.oid == oid) || (cloid != "" && strings.EqualFold(order.request.Cloid, cloid)) {
+			return order
+		}
+	}
+	return nil
+}
+
+// cancel cancels the open order with oid or cloid
+func (p *PaperExchange) cancel(asset int, oid int64, cloid string) StatusResponse {
+	order := p.findOrder(oid, cloid)
+	if order == nil {
+		return StatusResponse{Error: fmt.Sprintf("Order was never placed, already canceled, or filled. asset=%d", asset)}
+	}
+	p.removeOrder(order, "canceled")
+	return StatusResponse{Status: "success"}
+}
+
+// orderResponse wraps statuses in the response of the exchange
+func orderResponse(responseType string, statuses []StatusResponse) *OrderResponse {
+	return &OrderResponse{
+		Status:   "ok",
+		Response: OrderInnerResponse{Type: responseType, Data: DataResponse{Statuses: statuses}},
+	}
+}
+
+// defaultResponse returns the response of the exchange to actions without data
+func defaultResponse() *DefaultExchangeResponse {
+	response := &DefaultExchangeResponse{Status: "ok"}
+	response.Response.Type = "default"
+	return response
+}
+
+//
+// Account state
+//
+
+// GetAccountState returns the simulated account in the format of GetUserState
+func (p *PaperExchange) GetAccountState() (*UserState, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	report := p.report()
+	state := &UserState{
+		Withdrawable:               math.Max(0, report.AvailableMargin),
+		CrossMaintenanceMarginUsed: report.CrossMaintenanceMargin,
+		AssetPositions:             make([]AssetPosition, 0, len(report.Positions)),
+		Time:                       p.time(),
+	}
+	crossNotional := 0.0
+	crossRaw, totalRaw := report.CrossAccountValue, report.AccountValue
+	for _, risk := range report.Positions {
+		position := p.positions[risk.Coin]
+		leverage := p.leverageOf(risk.Coin)
+		entry := Position{
+			Coin:          risk.Coin,
+			EntryPx:       risk.EntryPx,
+			Leverage:      leverage,
+			LiquidationPx: risk.LiquidationPx,
+			MarginUsed:    risk.MarginUsed,
+			PositionValue: risk.PositionValue,
+			Szi:           risk.Szi,
+			UnrealizedPnl: risk.UnrealizedPnl,
+			MaxLeverage:   p.calc.defaultLeverage(risk.Coin).Value,
+		}
+		if asset, exists := p.calc.assets[risk.Coin]; exists {
+			entry.MaxLeverage = asset.MaxLeverage
+		}
+		if margin := math.Abs(risk.Szi) * risk.EntryPx / float64(leverage.Value); margin > 0 {
+			entry.ReturnOnEquity = risk.UnrealizedPnl / margin
+		}
+		entry.CumFunding.AllTime = p.allTimeFunds[risk.Coin]
+		entry.CumFunding.SinceOpne = position.sinceOpen
+		entry.CumFunding.SinceChan = position.sinceChange
+		state.AssetPositions = append(state.AssetPositions, AssetPosition{Position: entry, Type: "oneWay"})
+
+		totalRaw -= risk.Szi * risk.MarkPx
+		if risk.IsCross {
+			crossNotional += risk.PositionValue
+			crossRaw -= risk.Szi * risk.MarkPx
+		}
+	}
+	state.CrossMarginSummary = MarginSummary{
+		AccountValue:    report.CrossAccountValue,
+		TotalMarginUsed: report.CrossMarginUsed,
+		TotalNtlPos:     crossNotional,
+		TotalRawUsd:     crossRaw,
+	}
+	state.MarginSummary = MarginSummary{
+		AccountValue:    report.AccountValue,
+		TotalMarginUsed: report.CrossMarginUsed + report.IsolatedMarginUsed,
+		TotalNtlPos:     report.TotalNotional,
+		TotalRawUsd:     totalRaw,
+	}
+	return state, nil
+}
+
+// GetAccountOpenOrders returns the open orders in the format of GetOpenOrders
+func (p *PaperExchange) GetAccountOpenOrders() (*[]Order, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	orders := make([]Order, 0, len(p.orders))
+	for _, order := range p.orders {
+		orders = append(orders, order.info())
+	}
+	return &orders, nil
+}
+
+// GetAccountFills returns all simulated fills in the format of GetUserFills
+func (p *PaperExchange) GetAccountFills() (*[]OrderFill, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	fills := append([]OrderFill(nil), p.fills...)
+	return &fills, nil
+}
+
+// MarketPx returns the mark price of coin, 0 if there is no market data
+func (p *PaperExchange) MarketPx(coin string) float64 {
+	p.mu.Lock()
+	defer p.unlock()
+	return p.marks[coin]
+}
+
+//
+// IExchangeAPI
+//
+
+// BulkOrders places orders in bulk
+func (p *PaperExchange) BulkOrders(requests []OrderRequest, grouping Grouping, isSpot bool) (*OrderResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	statuses := make([]StatusResponse, 0, len(requests))
+	for _, request := range requests {
+		statuses = append(statuses, p.placeOrder(request, isSpot))
+	}
+	return orderResponse("order", statuses), nil
+}
+
+// Order places a single order
+func (p *PaperExchange) Order(request OrderRequest, grouping Grouping) (*OrderResponse, error) {
+	return p.BulkOrders([]OrderRequest{request}, grouping, false)
+}
+
+// MarketOrder places an Ioc order at the mark price plus slippage, see ExchangeAPI.MarketOrder
+func (p *PaperExchange) MarketOrder(coin string, size float64, slippage *float64, clientOID ...string) (*OrderResponse, error) {
+	isBuy := IsBuy(size)
+	orderRequest := OrderRequest{
+		Coin:      coin,
+		IsBuy:     isBuy,
+		Sz:        math.Abs(size),
+		LimitPx:   CalculateSlippage(isBuy, p.MarketPx(coin), GetSlippage(slippage)),
+		OrderType: OrderType{Limit: &LimitOrderType{Tif: TifIoc}},
+	}
+	if len(clientOID) > 0 {
+		orderRequest.Cloid = clientOID[0]
+	}
+	return p.Order(orderRequest, GroupingNa)
+}
+
+// LimitOrder places a limit order, see ExchangeAPI.LimitOrder
+func (p *PaperExchange) LimitOrder(orderType string, coin string, size float64, px float64, reduceOnly bool, clientOID ...string) (*OrderResponse, error) {
+	if orderType != TifGtc && orderType != TifIoc && orderType != TifAlo {
+		return nil, APIError{Message: fmt.Sprintf("Invalid order type: %s. Available types: %s, %s, %s", orderType, TifGtc, TifIoc, TifAlo)}
+	}
+	orderRequest := OrderRequest{
+		Coin:       coin,
+		IsBuy:      IsBuy(size),
+		Sz:         math.Abs(size),
+		LimitPx:    px,
+		OrderType:  OrderType{Limit: &LimitOrderType{Tif: orderType}},
+		ReduceOnly: reduceOnly,
+	}
+	if len(clientOID) > 0 {
+		orderRequest.Cloid = clientOID[0]
+	}
+	return p.Order(orderRequest, GroupingNa)
+}
+
+// CancelOrderByOID cancels an order by oid
+func (p *PaperExchange) CancelOrderByOID(coin string, orderID int64) (*OrderResponse, error) {
+	return p.BulkCancelOrders([]CancelOidWire{{Asset: p.assetId(coin), Oid: int(orderID)}})
+}
+
+// CancelOrderByCloid cancels an order by cloid
+func (p *PaperExchange) CancelOrderByCloid(coin string, clientOID string) (*OrderResponse, error) {
+	return p.BulkCancelOrdersByCloid([]CancelCloidWire{{Asset: p.assetId(coin), Cloid: clientOID}})
+}
+
+// BulkCancelOrders cancels orders by oid
+func (p *PaperExchange) BulkCancelOrders(cancels []CancelOidWire) (*OrderResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	statuses := make([]StatusResponse, 0, len(cancels))
+	for _, cancel := range cancels {
+		statuses = append(statuses, p.cancel(cancel.Asset, int64(cancel.Oid), ""))
+	}
+	return orderResponse("cancel", statuses), nil
+}
+
+// BulkCancelOrdersByCloid cancels orders by cloid
+func (p *PaperExchange) BulkCancelOrdersByCloid(cancels []CancelCloidWire) (*OrderResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	statuses := make([]StatusResponse, 0, len(cancels))
+	for _, cancel := range cancels {
+		statuses = append(statuses, p.cancel(cancel.Asset, 0, cancel.Cloid))
+	}
+	return orderResponse("cancel", statuses), nil
+}
+
+// BulkModifyOrders replaces orders addressed by OrderId, or by OrderCloid if it is set.
+// The modified orders get new oids and lose their time priority.
+func (p *PaperExchange) BulkModifyOrders(modifyRequests []ModifyOrderRequest, isSpot bool) (*OrderResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	statuses := make([]StatusResponse, 0, len(modifyRequests))
+	for _, modify := range modifyRequests {
+		order := p.findOrder(int64(modify.OrderId), modify.OrderCloid)
+		if order == nil {
+			statuses = append(statuses, StatusResponse{Error: fmt.Sprintf("Cannot modify canceled or filled order. asset=%d", p.assetId(modify.Coin))})
+			continue
+		}
+		p.removeOrder(order, "canceled")
+		statuses = append(statuses, p.placeOrder(OrderRequest{
+			Coin:         modify.Coin,
+			IsBuy:        modify.IsBuy,
+			Sz:           modify.Sz,
+			LimitPx:      modify.LimitPx,
+			OrderType:    modify.OrderType,
+			ReduceOnly:   modify.ReduceOnly,
+			Cloid:        modify.Cloid,
+			Builder:      order.request.Builder,
+			ExactSz:      modify.ExactSz,
+			ExactLimitPx: modify.ExactLimitPx,
+		}, isSpot))
+	}
+	return orderResponse("order", statuses), nil
+}
+
+// Replace reprices or resizes the open order with the cloid of request
+func (p *PaperExchange) Replace(request OrderRequest) (*OrderResponse, error) {
+	return p.BulkReplace([]OrderRequest{request}, false)
+}
+
+// BulkReplace replaces every order addressed by the cloid of its request
+func (p *PaperExchange) BulkReplace(requests []OrderRequest, isSpot bool) (*OrderResponse, error) {
+	modifies, err := replaceRequests(requests)
+	if err != nil {
+		return nil, err
+	}
+	return p.BulkModifyOrders(modifies, isSpot)
+}
+
+// CancelAllOrdersByCoin cancels all open orders of coin
+func (p *PaperExchange) CancelAllOrdersByCoin(coin string) (*OrderResponse, error) {
+	return p.cancelAll(func(order *paperOrder) bool { return order.request.Coin == coin })
+}
+
+// CancelAllOrders cancels all open orders
+func (p *PaperExchange) CancelAllOrders() (*OrderResponse, error) {
+	response, err := p.cancelAll(func(order *paperOrder) bool { return true })
+	if err == nil && len(response.Response.Data.Statuses) == 0 {
+		return nil, APIError{Message: "No open orders to cancel"}
+	}
+	return response, err
+}
+
+// cancelAll cancels the open orders matching filter
+func (p *PaperExchange) cancelAll(filter func(order *paperOrder) bool) (*OrderResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	var statuses []StatusResponse
+	for _, order := range append([]*paperOrder(nil), p.orders...) {
+		if filter(order) {
+			statuses = append(statuses, p.cancel(p.assetId(order.request.Coin), order.oid, ""))
+		}
+	}
+	return orderResponse("cancel", statuses), nil
+}
+
+// ClosePosition closes the position in coin with a reduce only market order
+func (p *PaperExchange) ClosePosition(coin string) (*OrderResponse, error) {
+	p.mu.Lock()
+	position, exists := p.positions[coin]
+	szi := 0.0
+	if exists {
+		szi = position.szi
+	}
+	p.unlock()
+	if szi == 0 {
+		return nil, APIError{Message: fmt.Sprintf("No position found for %s", coin)}
+	}
+	isBuy := !IsBuy(szi)
+	return p.Order(OrderRequest{
+		Coin:       coin,
+		IsBuy:      isBuy,
+		Sz:         math.Abs(szi),
+		LimitPx:    CalculateSlippage(isBuy, p.MarketPx(coin), GetSlippage(nil)),
+		OrderType:  OrderType{Limit: &LimitOrderType{Tif: TifIoc}},
+		ReduceOnly: true,
+	}, GroupingNa)
+}
+
+// Withdraw removes amount from the withdrawable balance
+func (p *PaperExchange) Withdraw(destination string, amount float64) (*WithdrawResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	if amount <= 0 || amount > p.availableMargin() {
+		return nil, APIError{Message: fmt.Sprintf("Insufficient balance for withdrawal of %v", amount)}
+	}
+	p.balance -= amount
+	return &WithdrawResponse{Status: "ok", Nonce: int64(GetNonce())}, nil
+}
+
+// UpdateLeverage sets the leverage of coin. The margin type of an open position can not be changed,
+// the isolated margin of an open position is topped up to the new leverage.
+func (p *PaperExchange) UpdateLeverage(coin string, isCross bool, leverage int) (*DefaultExchangeResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	maxLeverage := p.calc.defaultLeverage(coin).Value
+	if asset, exists := p.calc.assets[coin]; exists {
+		maxLeverage = asset.MaxLeverage
+		if isCross && asset.OnlyIsolated {
+			return nil, APIError{Message: fmt.Sprintf("Cross margin is not allowed for %s", coin)}
+		}
+	}
+	if leverage < 1 || leverage > maxLeverage {
+		return nil, APIError{Message: fmt.Sprintf("Invalid leverage value %d for %s", leverage, coin)}
+	}
+	leverageType := "isolated"
+	if isCross {
+		leverageType = "cross"
+	}
+	if position, exists := p.positions[coin]; exists {
+		if position.isCross != isCross {
+			return nil, APIError{Message: "Cannot switch leverage type with open position."}
+		}
+		old := position.leverage
+		position.leverage = leverage
+		required := position.initialMargin()
+		if isCross {
+			if leverage < old && p.availableMargin() < 0 {
+				position.leverage = old
+				return nil, APIError{Message: "Insufficient margin to decrease leverage."}
+			}
+		} else if topUp := required - position.isolatedMargin - position.unrealizedPnl(); topUp > 0 {
+			if topUp > p.availableMargin() {
+				position.leverage = old
+				return nil, APIError{Message: "Insufficient margin to decrease leverage."}
+			}
+			position.isolatedMargin += topUp
+			p.balance -= topUp
+		}
+	}
+	p.leverage[coin] = Leverage{Type: leverageType, Value: leverage}
+	return defaultResponse(), nil
+}
+
+// UpdateIsolatedMargin moves amount of USDC into the isolated position in coin, negative to remove margin
+func (p *PaperExchange) UpdateIsolatedMargin(coin string, amount float64) (*DefaultExchangeResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	if err := p.moveIsolatedMargin(coin, amount); err != nil {
+		return nil, err
+	}
+	return defaultResponse(), nil
+}
+
+// TopUpIsolatedOnlyMargin moves margin into the isolated position in coin until it has the given leverage
+func (p *PaperExchange) TopUpIsolatedOnlyMargin(coin string, leverage float64) (*DefaultExchangeResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	position, exists := p.positions[coin]
+	if !exists || leverage <= 0 {
+		return nil, APIError{Message: fmt.Sprintf("No isolated position found for %s", coin)}
+	}
+	target := math.Abs(position.szi) * position.markPx / leverage
+	if err := p.moveIsolatedMargin(coin, target-position.isolatedMargin-position.unrealizedPnl()); err != nil {
+		return nil, err
+	}
+	return defaultResponse(), nil
+}
+
+// moveIsolatedMargin moves amount between the cross account and the isolated position in coin
+func (p *PaperExchange) moveIsolatedMargin(coin string, amount float64) error {
+	position, exists := p.positions[coin]
+	if !exists || position.isCross {
+		return APIError{Message: fmt.Sprintf("No isolated position found for %s", coin)}
+	}
+	if amount > 0 && amount > p.availableMargin() {
+		return APIError{Message: "Insufficient margin to add to isolated position."}
+	}
+	if amount < 0 && position.isolatedMargin+position.unrealizedPnl()+amount < position.initialMargin() {
+		return APIError{Message: "Insufficient margin to remove from isolated position."}
+	}
+	position.isolatedMargin += amount
+	p.balance -= amount
+	return nil
+}
+
+// ScheduleCancel cancels all open orders once the market data reaches at. A zero time removes the scheduled cancel.
+func (p *PaperExchange) ScheduleCancel(at time.Time) (*DefaultExchangeResponse, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	if at.IsZero() {
+		p.cancelAt = 0
+		return defaultResponse(), nil
+	}
+	if at.UnixMilli() < p.time()+5000 {
+		return nil, APIError{Message: "Scheduled cancel time too early, must be at least 5 seconds from now."}
+	}
+	p.cancelAt = at.UnixMilli()
+	return defaultResponse(), nil
+}
+
+// ApproveBuilderFee approves a maximum fee rate for a builder, e.g. "0.01%"
+func (p *PaperExchange) ApproveBuilderFee(builder string, maxFeeRate string) (*DefaultExchangeResponse, error) {
+	if !strings.HasSuffix(maxFeeRate, "%") {
+		return nil, APIError{Message: fmt.Sprintf("Max fee rate must be a percentage, got %s", maxFeeRate)}
+	}
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(maxFeeRate, "%"), 64)
+	if err != nil {
+		return nil, APIError{Message: fmt.Sprintf("Invalid max fee rate %s", maxFeeRate)}
+	}
+	p.mu.Lock()
+	defer p.unlock()
+	// Tenths of a basis point
+	p.builderFees[strings.ToLower(builder)] = int(math.Round(percent * 1000))
+	return defaultResponse(), nil
+}
+
+// GetApprovedBuilderFee returns the maximum fee approved for builder in tenths of a basis point
+func (p *PaperExchange) GetApprovedBuilderFee(builder string) (*int, error) {
+	p.mu.Lock()
+	defer p.unlock()
+	fee := p.builderFees[strings.ToLower(builder)]
+	return &fee, nil
+}
+
+// BuildL1Envelope is not supported by the paper exchange
+func (p *PaperExchange) BuildL1Envelope(action any) (*ActionEnvelope, error) {
+	return nil, ErrPaperSigning
+}
+
+// BuildOrderEnvelope is not supported by the paper exchange
+func (p *PaperExchange) BuildOrderEnvelope(requests []OrderRequest, grouping Grouping, isSpot bool) (*ActionEnvelope, error) {
+	return nil, ErrPaperSigning
+}
+
+// BuildWithdrawEnvelope is not supported by the paper exchange
+func (p *PaperExchange) BuildWithdrawEnvelope(destination string, amount float64) (*ActionEnvelope, error) {
+	return nil, ErrPaperSigning
+}
+
+// SignEnvelope is not supported by the paper exchange
+func (p *PaperExchange) SignEnvelope(envelope *ActionEnvelope) (RsvSignature, error) {
+	return RsvSignature{}, ErrPaperSigning
+}
@@ -0,0 +1,312 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// paperBookAt returns a BTC book with a spread of 20 around mid
+func paperBookAt(t *testing.T, mid float64, ms int64) L2BookSnapshot {
+	var book L2BookSnapshot
+	data := fmt.Appendf(nil, `{"coin":"BTC","time":%d,"levels":[`+
+		`[{"px":"%v","sz":"1","n":1},{"px":"%v","sz":"2","n":1}],`+
+		`[{"px":"%v","sz":"0.5","n":1},{"px":"%v","sz":"1","n":1}]]}`, ms, mid-10, mid-20, mid+10, mid+20)
+	if err := FastUnmarshal(data, &book); err != nil {
+		t.Fatalf("Failed to decode book: %v", err)
+	}
+	return book
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+// TestPaperExchange tests matching, fees, margin and funding of the paper exchange
+func TestPaperExchange(t *testing.T) {
+	var exchange IExchangeAPI
+	paper := NewPaperExchange(PaperConfig{
+		Balance: 10000,
+		Meta:    &Meta{Universe: []Asset{{Name: "BTC", SzDecimals: 5, MaxLeverage: 50}}},
+	})
+	exchange = paper
+	var fills []OrderFill
+	var updates []OrderUpdate
+	paper.OnFill(func(fill OrderFill) { fills = append(fills, fill) })
+	paper.OnOrderUpdate(func(update OrderUpdate) { updates = append(updates, update) })
+	start := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC).UnixMilli()
+	paper.UpdateBook(paperBookAt(t, 50000, start))
+
+	// Taker order sweeps two levels
+	response, err := exchange.LimitOrder(TifIoc, "BTC", 1, 50020, false)
+	if err != nil {
+		t.Fatalf("LimitOrder failed: %v", err)
+	}
+	filled := response.Response.Data.Statuses[0].Filled
+	if filled.TotalSz != 1 || filled.AvgPx != 50015 {
+		t.Errorf("Expected 1 filled at 50015, got %+v", response.Response.Data.Statuses[0])
+	}
+	if len(fills) != 2 || !fills[0].Crossed || fills[1].Px != 50020 || fills[0].Dir != "Open Long" {
+		t.Errorf("Expected 2 taker fills, got %+v", fills)
+	}
+	takerFee := 50015 * PAPER_TAKER_FEE
+
+	// Resting order is filled by a trade through its price
+	response, _ = exchange.LimitOrder(TifGtc, "BTC", -0.4, 50100, false, "0x00000000000000000000000000000001")
+	if response.Response.Data.Statuses[0].Resting.OrderId == 0 {
+		t.Fatalf("Expected resting order, got %+v", response.Response.Data.Statuses[0])
+	}
+	paper.AddTrades([]Trade{{Coin: "BTC", Side: "A", Px: 50150, Sz: 1, Time: start + 1}})
+	if len(fills) != 2 {
+		t.Error("Expected no fill from a selling taker")
+	}
+	paper.AddTrades([]Trade{{Coin: "BTC", Side: "B", Px: 50120, Sz: 1, Time: start + 2}})
+	if len(fills) != 3 || fills[2].Crossed || fills[2].Px != 50100 || fills[2].Cloid != "0x00000000000000000000000000000001" {
+		t.Fatalf("Expected maker fill at 50100, got %+v", fills)
+	}
+	if !almostEqual(fills[2].ClosedPnl, 0.4*85) || fills[2].Dir != "Close Long" {
+		t.Errorf("Expected closed pnl of 34, got %+v", fills[2])
+	}
+	if last := updates[len(updates)-1]; last.Status != "filled" || last.Order.Oid != int64(fills[2].Oid) {
+		t.Errorf("Expected filled order update, got %+v", last)
+	}
+	makerFee := 0.4 * 50100 * PAPER_MAKER_FEE
+
+	state, _ := paper.GetAccountState()
+	if len(state.AssetPositions) != 1 {
+		t.Fatalf("Expected 1 position, got %+v", state.AssetPositions)
+	}
+	position := state.AssetPositions[0].Position
+	if !almostEqual(position.Szi, 0.6) || position.EntryPx != 50015 || position.Leverage.Value != DEFAULT_LEVERAGE {
+		t.Errorf("Unexpected position %+v", position)
+	}
+	// Marked at the mid of the book
+	expected := 10000 + 34 - takerFee - makerFee + 0.6*(50000-50015)
+	if !almostEqual(state.MarginSummary.AccountValue, expected) {
+		t.Errorf("Expected account value %v, got %v", expected, state.MarginSummary.AccountValue)
+	}
+	if !almostEqual(state.MarginSummary.TotalMarginUsed, 0.6*50000/DEFAULT_LEVERAGE) {
+		t.Errorf("Unexpected margin used %v", state.MarginSummary.TotalMarginUsed)
+	}
+
+	// Rejections
+	response, _ = exchange.LimitOrder(TifGtc, "BTC", 10, 49000, false)
+	if response.Response.Data.Statuses[0].Error == "" {
+		t.Error("Expected insufficient margin")
+	}
+	response, _ = exchange.LimitOrder(TifAlo, "BTC", 0.1, 50020, false)
+	if response.Response.Data.Statuses[0].Error == "" {
+		t.Error("Expected post only rejection")
+	}
+	response, _ = exchange.LimitOrder(TifGtc, "BTC", 0.1, 50000, true)
+	if response.Response.Data.Statuses[0].Error == "" {
+		t.Error("Expected reduce only rejection")
+	}
+	if _, err := exchange.CancelAllOrders(); err == nil {
+		t.Error("Expected error without open orders")
+	}
+
+	// Funding is settled at the full hour with the latest rate
+	paper.UpdateAssetCtx(ActiveAssetCtx{Coin: "BTC", Ctx: PerpAssetCtx{Funding: 0.0001, MarkPx: 50000}})
+	paper.UpdateBook(paperBookAt(t, 50000, start+30*60*1000))
+	state, _ = paper.GetAccountState()
+	if funding := state.AssetPositions[0].Position.CumFunding.SinceOpne; !almostEqual(funding, 0.6*50000*0.0001) {
+		t.Errorf("Expected funding of 3, got %v", funding)
+	}
+
+	// Stop loss fires on the mark and closes the position
+	exchange.Order(OrderRequest{
+		Coin:       "BTC",
+		Sz:         0.6,
+		LimitPx:    48000,
+		ReduceOnly: true,
+		Cloid:      "0x00000000000000000000000000000002",
+		OrderType:  OrderType{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: "49500", TpSl: TriggerSl}},
+	}, GroupingNa)
+	orders, _ := paper.GetAccountOpenOrders()
+	if len(*orders) != 1 || (*orders)[0].OrderType != "Stop Market" {
+		t.Fatalf("Expected open stop order, got %+v", orders)
+	}
+	paper.UpdateBook(paperBookAt(t, 49600, start+30*60*1000+1))
+	if orders, _ = paper.GetAccountOpenOrders(); len(*orders) != 1 {
+		t.Error("Expected stop order not to fire above its trigger price")
+	}
+	paper.UpdateBook(paperBookAt(t, 49400, start+30*60*1000+2))
+	state, _ = paper.GetAccountState()
+	if orders, _ = paper.GetAccountOpenOrders(); len(*orders) != 0 || len(state.AssetPositions) != 0 {
+		t.Errorf("Expected stop order to close the position, got %+v", state.AssetPositions)
+	}
+	if last := fills[len(fills)-1]; last.Px != 49390 || last.Dir != "Close Long" {
+		t.Errorf("Expected stop fill at the best bid, got %+v", last)
+	}
+
+	if _, err := exchange.SignEnvelope(&ActionEnvelope{}); err != ErrPaperSigning {
+		t.Errorf("Expected ErrPaperSigning, got %v", err)
+	}
+}
+
+// TestPaperExchangeIsolatedFunding tests that isolated positions pay funding from their own margin
+func TestPaperExchangeIsolatedFunding(t *testing.T) {
+	paper := NewPaperExchange(PaperConfig{Balance: 10000})
+	start := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC).UnixMilli()
+	paper.UpdateBook(paperBookAt(t, 50000, start))
+	if _, err := paper.UpdateLeverage("BTC", false, 10); err != nil {
+		t.Fatalf("UpdateLeverage failed: %v", err)
+	}
+	if response, _ := paper.LimitOrder(TifIoc, "BTC", 0.5, 50010, false); response.Response.Data.Statuses[0].Filled.TotalSz != 0.5 {
+		t.Fatalf("Expected 0.5 filled, got %+v", response.Response.Data.Statuses[0])
+	}
+	paper.UpdateAssetCtx(ActiveAssetCtx{Coin: "BTC", Ctx: PerpAssetCtx{Funding: 0.0001, MarkPx: 50000}})
+	before, _ := paper.GetAccountState()
+
+	paper.UpdateBook(paperBookAt(t, 50000, start+30*60*1000))
+	after, _ := paper.GetAccountState()
+	payment := 0.5 * 50000 * 0.0001
+	if funding := after.AssetPositions[0].Position.CumFunding.SinceOpne; !almostEqual(funding, payment) {
+		t.Errorf("Expected funding of %v, got %v", payment, funding)
+	}
+	if !almostEqual(after.CrossMarginSummary.AccountValue, before.CrossMarginSummary.AccountValue) {
+		t.Errorf("Expected the cross account to be unchanged, got %v and %v",
+			before.CrossMarginSummary.AccountValue, after.CrossMarginSummary.AccountValue)
+	}
+	if margin := after.AssetPositions[0].Position.MarginUsed; !almostEqual(margin, before.AssetPositions[0].Position.MarginUsed-payment) {
+		t.Errorf("Expected isolated margin to pay the funding, got %v", margin)
+	}
+	if !almostEqual(after.MarginSummary.AccountValue, before.MarginSummary.AccountValue-payment) {
+		t.Errorf("Expected account value to drop by the funding, got %v", after.MarginSummary.AccountValue)
+	}
+}
+
+// TestPaperExchangeFundingGap tests that a gap in the market data settles funding for every elapsed hour
+func TestPaperExchangeFundingGap(t *testing.T) {
+	paper := NewPaperExchange(PaperConfig{Balance: 10000})
+	start := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC).UnixMilli()
+	paper.UpdateBook(paperBookAt(t, 50000, start))
+	paper.LimitOrder(TifIoc, "BTC", 0.5, 50010, false)
+	paper.UpdateAssetCtx(ActiveAssetCtx{Coin: "BTC", Ctx: PerpAssetCtx{Funding: 0.0001, MarkPx: 50000}})
+	before, _ := paper.GetAccountState()
+
+	paper.UpdateBook(paperBookAt(t, 50000, start+3*60*60*1000))
+	after, _ := paper.GetAccountState()
+	payment := 3 * 0.5 * 50000 * 0.0001
+	if funding := after.AssetPositions[0].Position.CumFunding.SinceOpne; !almostEqual(funding, payment) {
+		t.Errorf("Expected 3 hours of funding of %v, got %v", payment, funding)
+	}
+	if !almostEqual(after.MarginSummary.AccountValue, before.MarginSummary.AccountValue-payment) {
+		t.Errorf("Expected account value to drop by %v, got %v", payment, after.MarginSummary.AccountValue-before.MarginSummary.AccountValue)
+	}
+}
+
+// TestPaperExchangeReduceOnly tests that resting reduce only orders never open or increase a position
+func TestPaperExchangeReduceOnly(t *testing.T) {
+	paper := NewPaperExchange(PaperConfig{Balance: 100000})
+	canceled := 0
+	paper.OnOrderUpdate(func(update OrderUpdate) {
+		if update.Status == "reduceOnlyCanceled" {
+			canceled++
+		}
+	})
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	szi := func() float64 {
+		state, _ := paper.GetAccountState()
+		if len(state.AssetPositions) == 0 {
+			return 0
+		}
+		return state.AssetPositions[0].Position.Szi
+	}
+	openOrders := func() int {
+		orders, _ := paper.GetAccountOpenOrders()
+		return len(*orders)
+	}
+	lastFillSz := func() float64 {
+		fills, _ := paper.GetAccountFills()
+		return (*fills)[len(*fills)-1].Sz
+	}
+
+	// A resting reduce only order is canceled once the position is closed
+	paper.UpdateBook(paperBookAt(t, 50000, start))
+	paper.LimitOrder(TifIoc, "BTC", 1, 50020, false)
+	paper.LimitOrder(TifGtc, "BTC", -1, 51000, true)
+	if _, err := paper.ClosePosition("BTC"); err != nil {
+		t.Fatalf("ClosePosition failed: %v", err)
+	}
+	if openOrders() != 0 || canceled != 1 {
+		t.Errorf("Expected the reduce only order to be canceled, got %d open and %d canceled", openOrders(), canceled)
+	}
+	paper.UpdateBook(paperBookAt(t, 51100, start+1))
+	if szi() != 0 {
+		t.Errorf("Expected no position, got %v", szi())
+	}
+
+	// The book sweep fills a reduce only order up to the position
+	paper.UpdateBook(paperBookAt(t, 50000, start+2))
+	paper.LimitOrder(TifIoc, "BTC", 1, 50020, false)
+	paper.LimitOrder(TifGtc, "BTC", -1, 51000, true)
+	paper.LimitOrder(TifIoc, "BTC", -0.4, 49990, false)
+	paper.UpdateBook(paperBookAt(t, 51100, start+3))
+	if !almostEqual(lastFillSz(), 0.6) || szi() != 0 || openOrders() != 0 || canceled != 2 {
+		t.Errorf("Expected a reduce only fill of 0.6 and the rest canceled, got %v, %v, %d open and %d canceled",
+			lastFillSz(), szi(), openOrders(), canceled)
+	}
+
+	// A trade that flips the position cancels the reduce only order behind it
+	paper.UpdateBook(paperBookAt(t, 50000, start+4))
+	paper.LimitOrder(TifIoc, "BTC", 1, 50020, false)
+	paper.LimitOrder(TifGtc, "BTC", -1, 50500, true)
+	paper.LimitOrder(TifGtc, "BTC", -2, 50400, false)
+	paper.AddTrades([]Trade{{Coin: "BTC", Side: "B", Px: 50600, Sz: 5, Time: start + 5}})
+	if !almostEqual(szi(), -1) || openOrders() != 0 || canceled != 3 {
+		t.Errorf("Expected a short of 1 and the reduce only order canceled, got %v, %d open and %d canceled", szi(), openOrders(), canceled)
+	}
+
+	// Trades fill a reduce only order up to the position
+	paper.LimitOrder(TifGtc, "BTC", 1, 50000, true)
+	paper.LimitOrder(TifIoc, "BTC", 0.4, 50020, false)
+	paper.AddTrades([]Trade{{Coin: "BTC", Side: "A", Px: 49900, Sz: 5, Time: start + 6}})
+	if !almostEqual(lastFillSz(), 0.6) || szi() != 0 || openOrders() != 0 || canceled != 4 {
+		t.Errorf("Expected a reduce only fill of 0.6 and the rest canceled, got %v, %v, %d open and %d canceled",
+			lastFillSz(), szi(), openOrders(), canceled)
+	}
+}
+
+// TestPaperExchangeOrderManagement tests modifying and canceling simulated orders
+func TestPaperExchangeOrderManagement(t *testing.T) {
+	paper := NewPaperExchange(PaperConfig{Balance: 10000})
+	paper.UpdateBook(paperBookAt(t, 50000, 1000))
+	cloid := "0x00000000000000000000000000000003"
+	paper.LimitOrder(TifGtc, "BTC", 0.1, 49000, false, cloid)
+	response, _ := paper.LimitOrder(TifGtc, "BTC", 0.1, 48000, false, cloid)
+	if response.Response.Data.Statuses[0].Error == "" {
+		t.Error("Expected duplicate cloid rejection")
+	}
+
+	response, err := paper.Replace(OrderRequest{Coin: "BTC", IsBuy: true, Sz: 0.2, LimitPx: 49500, Cloid: cloid, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}})
+	if err != nil || response.Response.Data.Statuses[0].Resting.OrderId == 0 {
+		t.Fatalf("Replace failed: %v %+v", err, response)
+	}
+	orders, _ := paper.GetAccountOpenOrders()
+	if len(*orders) != 1 || (*orders)[0].LimitPx != 49500 || (*orders)[0].Sz != 0.2 {
+		t.Errorf("Expected replaced order, got %+v", orders)
+	}
+
+	response, _ = paper.CancelOrderByCloid("BTC", cloid)
+	if response.Response.Data.Statuses[0].Status != "success" {
+		t.Errorf("Expected successful cancel, got %+v", response.Response.Data.Statuses[0])
+	}
+	response, _ = paper.CancelOrderByCloid("BTC", cloid)
+	if response.Response.Data.Statuses[0].Error == "" {
+		t.Error("Expected error canceling a canceled order")
+	}
+
+	// Scheduled cancel fires with the market data time
+	paper.LimitOrder(TifGtc, "BTC", 0.1, 49000, false)
+	if _, err := paper.ScheduleCancel(time.UnixMilli(2000)); err == nil {
+		t.Error("Expected error for a scheduled cancel less than 5 seconds ahead")
+	}
+	paper.ScheduleCancel(time.UnixMilli(10000))
+	paper.UpdateBook(paperBookAt(t, 50000, 10000))
+	if orders, _ = paper.GetAccountOpenOrders(); len(*orders) != 0 {
+		t.Errorf("Expected scheduled cancel, got %+v", orders)
+	}
+}
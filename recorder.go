@@ -0,0 +1,385 @@
+package hyperliquid
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults of RecorderConfig
+const (
+	RECORDER_SEGMENT_SIZE = 256 << 20 // Uncompressed bytes per segment
+	RECORDER_SEGMENT_AGE  = time.Hour
+	RECORDER_BUFFER_SIZE  = 10000 // Messages queued for the writer before dropping
+)
+
+// recorderFlushInterval bounds the data lost when the process dies without closing the recorder
+const recorderFlushInterval = time.Second
+
+// recorderTimeLayout names segments so that sorting by name sorts them by time
+const recorderTimeLayout = "20060102T150405.000Z"
+
+// ErrRecorderClosed is returned by Close when the recorder was already closed
+var ErrRecorderClosed = errors.New("recorder closed")
+
+// RecorderConfig configures a Recorder
+type RecorderConfig struct {
+	Dir         string        // Directory of the segment files, created if missing
+	Prefix      string        // File name prefix of the segments, "hyperliquid" if empty
+	Channels    []string      // Recorded channels, e.g. "l2Book" or "trades", all if empty
+	SegmentSize int64         // Uncompressed bytes after which a new segment starts, RECORDER_SEGMENT_SIZE if 0
+	SegmentAge  time.Duration // Age after which a new segment starts, RECORDER_SEGMENT_AGE if 0
+	BufferSize  int           // Messages queued for the writer, RECORDER_BUFFER_SIZE if 0
+}
+
+// RecordedMessage is a line of a segment file
+type RecordedMessage struct {
+	Time    int64           `json:"t"` // Receive time in unix nanoseconds
+	Message json.RawMessage `json:"m"` // WebSocket message exactly as received
+}
+
+// Recorder writes WebSocket subscription messages to gzip compressed JSONL segment files named
+// <prefix>-<UTC start time>-<sequence>.jsonl.gz, one RecordedMessage per line. A new segment
+// starts once the current one exceeds the configured size or age.
+//
+// Messages are written on a separate goroutine, so recording never blocks the read loop.
+// If the writer falls behind by more than BufferSize messages, further messages are dropped
+// and counted, see Dropped.
+type Recorder struct {
+	config   RecorderConfig
+	channels map[string]bool
+	queue    chan RecordedMessage
+	done     chan struct{}
+	dropped  atomic.Int64
+
+	mu     sync.RWMutex // Guards closed against sends on the closed queue
+	closed bool
+
+	// Owned by the writer goroutine
+	file     *os.File
+	gz       *gzip.Writer
+	size     int64
+	started  time.Time
+	sequence int
+	err      error
+}
+
+// NewRecorder creates the segment directory and starts the writer of a Recorder.
+// Attach it to a WebSocketAPI with SetRecorder and close it to flush the last segment.
+func NewRecorder(config RecorderConfig) (*Recorder, error) {
+	if config.Dir == "" {
+		return nil, errors.New("recorder directory is required")
+	}
+	if config.Prefix == "" {
+		config.Prefix = "hyperliquid"
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = RECORDER_SEGMENT_SIZE
+	}
+	if config.SegmentAge <= 0 {
+		config.SegmentAge = RECORDER_SEGMENT_AGE
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = RECORDER_BUFFER_SIZE
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recorder directory: %w", err)
+	}
+
+	r := &Recorder{
+		config: config,
+		queue:  make(chan RecordedMessage, config.BufferSize),
+		done:   make(chan struct{}),
+	}
+	if len(config.Channels) > 0 {
+		r.channels = make(map[string]bool, len(config.Channels))
+		for _, channel := range config.Channels {
+			r.channels[channel] = true
+		}
+	}
+	go r.run()
+	return r, nil
+}
+
+// record queues a copy of message, the read loop reuses its buffer
+func (r *Recorder) record(channel string, message []byte) {
+	if r.channels != nil && !r.channels[channel] {
+		return
+	}
+	recorded := RecordedMessage{Time: time.Now().UnixNano(), Message: bytes.Clone(message)}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- recorded:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of messages dropped because the writer fell behind
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close writes the queued messages, closes the current segment and returns the first write error.
+// Detach the recorder from the WebSocketAPI before, messages recorded after Close are ignored.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ErrRecorderClosed
+	}
+	r.closed = true
+	close(r.queue)
+	r.mu.Unlock()
+
+	<-r.done
+	return r.err
+}
+
+// run writes queued messages until the queue is closed
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(recorderFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case recorded, ok := <-r.queue:
+			if !ok {
+				r.setErr(r.closeSegment())
+				return
+			}
+			r.setErr(r.write(recorded))
+		case <-ticker.C:
+			if r.gz != nil {
+				r.setErr(r.gz.Flush())
+			}
+		}
+	}
+}
+
+// setErr keeps the first write error, later messages are still attempted
+func (r *Recorder) setErr(err error) {
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// write appends a line to the current segment, rotating it first if it is full or too old
+func (r *Recorder) write(recorded RecordedMessage) error {
+	received := time.Unix(0, recorded.Time)
+	if r.gz != nil && (r.size >= r.config.SegmentSize || received.Sub(r.started) >= r.config.SegmentAge) {
+		if err := r.closeSegment(); err != nil {
+			return err
+		}
+	}
+	if r.gz == nil {
+		if err := r.openSegment(received); err != nil {
+			return err
+		}
+	}
+
+	message := recorded.Message
+	if bytes.IndexByte(message, '\n') >= 0 {
+		// One message per line
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, message); err != nil {
+			return fmt.Errorf("failed to compact recorded message: %w", err)
+		}
+		message = compacted.Bytes()
+	}
+	line := make([]byte, 0, len(message)+32)
+	line = fmt.Appendf(line, `{"t":%d,"m":`, recorded.Time)
+	line = append(line, message...)
+	line = append(line, '}', '\n')
+	n, err := r.gz.Write(line)
+	r.size += int64(n)
+	return err
+}
+
+// openSegment creates a new segment file started at the receive time of its first message
+func (r *Recorder) openSegment(started time.Time) error {
+	r.sequence++
+	name := fmt.Sprintf("%s-%s-%04d.jsonl.gz", r.config.Prefix, started.UTC().Format(recorderTimeLayout), r.sequence)
+	file, err := os.OpenFile(filepath.Join(r.config.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %w", err)
+	}
+	r.file = file
+	r.gz = gzip.NewWriter(file)
+	r.size = 0
+	r.started = started
+	return nil
+}
+
+// closeSegment flushes and closes the current segment, if any
+func (r *Recorder) closeSegment() error {
+	if r.gz == nil {
+		return nil
+	}
+	err := r.gz.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.gz = nil
+	r.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close segment: %w", err)
+	}
+	return nil
+}
+
+// SegmentFiles returns the segment files of prefix in dir in recording order
+func SegmentFiles(dir, prefix string) ([]string, error) {
+	if prefix == "" {
+		prefix = "hyperliquid"
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix+"-") || !strings.HasSuffix(name, ".jsonl.gz") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Replayer feeds recorded segment files through the subscription handlers of its WebSocketAPI.
+// Subscribe to the replayer's WebSocketAPI like to a connected one, then call Replay. Subscribing
+// does not send anything and handlers are called synchronously in recording order, so a replay
+// delivers every recorded message of the subscribed channels, deterministically.
+//
+// Example:
+//
+//	files, _ := hyperliquid.SegmentFiles("recordings", "")
+//	replayer := hyperliquid.NewReplayer(files...)
+//	replayer.SetSpeed(10)
+//	replayer.WebSocketAPI().SubscribeTrades("BTC", onTrades)
+//	err := replayer.Replay(ctx)
+type Replayer struct {
+	ws    *WebSocketAPI
+	files []string
+	speed float64
+}
+
+// NewReplayer creates a Replayer of files, played in the given order at real speed
+func NewReplayer(files ...string) *Replayer {
+	ws := NewWebSocketAPI(true)
+	ws.replay = true
+	return &Replayer{ws: ws, files: files, speed: 1}
+}
+
+// WebSocketAPI returns the WebSocketAPI the recorded messages are fed to
+func (r *Replayer) WebSocketAPI() *WebSocketAPI {
+	return r.ws
+}
+
+// SetSpeed sets the replay speed relative to the recording, e.g. 10 for ten times faster.
+// With 0 messages are replayed as fast as the handlers process them.
+func (r *Replayer) SetSpeed(speed float64) {
+	r.speed = speed
+}
+
+// Replay feeds the messages of all files to the subscription handlers and returns once they
+// were all delivered or ctx is done. The gaps between messages are kept, scaled by the speed.
+func (r *Replayer) Replay(ctx context.Context) error {
+	var first int64
+	var start time.Time
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	for _, path := range r.files {
+		err := readSegment(path, func(recorded RecordedMessage) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if start.IsZero() {
+				first, start = recorded.Time, time.Now()
+			}
+			if r.speed > 0 {
+				due := start.Add(time.Duration(float64(recorded.Time-first) / r.speed))
+				if wait := time.Until(due); wait > 0 {
+					timer.Reset(wait)
+					select {
+					case <-timer.C:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+			r.ws.processJSONMessage(recorded.Message)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSegment calls fn with every message of a segment file, gzip compressed if it ends in .gz
+func readSegment(path string, fn func(RecordedMessage) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to open segment %s: %w", path, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	lines := bufio.NewReader(reader)
+	for {
+		line, err := lines.ReadBytes('\n')
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			// A segment cut off by a crash ends with a truncated gzip stream and line
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read segment %s: %w", path, err)
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			var recorded RecordedMessage
+			if err := FastUnmarshal(line, &recorded); err != nil {
+				return fmt.Errorf("failed to decode segment %s: %w", path, err)
+			}
+			if err := fn(recorded); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
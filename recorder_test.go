@@ -0,0 +1,114 @@
+package hyperliquid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRecordAndReplay tests that recorded messages are rotated into segments and replayed in order
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir, Channels: []string{"l2Book", "trades"}, SegmentSize: 300})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	ws := NewWebSocketAPI(true)
+	ws.SetRecorder(recorder)
+	for i := 1; i <= 5; i++ {
+		ws.processJSONMessage(fmt.Appendf(nil, `{"channel":"l2Book","data":{"coin":"BTC","time":%d,"levels":[[{"px":"%d","sz":"1","n":1}],[{"px":"%d","sz":"1","n":1}]]}}`, i, 49990+i, 50010+i))
+		ws.processJSONMessage(fmt.Appendf(nil, `{"channel":"trades","data":[{"coin":"BTC","side":"B","px":"%d","sz":"0.1","time":%d,"hash":"0x1","tid":%d}]}`, 50000+i, i, i))
+		ws.processJSONMessage([]byte(`{"channel":"bbo","data":{"coin":"BTC","time":1,"bbo":[null,null]}}`))
+	}
+	// Multi-line messages are compacted to one line
+	ws.processJSONMessage([]byte("{\"channel\":\"trades\",\n\"data\":[{\"coin\":\"ETH\",\"side\":\"A\",\"px\":\"2500\",\"sz\":\"1\",\"time\":6,\"hash\":\"0x2\",\"tid\":6}]}"))
+	ws.SetRecorder(nil)
+	ws.processJSONMessage([]byte(`{"channel":"trades","data":[{"coin":"BTC","side":"B","px":"1","sz":"1","time":7,"hash":"0x3","tid":7}]}`))
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := recorder.Close(); !errors.Is(err, ErrRecorderClosed) {
+		t.Errorf("Expected ErrRecorderClosed, got %v", err)
+	}
+	if recorder.Dropped() != 0 {
+		t.Errorf("Expected no dropped messages, got %d", recorder.Dropped())
+	}
+
+	files, err := SegmentFiles(dir, "")
+	if err != nil {
+		t.Fatalf("SegmentFiles failed: %v", err)
+	}
+	if len(files) < 2 {
+		t.Fatalf("Expected rotated segments, got %v", files)
+	}
+
+	replayer := NewReplayer(files...)
+	replayer.SetSpeed(0)
+	var events []string
+	replayed := replayer.WebSocketAPI()
+	if _, err := replayed.SubscribeOrderbook("BTC", func(data interface{}) {
+		book := data.(L2BookSnapshot)
+		events = append(events, fmt.Sprintf("book %d", book.Time))
+	}); err != nil {
+		t.Fatalf("Failed to subscribe offline: %v", err)
+	}
+	if _, err := replayed.SubscribeTrades("BTC", func(data interface{}) {
+		for _, trade := range data.([]Trade) {
+			events = append(events, fmt.Sprintf("trade %d", trade.Time))
+		}
+	}); err != nil {
+		t.Fatalf("Failed to subscribe offline: %v", err)
+	}
+	paper := NewPaperExchange(PaperConfig{Balance: 1000})
+	if _, err := paper.Follow(replayed, "BTC"); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if err := replayer.Replay(context.Background()); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	var expected []string
+	for i := 1; i <= 5; i++ {
+		expected = append(expected, fmt.Sprintf("book %d", i), fmt.Sprintf("trade %d", i))
+	}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Errorf("Expected events %v, got %v", expected, events)
+	}
+	if px := paper.MarketPx("BTC"); px != 50005 {
+		t.Errorf("Expected paper mark at the last replayed trade of 50005, got %v", px)
+	}
+}
+
+// TestReplaySpeed tests that replays keep the recorded gaps scaled by the speed
+func TestReplaySpeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	trade := `{"channel":"trades","data":[{"coin":"BTC","side":"B","px":"50000","sz":"1","time":1,"hash":"0x1","tid":1}]}`
+	lines := fmt.Sprintf("{\"t\":%d,\"m\":%s}\n{\"t\":%d,\"m\":%s}\n{\"t\":%d,\"m\":%s}\n",
+		base, trade, base+int64(200*time.Millisecond), trade, base+int64(time.Hour), trade)
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatalf("Failed to write recording: %v", err)
+	}
+
+	replayer := NewReplayer(path)
+	replayer.SetSpeed(4)
+	var received []time.Time
+	replayer.WebSocketAPI().SubscribeTrades("BTC", func(data interface{}) {
+		received = append(received, time.Now())
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := replayer.Replay(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the hour long gap to hit the deadline, got %v", err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 replayed messages, got %d", len(received))
+	}
+	if gap := received[1].Sub(received[0]); gap < 45*time.Millisecond || gap > 400*time.Millisecond {
+		t.Errorf("Expected a gap of about 50ms at 4x speed, got %v", gap)
+	}
+}
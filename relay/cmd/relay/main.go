@@ -17,6 +17,7 @@ import (
 	"go_hyperliquid/relay/internal/fanout"
 	"go_hyperliquid/relay/internal/logger"
 	"go_hyperliquid/relay/internal/ratelimit"
+	"go_hyperliquid/relay/internal/tracing"
 	"go_hyperliquid/relay/internal/upstream"
 	"go_hyperliquid/relay/pkg/types"
 
@@ -45,6 +46,16 @@ func main() {
 	defer logger.Sync()
 
 	log := logger.Log
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(&tracing.Config{
+		Enabled:     cfg.Tracing.Enabled,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
 	log.Info("Starting HL Relay Service",
 		zap.Int("http_port", cfg.Server.HTTPPort),
 		zap.Int("grpc_port", cfg.Server.GRPCPort))
@@ -152,6 +163,9 @@ func main() {
 	if err := server.Shutdown(); err != nil {
 		log.Error("Server shutdown error", zap.Error(err))
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("Tracing shutdown error", zap.Error(err))
+	}
 
 	select {
 	case <-ctx.Done():
@@ -13,6 +13,7 @@ import (
 	"go_hyperliquid/relay/internal/fanout"
 	"go_hyperliquid/relay/internal/models"
 	"go_hyperliquid/relay/internal/ratelimit"
+	"go_hyperliquid/relay/internal/tracing"
 	"go_hyperliquid/relay/internal/upstream"
 	"go_hyperliquid/relay/pkg/types"
 
@@ -75,6 +76,7 @@ func NewServer(
 // setupMiddleware sets up middleware.
 func (s *Server) setupMiddleware() {
 	s.app.Use(recover.New())
+	s.app.Use(tracing.Middleware())
 	s.app.Use(logger.New())
 	s.app.Use(cors.New())
 }
@@ -114,7 +116,7 @@ func (s *Server) authMiddleware(c *fiber.Ctx) error {
 	}
 
 	// Authenticate
-	authCtx, err := s.auth.Authenticate(c.Context(), apiKey)
+	authCtx, err := s.auth.Authenticate(c.UserContext(), apiKey)
 	if err != nil {
 		status := fiber.StatusUnauthorized
 		code := "AUTH_INVALID_KEY"
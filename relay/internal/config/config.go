@@ -17,6 +17,7 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Logger   LoggerConfig   `mapstructure:"logger"`
+	Tracing  TracingConfig  `mapstructure:"tracing"`
 }
 
 // ServerConfig holds server settings.
@@ -93,6 +94,13 @@ type LoggerConfig struct {
 	Encoding    string `mapstructure:"encoding"`
 }
 
+// TracingConfig holds OpenTelemetry tracing settings.
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
 // Load loads configuration from file and environment.
 func Load(configPath string) (*Config, error) {
 	v := viper.New()
@@ -176,4 +184,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("logger.level", "info")
 	v.SetDefault("logger.development", false)
 	v.SetDefault("logger.encoding", "json")
+
+	// Tracing defaults
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "hl-relay")
+	v.SetDefault("tracing.sample_ratio", 1.0)
 }
@@ -11,6 +11,7 @@ import (
 	"go_hyperliquid/relay/internal/fanout"
 	"go_hyperliquid/relay/internal/metrics"
 	"go_hyperliquid/relay/internal/ratelimit"
+	"go_hyperliquid/relay/internal/tracing"
 	"go_hyperliquid/relay/internal/upstream"
 	"go_hyperliquid/relay/pkg/types"
 
@@ -64,8 +65,8 @@ func NewServer(
 
 	// Create gRPC server with interceptors
 	s.server = grpc.NewServer(
-		grpc.UnaryInterceptor(s.unaryAuthInterceptor),
-		grpc.StreamInterceptor(s.streamAuthInterceptor),
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor(), s.unaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(tracing.StreamServerInterceptor(), s.streamAuthInterceptor),
 	)
 
 	return s
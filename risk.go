@@ -0,0 +1,475 @@
+package hyperliquid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// Default leverage the exchange applies to assets the user never configured
+const DEFAULT_LEVERAGE = 20
+
+var ErrNoUserState = errors.New("risk calculator has no user state")
+
+// PositionRisk describes the margin and liquidation risk of a single position
+type PositionRisk struct {
+	Coin              string  `json:"coin"`
+	Szi               float64 `json:"szi"`
+	EntryPx           float64 `json:"entryPx"`
+	MarkPx            float64 `json:"markPx"`
+	PositionValue     float64 `json:"positionValue"`
+	UnrealizedPnl     float64 `json:"unrealizedPnl"`
+	IsCross           bool    `json:"isCross"`
+	Leverage          int     `json:"leverage"`
+	MarginUsed        float64 `json:"marginUsed"`        // Initial margin, including unrealized pnl for isolated positions
+	MaintenanceMargin float64 `json:"maintenanceMargin"` // Margin below which the position is liquidated
+	LiquidationPx     float64 `json:"liquidationPx"`     // 0 if the position can not be liquidated
+	// Relative price move towards LiquidationPx that triggers liquidation, e.g. 0.1 for 10%
+	DistanceToLiquidation float64 `json:"distanceToLiquidation"`
+}
+
+// AccountRisk describes the margin usage and leverage of an account
+type AccountRisk struct {
+	AccountValue           float64        `json:"accountValue"`
+	CrossAccountValue      float64        `json:"crossAccountValue"`
+	CrossMarginUsed        float64        `json:"crossMarginUsed"`
+	CrossMaintenanceMargin float64        `json:"crossMaintenanceMargin"`
+	CrossMarginRatio       float64        `json:"crossMarginRatio"` // Maintenance margin / cross account value, liquidation at 1
+	IsolatedMarginUsed     float64        `json:"isolatedMarginUsed"`
+	AvailableMargin        float64        `json:"availableMargin"` // Cross account value not used as initial margin
+	TotalNotional          float64        `json:"totalNotional"`
+	Leverage               float64        `json:"leverage"` // Total notional / account value
+	Positions              []PositionRisk `json:"positions"`
+}
+
+// Position returns the risk of the position in coin, or nil if there is none
+func (r *AccountRisk) Position(coin string) *PositionRisk {
+	for i := range r.Positions {
+		if r.Positions[i].Coin == coin {
+			return &r.Positions[i]
+		}
+	}
+	return nil
+}
+
+// OrderImpact describes how an order would change the margin of an account if it was filled
+type OrderImpact struct {
+	Before         *AccountRisk  `json:"before"`
+	After          *AccountRisk  `json:"after"`
+	Position       *PositionRisk `json:"position"` // Position after the fill, nil if the order closes it
+	FillPx         float64       `json:"fillPx"`
+	RealizedPnl    float64       `json:"realizedPnl"`
+	MarginRequired float64       `json:"marginRequired"` // Additional initial margin, negative if margin is released
+	Sufficient     bool          `json:"sufficient"`     // Whether the available margin covers MarginRequired
+}
+
+// riskPosition is the internal representation of a position used for valuation
+type riskPosition struct {
+	coin           string
+	szi            float64
+	entryPx        float64
+	markPx         float64
+	isCross        bool
+	leverage       int
+	isolatedMargin float64 // Margin of an isolated position excluding unrealized pnl
+}
+
+func (p *riskPosition) unrealizedPnl() float64 {
+	return p.szi * (p.markPx - p.entryPx)
+}
+
+// RiskCalculator computes margin usage, liquidation prices and leverage of an account
+// from a UserState, mark prices and the perp meta. It mirrors the exchange margin rules:
+// initial margin is position value / leverage and maintenance margin is half of the
+// initial margin at the asset's max leverage.
+//
+// The calculator keeps the latest state, so it can be fed once with Update
+// or kept up to date with Follow:
+//
+//	meta, _ := client.GetMeta()
+//	calc := NewRiskCalculator(meta)
+//	state, _ := client.GetUserState(address)
+//	mids, _ := client.GetAllMids()
+//	calc.Update(state, *mids)
+//	report, _ := calc.Report()
+type RiskCalculator struct {
+	mu       sync.RWMutex
+	assets   map[string]Asset
+	leverage map[string]Leverage // Leverage settings for coins without a position
+	state    *UserState
+	marks    map[string]float64
+}
+
+// NewRiskCalculator returns a risk calculator for the assets in meta
+func NewRiskCalculator(meta *Meta) *RiskCalculator {
+	calc := &RiskCalculator{
+		assets:   make(map[string]Asset),
+		leverage: make(map[string]Leverage),
+		marks:    make(map[string]float64),
+	}
+	if meta != nil {
+		calc.SetMeta(meta)
+	}
+	return calc
+}
+
+// SetMeta replaces the asset meta
+func (c *RiskCalculator) SetMeta(meta *Meta) {
+	assets := make(map[string]Asset, len(meta.Universe))
+	for _, asset := range meta.Universe {
+		assets[asset.Name] = asset
+	}
+	c.mu.Lock()
+	c.assets = assets
+	c.mu.Unlock()
+}
+
+// SetLeverage sets the leverage used to simulate orders on a coin without an open position.
+// Coins with a position always use the position's leverage.
+func (c *RiskCalculator) SetLeverage(coin string, leverage Leverage) {
+	c.mu.Lock()
+	c.leverage[coin] = leverage
+	c.mu.Unlock()
+}
+
+// Update replaces the user state and mark prices. mids has the format returned by GetAllMids,
+// entries that can not be parsed are ignored.
+func (c *RiskCalculator) Update(state *UserState, mids map[string]string) {
+	marks := make(map[string]float64, len(mids))
+	for coin, mid := range mids {
+		if px, err := strconv.ParseFloat(mid, 64); err == nil {
+			marks[coin] = px
+		}
+	}
+	c.mu.Lock()
+	c.state = state
+	for coin, px := range marks {
+		c.marks[coin] = px
+	}
+	c.mu.Unlock()
+}
+
+// UpdateMarks updates the mark prices of the given coins
+func (c *RiskCalculator) UpdateMarks(marks map[string]float64) {
+	c.mu.Lock()
+	for coin, px := range marks {
+		c.marks[coin] = px
+	}
+	c.mu.Unlock()
+}
+
+// UpdateWebData2 updates meta, user state and mark prices from a webData2 update
+func (c *RiskCalculator) UpdateWebData2(data *WebData2) {
+	if len(data.Meta.Universe) > 0 {
+		c.SetMeta(&data.Meta)
+	}
+	marks := make(map[string]float64, len(data.AssetCtxs))
+	for i, ctx := range data.AssetCtxs {
+		if i >= len(data.Meta.Universe) {
+			break
+		}
+		if ctx.MarkPx > 0 {
+			marks[data.Meta.Universe[i].Name] = ctx.MarkPx
+		}
+	}
+	state := data.ClearinghouseState
+	c.mu.Lock()
+	c.state = &state
+	for coin, px := range marks {
+		c.marks[coin] = px
+	}
+	c.mu.Unlock()
+}
+
+// Follow subscribes to the webData2 stream of user and recomputes the account risk on every update.
+// Updates that can not be decoded or evaluated are skipped.
+func (c *RiskCalculator) Follow(ws *WebSocketAPI, user string, handler func(report *AccountRisk)) (*SubscriptionHandle, error) {
+	return ws.SubscribeWebData2(user, func(data interface{}) {
+		update, err := convertWSData[WebData2](data)
+		if err != nil {
+			return
+		}
+		c.UpdateWebData2(update)
+		report, err := c.Report()
+		if err != nil || handler == nil {
+			return
+		}
+		handler(report)
+	})
+}
+
+// Report computes the current account risk
+func (c *RiskCalculator) Report() (*AccountRisk, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.state == nil {
+		return nil, ErrNoUserState
+	}
+	crossBase, positions := c.positions()
+	return c.evaluate(crossBase, positions), nil
+}
+
+// SimulateOrder computes the margin impact of order assuming it is completely filled at its
+// limit price (or the mark price if no limit price is set). Fees are not included.
+func (c *RiskCalculator) SimulateOrder(order OrderRequest) (*OrderImpact, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.state == nil {
+		return nil, ErrNoUserState
+	}
+	if order.Sz <= 0 {
+		return nil, fmt.Errorf("invalid order size: %v", order.Sz)
+	}
+	crossBase, positions := c.positions()
+	before := c.evaluate(crossBase, positions)
+
+	var current *riskPosition
+	index := -1
+	for i := range positions {
+		if positions[i].coin == order.Coin {
+			current = &positions[i]
+			index = i
+			break
+		}
+	}
+	markPx, hasMark := c.marks[order.Coin]
+	if current == nil {
+		if !hasMark && order.LimitPx <= 0 {
+			return nil, fmt.Errorf("no mark price for %s", order.Coin)
+		}
+		leverage, exists := c.leverage[order.Coin]
+		if !exists {
+			leverage = c.defaultLeverage(order.Coin)
+		}
+		positions = append(positions, riskPosition{
+			coin:     order.Coin,
+			markPx:   markPx,
+			isCross:  leverage.Type != "isolated",
+			leverage: leverage.Value,
+		})
+		index = len(positions) - 1
+		current = &positions[index]
+	}
+	if current.markPx == 0 {
+		current.markPx = order.LimitPx
+	}
+	fillPx := order.LimitPx
+	if fillPx <= 0 {
+		fillPx = current.markPx
+	}
+
+	delta := order.Sz
+	if !order.IsBuy {
+		delta = -delta
+	}
+	if order.ReduceOnly {
+		if current.szi == 0 || (current.szi > 0) == (delta > 0) {
+			delta = 0
+		} else if math.Abs(delta) > math.Abs(current.szi) {
+			delta = -current.szi
+		}
+	}
+
+	oldMargin := current.initialMargin()
+	realizedPnl := current.fill(delta, fillPx)
+	newMargin := current.initialMargin()
+	marginRequired := newMargin - oldMargin
+	if current.isCross {
+		crossBase += realizedPnl
+	} else {
+		// Margin moves between the cross account and the isolated position,
+		// realized pnl is settled to the cross account
+		current.isolatedMargin += marginRequired
+		crossBase += realizedPnl - marginRequired
+	}
+
+	if current.szi == 0 {
+		if !current.isCross {
+			crossBase += current.isolatedMargin
+		}
+		positions = append(positions[:index], positions[index+1:]...)
+	}
+	after := c.evaluate(crossBase, positions)
+
+	return &OrderImpact{
+		Before:         before,
+		After:          after,
+		Position:       after.Position(order.Coin),
+		FillPx:         fillPx,
+		RealizedPnl:    realizedPnl,
+		MarginRequired: marginRequired,
+		Sufficient:     marginRequired <= 0 || marginRequired <= before.AvailableMargin,
+	}, nil
+}
+
+// positions converts the user state into positions valued at the latest mark prices.
+// It returns the cross account value excluding unrealized pnl of cross positions.
+func (c *RiskCalculator) positions() (float64, []riskPosition) {
+	crossBase := c.state.CrossMarginSummary.AccountValue
+	positions := make([]riskPosition, 0, len(c.state.AssetPositions))
+	for _, assetPosition := range c.state.AssetPositions {
+		p := assetPosition.Position
+		if p.Szi == 0 {
+			continue
+		}
+		position := riskPosition{
+			coin:     p.Coin,
+			szi:      p.Szi,
+			entryPx:  p.EntryPx,
+			markPx:   p.PositionValue / math.Abs(p.Szi),
+			isCross:  p.Leverage.Type != "isolated",
+			leverage: p.Leverage.Value,
+		}
+		// The state is valued at the mark price the exchange used when it was produced
+		if position.isCross {
+			crossBase -= position.unrealizedPnl()
+		} else {
+			position.isolatedMargin = p.MarginUsed - position.unrealizedPnl()
+		}
+		if px, exists := c.marks[p.Coin]; exists {
+			position.markPx = px
+		}
+		positions = append(positions, position)
+	}
+	return crossBase, positions
+}
+
+// evaluate computes the account risk of positions valued at their mark prices
+func (c *RiskCalculator) evaluate(crossBase float64, positions []riskPosition) *AccountRisk {
+	report := &AccountRisk{
+		CrossAccountValue: crossBase,
+		Positions:         make([]PositionRisk, 0, len(positions)),
+	}
+	for _, position := range positions {
+		if position.isCross {
+			report.CrossAccountValue += position.unrealizedPnl()
+		}
+	}
+
+	var isolatedValue float64
+	for _, position := range positions {
+		value := math.Abs(position.szi) * position.markPx
+		risk := PositionRisk{
+			Coin:              position.coin,
+			Szi:               position.szi,
+			EntryPx:           position.entryPx,
+			MarkPx:            position.markPx,
+			PositionValue:     value,
+			UnrealizedPnl:     position.unrealizedPnl(),
+			IsCross:           position.isCross,
+			Leverage:          position.leverage,
+			MaintenanceMargin: value * c.maintenanceMarginRate(position.coin),
+		}
+		report.TotalNotional += value
+		if position.isCross {
+			risk.MarginUsed = position.initialMargin()
+			report.CrossMarginUsed += risk.MarginUsed
+			report.CrossMaintenanceMargin += risk.MaintenanceMargin
+		} else {
+			risk.MarginUsed = position.isolatedMargin + risk.UnrealizedPnl
+			report.IsolatedMarginUsed += risk.MarginUsed
+			isolatedValue += risk.MarginUsed
+		}
+		report.Positions = append(report.Positions, risk)
+	}
+
+	for i := range report.Positions {
+		risk := &report.Positions[i]
+		// Cross positions share the cross account, the other positions are assumed to keep their price
+		marginAvailable := report.CrossAccountValue - report.CrossMaintenanceMargin
+		if !risk.IsCross {
+			marginAvailable = risk.MarginUsed - risk.MaintenanceMargin
+		}
+		risk.LiquidationPx, risk.DistanceToLiquidation = liquidationPx(risk, marginAvailable, c.maintenanceMarginRate(risk.Coin))
+	}
+
+	report.AccountValue = report.CrossAccountValue + isolatedValue
+	report.AvailableMargin = report.CrossAccountValue - report.CrossMarginUsed
+	if report.CrossAccountValue > 0 {
+		report.CrossMarginRatio = report.CrossMaintenanceMargin / report.CrossAccountValue
+	}
+	if report.AccountValue > 0 {
+		report.Leverage = report.TotalNotional / report.AccountValue
+	}
+	return report
+}
+
+// defaultLeverage returns the leverage the exchange applies to coin if the user never configured it
+func (c *RiskCalculator) defaultLeverage(coin string) Leverage {
+	leverage := Leverage{Type: "cross", Value: DEFAULT_LEVERAGE}
+	if asset, exists := c.assets[coin]; exists && asset.MaxLeverage < DEFAULT_LEVERAGE {
+		leverage.Value = asset.MaxLeverage
+	}
+	if asset, exists := c.assets[coin]; exists && asset.OnlyIsolated {
+		leverage.Type = "isolated"
+	}
+	return leverage
+}
+
+// maintenanceMarginRate returns the maintenance margin per unit of position value
+func (c *RiskCalculator) maintenanceMarginRate(coin string) float64 {
+	maxLeverage := DEFAULT_LEVERAGE
+	if asset, exists := c.assets[coin]; exists && asset.MaxLeverage > 0 {
+		maxLeverage = asset.MaxLeverage
+	}
+	return 1 / float64(2*maxLeverage)
+}
+
+// liquidationPx returns the price at which the position is liquidated and the relative distance to it.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/trading/liquidations
+func liquidationPx(risk *PositionRisk, marginAvailable float64, maintenanceRate float64) (float64, float64) {
+	if risk.Szi == 0 || risk.MarkPx <= 0 {
+		return 0, 0
+	}
+	side := 1.0
+	if risk.Szi < 0 {
+		side = -1
+	}
+	px := risk.MarkPx - side*marginAvailable/math.Abs(risk.Szi)/(1-maintenanceRate*side)
+	if px <= 0 {
+		// A long position that survives a price of zero
+		return 0, 1
+	}
+	distance := (risk.MarkPx - px) / risk.MarkPx * side
+	if distance < 0 {
+		distance = 0
+	}
+	return px, distance
+}
+
+// initialMargin returns the margin required to open the position at its leverage
+func (p *riskPosition) initialMargin() float64 {
+	if p.leverage <= 0 {
+		return 0
+	}
+	return math.Abs(p.szi) * p.markPx / float64(p.leverage)
+}
+
+// fill changes the position by delta at px and returns the realized pnl
+func (p *riskPosition) fill(delta float64, px float64) float64 {
+	if delta == 0 {
+		return 0
+	}
+	if p.szi == 0 || (p.szi > 0) == (delta > 0) {
+		// Opening or increasing
+		p.entryPx = (p.entryPx*math.Abs(p.szi) + px*math.Abs(delta)) / (math.Abs(p.szi) + math.Abs(delta))
+		p.szi += delta
+		return 0
+	}
+	closed := math.Min(math.Abs(delta), math.Abs(p.szi))
+	side := 1.0
+	if p.szi < 0 {
+		side = -1
+	}
+	realizedPnl := closed * (px - p.entryPx) * side
+	p.szi += delta
+	if math.Abs(p.szi) < 1e-12 {
+		p.szi = 0
+	} else if (p.szi > 0) != (side > 0) {
+		// Flipped, the remainder is opened at the fill price
+		p.entryPx = px
+	}
+	return realizedPnl
+}
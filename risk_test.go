@@ -0,0 +1,102 @@
+package hyperliquid
+
+import (
+	"math"
+	"testing"
+)
+
+func testRiskCalculator() *RiskCalculator {
+	calc := NewRiskCalculator(&Meta{Universe: []Asset{
+		{Name: "BTC", SzDecimals: 5, MaxLeverage: 50},
+		{Name: "ETH", SzDecimals: 4, MaxLeverage: 25},
+	}})
+	state := &UserState{
+		AssetPositions: []AssetPosition{
+			{Type: "oneWay", Position: Position{
+				Coin: "BTC", Szi: 1, EntryPx: 100, PositionValue: 110, UnrealizedPnl: 10,
+				Leverage: Leverage{Type: "cross", Value: 10}, MarginUsed: 11,
+			}},
+			{Type: "oneWay", Position: Position{
+				Coin: "ETH", Szi: -2, EntryPx: 50, PositionValue: 100,
+				Leverage: Leverage{Type: "isolated", Value: 5}, MarginUsed: 20,
+			}},
+		},
+		CrossMarginSummary: MarginSummary{AccountValue: 1000},
+		MarginSummary:      MarginSummary{AccountValue: 1020},
+	}
+	calc.Update(state, map[string]string{"BTC": "120", "ETH": "55"})
+	return calc
+}
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s: expected %v, got %v", name, want, got)
+	}
+}
+
+// TestRiskCalculatorReport tests margin usage and liquidation prices of cross and isolated positions
+func TestRiskCalculatorReport(t *testing.T) {
+	report, err := testRiskCalculator().Report()
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	assertClose(t, "cross account value", report.CrossAccountValue, 1010)
+	assertClose(t, "account value", report.AccountValue, 1020)
+	assertClose(t, "cross margin used", report.CrossMarginUsed, 12)
+	assertClose(t, "cross maintenance margin", report.CrossMaintenanceMargin, 1.2)
+	assertClose(t, "isolated margin used", report.IsolatedMarginUsed, 10)
+	assertClose(t, "leverage", report.Leverage, 230.0/1020)
+
+	btc := report.Position("BTC")
+	if btc == nil || btc.LiquidationPx != 0 || btc.DistanceToLiquidation != 1 {
+		t.Errorf("Expected BTC long without liquidation price, got %+v", btc)
+	}
+	eth := report.Position("ETH")
+	if eth == nil {
+		t.Fatal("Expected ETH position")
+	}
+	assertClose(t, "ETH liquidation price", eth.LiquidationPx, 55+7.8/2/1.02)
+	assertClose(t, "ETH distance to liquidation", eth.DistanceToLiquidation, (eth.LiquidationPx-55)/55)
+}
+
+// TestRiskCalculatorSimulateOrder tests the margin impact of hypothetical orders
+func TestRiskCalculatorSimulateOrder(t *testing.T) {
+	calc := testRiskCalculator()
+
+	impact, err := calc.SimulateOrder(OrderRequest{Coin: "BTC", IsBuy: true, Sz: 1, LimitPx: 120})
+	if err != nil {
+		t.Fatalf("SimulateOrder failed: %v", err)
+	}
+	assertClose(t, "margin required", impact.MarginRequired, 12)
+	if !impact.Sufficient || impact.Position == nil || impact.Position.Szi != 2 || impact.Position.EntryPx != 110 {
+		t.Errorf("Unexpected impact: %+v", impact.Position)
+	}
+
+	// Reduce only orders are capped at the position size and release the isolated margin
+	impact, err = calc.SimulateOrder(OrderRequest{Coin: "ETH", IsBuy: true, Sz: 5, LimitPx: 55, ReduceOnly: true})
+	if err != nil {
+		t.Fatalf("SimulateOrder failed: %v", err)
+	}
+	if impact.Position != nil {
+		t.Errorf("Expected ETH position to be closed, got %+v", impact.Position)
+	}
+	assertClose(t, "realized pnl", impact.RealizedPnl, -10)
+	assertClose(t, "account value after close", impact.After.AccountValue, 1020)
+	assertClose(t, "isolated margin after close", impact.After.IsolatedMarginUsed, 0)
+
+	// A new position uses the default leverage
+	impact, err = calc.SimulateOrder(OrderRequest{Coin: "SOL", IsBuy: false, Sz: 10, LimitPx: 100})
+	if err != nil {
+		t.Fatalf("SimulateOrder failed: %v", err)
+	}
+	assertClose(t, "margin required for new position", impact.MarginRequired, 1000.0/DEFAULT_LEVERAGE)
+
+	impact, err = calc.SimulateOrder(OrderRequest{Coin: "BTC", IsBuy: true, Sz: 100, LimitPx: 120})
+	if err != nil {
+		t.Fatalf("SimulateOrder failed: %v", err)
+	}
+	if impact.Sufficient {
+		t.Errorf("Expected insufficient margin for %v", impact.MarginRequired)
+	}
+}
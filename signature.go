@@ -3,7 +3,6 @@ package hyperliquid
 import (
 	"encoding/binary"
 	"fmt"
-	"log"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -67,17 +66,20 @@ func (signer *Signer) Sign(request *SignRequest) (byte, [32]byte, [32]byte, erro
 	return signer.signInternal(SignRequestToEIP712TypedData(request))
 }
 
+// SignTypedData signs EIP-712 typed data and returns the signature in VRS format
+func (signer *Signer) SignTypedData(typedData apitypes.TypedData) (byte, [32]byte, [32]byte, error) {
+	return signer.signInternal(typedData)
+}
+
 // signInternal signs the typed data and returns the signature in VRS format
 func (signer *Signer) signInternal(message apitypes.TypedData) (byte, [32]byte, [32]byte, error) {
 	pkey := signer.manager.PrivateECDSA()
 	bytes, _, err := apitypes.TypedDataAndHash(message)
 	if err != nil {
-		log.Printf("Error hashing typed data: %s", err)
 		return 0, [32]byte{}, [32]byte{}, err
 	}
 	signature, err := crypto.Sign(bytes, pkey)
 	if err != nil {
-		log.Printf("Error signing typed data: %s", err)
 		return 0, [32]byte{}, [32]byte{}, err
 	}
 	return SignatureToVRS(signature)
@@ -102,12 +104,18 @@ func SignatureToVRS(sig []byte) (byte, [32]byte, [32]byte, error) {
 	return v, r, s, nil
 }
 
-// Create a hash of an action (json object)
-func buildActionHash(action any, vaultAd string, nonce uint64) (common.Hash, error) {
+// Create a hash of an action (json object) with its nonce, vault address and expiry.
+// An expiresAfter of zero is left out of the hash.
+func buildActionHash(action any, vaultAd string, nonce uint64, expiresAfter uint64) (common.Hash, error) {
 	data, err := msgpack.Marshal(action)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("error while marshaling action: %s", err)
 	}
+	return hashEncodedAction(data, vaultAd, nonce, expiresAfter), nil
+}
+
+// hashEncodedAction hashes a msgpack encoded action
+func hashEncodedAction(data []byte, vaultAd string, nonce uint64, expiresAfter uint64) common.Hash {
 	nonceBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(nonceBytes, uint64(nonce))
 	data = ArrayAppend(data, nonceBytes)
@@ -118,8 +126,12 @@ func buildActionHash(action any, vaultAd string, nonce uint64) (common.Hash, err
 		data = ArrayAppend(data, []byte("\x01"))
 		data = ArrayAppend(data, HexToBytes(vaultAd))
 	}
-	result := crypto.Keccak256Hash(data)
-	return result, nil
+	if expiresAfter > 0 {
+		expiresBytes := make([]byte, 9)
+		binary.BigEndian.PutUint64(expiresBytes[1:], expiresAfter)
+		data = ArrayAppend(data, expiresBytes)
+	}
+	return crypto.Keccak256Hash(data)
 }
 
 func getNetSource(isMainnet bool) string {
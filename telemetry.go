@@ -0,0 +1,306 @@
+package hyperliquid
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Endpoints of the round trip times recorded by Telemetry
+const (
+	EndpointHTTPInfo     = "http:info"     // HTTP requests to /info
+	EndpointHTTPExchange = "http:exchange" // HTTP requests to /exchange
+	EndpointWSInfo       = "ws:info"       // WebSocket info post requests
+	EndpointWSAction     = "ws:action"     // WebSocket action post requests
+	EndpointWSPing       = "ws:ping"       // WebSocket ping/pong
+)
+
+// Latency histogram buckets grow exponentially from 100µs to about 100s
+const (
+	latencyBucketBase  = 100 * time.Microsecond
+	latencyBucketCount = 21
+)
+
+// CLOCK_OFFSET_WINDOW is the window of stream messages the clock offset is estimated from
+const CLOCK_OFFSET_WINDOW = time.Minute
+
+// DefaultTelemetry collects the measurements of clients without their own Telemetry
+var DefaultTelemetry = NewTelemetry()
+
+// nonceClockOffset is added to the local time by GetNonce, in milliseconds
+var nonceClockOffset atomic.Int64
+
+// SetNonceClockOffset shifts the nonces of GetNonce by offset, the difference between
+// the exchange clock and the local clock. Like GetNonce it is process-wide. See Telemetry.SyncNonceClock.
+func SetNonceClockOffset(offset time.Duration) {
+	nonceClockOffset.Store(offset.Milliseconds())
+}
+
+// LatencyHistogram is a lock-free histogram of durations with exponential buckets
+type LatencyHistogram struct {
+	buckets [latencyBucketCount + 1]atomic.Uint64 // The last bucket counts everything above the largest bound
+	count   atomic.Uint64
+	sum     atomic.Int64
+	min     atomic.Int64
+	max     atomic.Int64
+}
+
+// LatencyBucket is a histogram bucket counting durations up to UpperBound.
+// The UpperBound of the last bucket is math.MaxInt64.
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upperBound"`
+	Count      uint64        `json:"count"`
+}
+
+// LatencyStats is a snapshot of a LatencyHistogram. Percentiles are the upper bounds of their
+// buckets, capped at Max.
+type LatencyStats struct {
+	Count   uint64          `json:"count"`
+	Mean    time.Duration   `json:"mean"`
+	Min     time.Duration   `json:"min"`
+	Max     time.Duration   `json:"max"`
+	P50     time.Duration   `json:"p50"`
+	P90     time.Duration   `json:"p90"`
+	P99     time.Duration   `json:"p99"`
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// NewLatencyHistogram creates an empty histogram
+func NewLatencyHistogram() *LatencyHistogram {
+	h := &LatencyHistogram{}
+	h.min.Store(math.MaxInt64)
+	return h
+}
+
+// latencyBucketBound returns the upper bound of bucket i
+func latencyBucketBound(i int) time.Duration {
+	if i >= latencyBucketCount {
+		return math.MaxInt64
+	}
+	return latencyBucketBase << i
+}
+
+// latencyBucketIndex returns the bucket of d
+func latencyBucketIndex(d time.Duration) int {
+	if d <= latencyBucketBase {
+		return 0
+	}
+	i := bits.Len64(uint64((d - 1) / latencyBucketBase))
+	if i > latencyBucketCount {
+		return latencyBucketCount
+	}
+	return i
+}
+
+// Observe records a duration, negative durations are recorded as zero
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.buckets[latencyBucketIndex(d)].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+	for current := h.min.Load(); int64(d) < current && !h.min.CompareAndSwap(current, int64(d)); current = h.min.Load() {
+	}
+	for current := h.max.Load(); int64(d) > current && !h.max.CompareAndSwap(current, int64(d)); current = h.max.Load() {
+	}
+}
+
+// Stats returns a snapshot of the histogram
+func (h *LatencyHistogram) Stats() LatencyStats {
+	stats := LatencyStats{Count: h.count.Load()}
+	if stats.Count == 0 {
+		return stats
+	}
+	stats.Mean = time.Duration(h.sum.Load() / int64(stats.Count))
+	stats.Min = time.Duration(h.min.Load())
+	stats.Max = time.Duration(h.max.Load())
+	stats.Buckets = make([]LatencyBucket, len(h.buckets))
+	var total uint64
+	for i := range h.buckets {
+		stats.Buckets[i] = LatencyBucket{UpperBound: latencyBucketBound(i), Count: h.buckets[i].Load()}
+		total += stats.Buckets[i].Count
+	}
+	percentile := func(q float64) time.Duration {
+		rank := uint64(math.Ceil(q * float64(total)))
+		var cumulative uint64
+		for _, bucket := range stats.Buckets {
+			cumulative += bucket.Count
+			if cumulative >= rank {
+				return min(bucket.UpperBound, stats.Max)
+			}
+		}
+		return stats.Max
+	}
+	stats.P50 = percentile(0.5)
+	stats.P90 = percentile(0.9)
+	stats.P99 = percentile(0.99)
+	return stats
+}
+
+// TelemetryStats is a snapshot of the measurements of a Telemetry
+type TelemetryStats struct {
+	Endpoints   map[string]LatencyStats `json:"endpoints"`   // Round trip times by endpoint, e.g. EndpointHTTPInfo
+	StreamLag   map[string]LatencyStats `json:"streamLag"`   // Exchange to local lag of stream messages by channel
+	ClockOffset time.Duration           `json:"clockOffset"` // Estimated exchange clock minus local clock
+	ClockSynced bool                    `json:"clockSynced"` // Whether ClockOffset has been estimated yet
+}
+
+// Telemetry measures request round trip times, the lag of stream messages and the offset between
+// the local and the exchange clock. It is safe for concurrent use.
+//
+// Stream lag is the local receive time minus the exchange time of a message, so it includes the
+// clock offset. The clock offset is estimated from the smallest lag seen in the last
+// CLOCK_OFFSET_WINDOW and half the latest WebSocket ping round trip.
+type Telemetry struct {
+	mu        sync.RWMutex
+	endpoints map[string]*LatencyHistogram
+	streams   map[string]*LatencyHistogram
+
+	clockMu     sync.Mutex
+	windowStart time.Time
+	windowMin   time.Duration
+	previousMin time.Duration
+	windows     int // Number of windows with samples, 0 until the first sample
+	pingRTT     time.Duration
+	clockOffset atomic.Int64
+	clockSynced atomic.Bool
+	syncNonce   atomic.Bool
+}
+
+// NewTelemetry creates an empty Telemetry
+func NewTelemetry() *Telemetry {
+	return &Telemetry{
+		endpoints: make(map[string]*LatencyHistogram),
+		streams:   make(map[string]*LatencyHistogram),
+	}
+}
+
+// histogram returns the histogram of key, creating it on first use
+func (t *Telemetry) histogram(histograms map[string]*LatencyHistogram, key string) *LatencyHistogram {
+	t.mu.RLock()
+	h, exists := histograms[key]
+	t.mu.RUnlock()
+	if exists {
+		return h
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if h, exists = histograms[key]; !exists {
+		h = NewLatencyHistogram()
+		histograms[key] = h
+	}
+	return h
+}
+
+// ObserveRTT records the round trip time of a request to endpoint.
+// WebSocket ping round trips are also used for the clock offset estimation.
+func (t *Telemetry) ObserveRTT(endpoint string, rtt time.Duration) {
+	t.histogram(t.endpoints, endpoint).Observe(rtt)
+	if endpoint == EndpointWSPing {
+		t.clockMu.Lock()
+		t.pingRTT = rtt
+		t.clockMu.Unlock()
+	}
+}
+
+// ObserveStreamTime records the lag of a message of channel with the exchange time
+// exchangeTime (in milliseconds) that was received at received
+func (t *Telemetry) ObserveStreamTime(channel string, exchangeTime int64, received time.Time) {
+	lag := received.Sub(time.UnixMilli(exchangeTime))
+	t.histogram(t.streams, channel).Observe(lag)
+
+	t.clockMu.Lock()
+	if t.windows == 0 || received.Sub(t.windowStart) >= CLOCK_OFFSET_WINDOW {
+		t.previousMin = t.windowMin
+		t.windowStart = received
+		t.windowMin = lag
+		t.windows++
+	} else if lag < t.windowMin {
+		t.windowMin = lag
+	}
+	minLag := t.windowMin
+	if t.windows > 1 && t.previousMin < minLag {
+		minLag = t.previousMin
+	}
+	offset := t.pingRTT/2 - minLag
+	t.clockMu.Unlock()
+
+	t.clockOffset.Store(int64(offset))
+	t.clockSynced.Store(true)
+	if t.syncNonce.Load() {
+		SetNonceClockOffset(offset)
+	}
+}
+
+// ClockOffset returns the estimated exchange clock minus the local clock.
+// It returns false until a stream message with an exchange time was received.
+func (t *Telemetry) ClockOffset() (time.Duration, bool) {
+	return time.Duration(t.clockOffset.Load()), t.clockSynced.Load()
+}
+
+// SyncNonceClock makes GetNonce follow the estimated exchange clock, see SetNonceClockOffset.
+// Disabling it resets the nonce clock to the local time.
+//
+// The nonce clock is process-wide like GetNonce: it applies to all clients, whatever Telemetry
+// they use. Enable it on a single Telemetry, otherwise the last estimate of any of them wins.
+func (t *Telemetry) SyncNonceClock(enabled bool) {
+	t.syncNonce.Store(enabled)
+	if !enabled {
+		SetNonceClockOffset(0)
+		return
+	}
+	if offset, synced := t.ClockOffset(); synced {
+		SetNonceClockOffset(offset)
+	}
+}
+
+// Stats returns a snapshot of all measurements
+func (t *Telemetry) Stats() TelemetryStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	stats := TelemetryStats{
+		Endpoints: make(map[string]LatencyStats, len(t.endpoints)),
+		StreamLag: make(map[string]LatencyStats, len(t.streams)),
+	}
+	for endpoint, h := range t.endpoints {
+		stats.Endpoints[endpoint] = h.Stats()
+	}
+	for channel, h := range t.streams {
+		stats.StreamLag[channel] = h.Stats()
+	}
+	stats.ClockOffset, stats.ClockSynced = t.ClockOffset()
+	return stats
+}
+
+// streamMessageTime returns the exchange time (in milliseconds) of a decoded stream message,
+// false for messages without one. Snapshots of past fills are skipped.
+func streamMessageTime(data interface{}) (int64, bool) {
+	var latest int64
+	switch update := data.(type) {
+	case L2BookSnapshot:
+		latest = update.Time
+	case Bbo:
+		latest = update.Time
+	case WebData2:
+		latest = update.ServerTime
+	case []Trade:
+		for i := range update {
+			latest = max(latest, update[i].Time)
+		}
+	case UserFills:
+		if update.IsSnapshot {
+			return 0, false
+		}
+		for i := range update.Fills {
+			latest = max(latest, update.Fills[i].Time)
+		}
+	case []OrderUpdate:
+		for i := range update {
+			latest = max(latest, update[i].StatusTimestamp)
+		}
+	}
+	return latest, latest > 0
+}
@@ -0,0 +1,121 @@
+package hyperliquid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestLatencyHistogram tests bucketing and percentiles of latency histograms
+func TestLatencyHistogram(t *testing.T) {
+	h := NewLatencyHistogram()
+	if stats := h.Stats(); stats.Count != 0 || stats.Buckets != nil {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+	h.Observe(-time.Second)
+	h.Observe(time.Hour)
+
+	stats := h.Stats()
+	if stats.Count != 102 || stats.Min != 0 || stats.Max != time.Hour {
+		t.Errorf("Unexpected count or range: %+v", stats)
+	}
+	// 50ms is in the (25.6ms, 51.2ms] bucket, 90ms and 99ms in (51.2ms, 102.4ms]
+	if stats.P50 != 51200*time.Microsecond || stats.P90 != 102400*time.Microsecond || stats.P99 != 102400*time.Microsecond {
+		t.Errorf("Unexpected percentiles: p50 %s, p90 %s, p99 %s", stats.P50, stats.P90, stats.P99)
+	}
+	if last := stats.Buckets[len(stats.Buckets)-1]; last.Count != 1 {
+		t.Errorf("Expected the hour in the overflow bucket, got %+v", last)
+	}
+	for _, d := range []time.Duration{0, latencyBucketBase, latencyBucketBase + 1, 3 * latencyBucketBase, 4 * latencyBucketBase} {
+		i := latencyBucketIndex(d)
+		if d > latencyBucketBound(i) || (i > 0 && d <= latencyBucketBound(i-1)) {
+			t.Errorf("Duration %s in wrong bucket %d", d, i)
+		}
+	}
+}
+
+// TestClockOffset tests the clock offset estimation and its use by GetNonce
+func TestClockOffset(t *testing.T) {
+	telemetry := NewTelemetry()
+	if _, synced := telemetry.ClockOffset(); synced {
+		t.Error("Expected no clock offset before the first message")
+	}
+
+	// The exchange clock is 200ms ahead, messages take 10ms to 30ms
+	const skew = 200 * time.Millisecond
+	telemetry.ObserveRTT(EndpointWSPing, 20*time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		received := start.Add(time.Duration(i) * time.Second)
+		transit := time.Duration(10+i%20) * time.Millisecond
+		telemetry.ObserveStreamTime("trades", received.Add(skew-transit).UnixMilli(), received)
+	}
+	offset, synced := telemetry.ClockOffset()
+	if !synced || offset < skew-2*time.Millisecond || offset > skew+2*time.Millisecond {
+		t.Errorf("Expected clock offset of about %s, got %s", skew, offset)
+	}
+	if lag := telemetry.Stats().StreamLag["trades"]; lag.Count != 50 {
+		t.Errorf("Expected 50 lag samples, got %+v", lag)
+	}
+
+	t.Cleanup(func() { SetNonceClockOffset(0) })
+	telemetry.SyncNonceClock(true)
+	if ahead := int64(GetNonce()) - time.Now().UnixMilli(); ahead < 150 {
+		t.Errorf("Expected nonce to follow the exchange clock, it is %dms ahead", ahead)
+	}
+	telemetry.SyncNonceClock(false)
+	if nonceClockOffset.Load() != 0 {
+		t.Error("Expected nonce clock offset to be reset")
+	}
+}
+
+// TestTelemetryRecording tests that HTTP requests, WebSocket posts and stream messages are recorded
+func TestTelemetryRecording(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	telemetry := NewTelemetry()
+	client := NewClient(true)
+	client.baseUrl = server.URL
+	client.SetTelemetry(telemetry)
+	if _, err := client.Request("/info", map[string]string{"type": "meta"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		if FastUnmarshal(message, &request); request.Method == "post" {
+			replyToPost(t, conn, message, "info", `{"type":"meta","data":{}}`)
+		}
+	})
+	ws.SetTelemetry(telemetry)
+	if _, err := ws.PostInfoRequest(map[string]string{"type": "meta"}); err != nil {
+		t.Fatalf("PostInfoRequest failed: %v", err)
+	}
+	if _, err := ws.SubscribeOrderbook("BTC", func(data interface{}) {}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	exchangeTime := strconv.FormatInt(time.Now().Add(-5*time.Millisecond).UnixMilli(), 10)
+	ws.processJSONMessage([]byte(`{"channel":"l2Book","data":{"coin":"BTC","time":` + exchangeTime + `,"levels":[[],[]]}}`))
+
+	stats := telemetry.Stats()
+	for _, endpoint := range []string{EndpointHTTPInfo, EndpointWSInfo} {
+		if stats.Endpoints[endpoint].Count != 1 {
+			t.Errorf("Expected one %s round trip, got %+v", endpoint, stats.Endpoints[endpoint])
+		}
+	}
+	if lag := stats.StreamLag["l2Book"]; lag.Count != 1 || lag.Max < 5*time.Millisecond {
+		t.Errorf("Expected l2Book lag of at least 5ms, got %+v", lag)
+	}
+	if !stats.ClockSynced {
+		t.Error("Expected clock offset estimate after a stream message")
+	}
+}
@@ -0,0 +1,261 @@
+package hyperliquid
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TRACER_NAME is the instrumentation name of the spans of the SDK
+const TRACER_NAME = "go_hyperliquid"
+
+// ORDER_TRACE_TTL is how long WebSocket events are matched to the span of an order
+// that did not reach a final status
+const ORDER_TRACE_TTL = time.Hour
+
+// ORDER_TRACE_GRACE is how long fills are still matched to the span of an order after its
+// final status, userFills events can arrive after the orderUpdates event of a filled order
+const ORDER_TRACE_GRACE = 30 * time.Second
+
+// Span attributes of the SDK
+const (
+	AttrEndpoint  = attribute.Key("hyperliquid.endpoint")
+	AttrTransport = attribute.Key("hyperliquid.transport")
+	AttrCoin      = attribute.Key("hyperliquid.coin")
+	AttrOid       = attribute.Key("hyperliquid.oid")
+	AttrCloid     = attribute.Key("hyperliquid.cloid")
+	AttrCloids    = attribute.Key("hyperliquid.cloids")
+	AttrStatus    = attribute.Key("hyperliquid.status")
+	AttrOrders    = attribute.Key("hyperliquid.orders")
+	AttrSigning   = attribute.Key("hyperliquid.signing")
+)
+
+// DefaultTracing creates spans with the global tracer provider (otel.GetTracerProvider)
+var DefaultTracing = NewTracing(nil)
+
+// Tracing creates OpenTelemetry spans that follow an order from OrderContext or BulkOrdersContext
+// through signing, the request (WebSocket post or HTTP) and response parsing to the orderUpdates
+// and userFills events that reference its cloid. Orders without cloid are only traced up to the
+// response. It is safe for concurrent use.
+//
+// The ExchangeAPI placing orders and the WebSocketAPI receiving their events must share a Tracing.
+type Tracing struct {
+	tracer  trace.Tracer
+	mu      sync.Mutex
+	orders  map[string]*orderTrace // Span of the order by cloid
+	ttl     []orderExpiry          // Expiries after ORDER_TRACE_TTL in insertion order
+	grace   []orderExpiry          // Expiries after ORDER_TRACE_GRACE in insertion order
+	pending atomic.Int64           // len(orders), checked without lock for every stream message
+}
+
+// orderTrace is the span of an order waiting for WebSocket events
+type orderTrace struct {
+	span    trace.SpanContext
+	expires time.Time
+	final   bool // Reached a final status, only fills are still traced
+}
+
+// orderExpiry is a queued expiry of an order. It is stale if the expiry of the order changed since.
+type orderExpiry struct {
+	cloid   string
+	expires time.Time
+}
+
+// NewTracing creates a Tracing with spans of provider, the global tracer provider if nil
+func NewTracing(provider trace.TracerProvider) *Tracing {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracing{
+		tracer: provider.Tracer(TRACER_NAME),
+		orders: make(map[string]*orderTrace),
+	}
+}
+
+// Tracer returns the tracer of the spans
+func (t *Tracing) Tracer() trace.Tracer {
+	return t.tracer
+}
+
+// start starts a span as child of the span in ctx
+func (t *Tracing) start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name, opts...)
+}
+
+// endSpan records err on span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// trackOrders remembers the span in ctx for the cloids of requests, so their WebSocket
+// events are traced as its children
+func (t *Tracing) trackOrders(ctx context.Context, requests []OrderRequest) {
+	span := trace.SpanContextFromContext(ctx)
+	if !span.IsValid() {
+		return
+	}
+	now := time.Now()
+	expires := now.Add(ORDER_TRACE_TTL)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.evictLocked(now)
+	for _, request := range requests {
+		if request.Cloid != "" {
+			cloid := strings.ToLower(request.Cloid)
+			t.orders[cloid] = &orderTrace{span: span, expires: expires}
+			t.ttl = append(t.ttl, orderExpiry{cloid: cloid, expires: expires})
+		}
+	}
+	t.pending.Store(int64(len(t.orders)))
+}
+
+// evictLocked forgets the orders that expired by now. The queues are ordered by expiry,
+// so only expired entries are visited. The caller must hold t.mu.
+func (t *Tracing) evictLocked(now time.Time) {
+	t.ttl = t.evictQueueLocked(t.ttl, now)
+	t.grace = t.evictQueueLocked(t.grace, now)
+	t.pending.Store(int64(len(t.orders)))
+}
+
+func (t *Tracing) evictQueueLocked(queue []orderExpiry, now time.Time) []orderExpiry {
+	for len(queue) > 0 && !queue[0].expires.After(now) {
+		if order, exists := t.orders[queue[0].cloid]; exists && order.expires.Equal(queue[0].expires) {
+			delete(t.orders, queue[0].cloid)
+		}
+		queue = queue[1:]
+	}
+	return queue
+}
+
+// orderSpan returns the span of the order with cloid for an orderUpdates event with status.
+// After a final status the order is kept for ORDER_TRACE_GRACE to trace late fills.
+func (t *Tracing) orderSpan(cloid string, status string) (trace.SpanContext, bool) {
+	if cloid == "" {
+		return trace.SpanContext{}, false
+	}
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.evictLocked(now)
+	order, exists := t.orders[strings.ToLower(cloid)]
+	if !exists || order.final {
+		return trace.SpanContext{}, false
+	}
+	if isFinalOrderStatus(status) {
+		order.final = true
+		order.expires = now.Add(ORDER_TRACE_GRACE)
+		t.grace = append(t.grace, orderExpiry{cloid: strings.ToLower(cloid), expires: order.expires})
+	}
+	return order.span, true
+}
+
+// fillSpan returns the span of the order with cloid for a userFills event
+func (t *Tracing) fillSpan(cloid string) (trace.SpanContext, bool) {
+	if cloid == "" {
+		return trace.SpanContext{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.evictLocked(time.Now())
+	order, exists := t.orders[strings.ToLower(cloid)]
+	if !exists {
+		return trace.SpanContext{}, false
+	}
+	return order.span, true
+}
+
+// forgetOrder stops tracing the order with cloid, e.g. because it was rejected
+func (t *Tracing) forgetOrder(cloid string) {
+	if cloid == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, strings.ToLower(cloid))
+	t.pending.Store(int64(len(t.orders)))
+}
+
+// isFinalOrderStatus checks if no further updates follow an order status
+func isFinalOrderStatus(status string) bool {
+	return status == "filled" || status == "canceled" || status == "rejected" ||
+		strings.HasSuffix(status, "Canceled") || strings.HasSuffix(status, "Rejected")
+}
+
+// traceStreamEvent creates a span for every order update and fill of a tracked order
+func (t *Tracing) traceStreamEvent(data interface{}) {
+	if t.pending.Load() == 0 {
+		return
+	}
+	switch update := data.(type) {
+	case []OrderUpdate:
+		for i := range update {
+			order := &update[i].Order
+			parent, exists := t.orderSpan(order.Cloid, update[i].Status)
+			if !exists {
+				continue
+			}
+			_, span := t.start(trace.ContextWithSpanContext(context.Background(), parent), "hyperliquid.orderUpdate",
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					AttrCoin.String(order.Coin),
+					AttrOid.Int64(order.Oid),
+					AttrCloid.String(order.Cloid),
+					AttrStatus.String(update[i].Status),
+				))
+			span.End()
+		}
+	case UserFills:
+		if update.IsSnapshot {
+			return
+		}
+		for i := range update.Fills {
+			fill := &update.Fills[i]
+			parent, exists := t.fillSpan(fill.Cloid)
+			if !exists {
+				continue
+			}
+			_, span := t.start(trace.ContextWithSpanContext(context.Background(), parent), "hyperliquid.fill",
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					AttrCoin.String(fill.Coin),
+					AttrOid.Int(fill.Oid),
+					AttrCloid.String(fill.Cloid),
+					attribute.Float64("hyperliquid.px", fill.Px),
+					attribute.Float64("hyperliquid.sz", fill.Sz),
+				))
+			span.End()
+		}
+	}
+}
+
+// traceOrderStatuses adds an event for the status of every order of a bulk order response
+func traceOrderStatuses(span trace.Span, requests []OrderRequest, response *OrderResponse) {
+	if response == nil || !span.IsRecording() {
+		return
+	}
+	for i, status := range response.Response.Data.Statuses {
+		if i >= len(requests) {
+			break
+		}
+		attrs := []attribute.KeyValue{AttrCoin.String(requests[i].Coin), AttrCloid.String(requests[i].Cloid)}
+		switch {
+		case status.Error != "":
+			span.AddEvent("rejected", trace.WithAttributes(append(attrs, attribute.String("error", status.Error))...))
+		case status.Filled.OrderId != 0:
+			span.AddEvent("filled", trace.WithAttributes(append(attrs, AttrOid.Int(status.Filled.OrderId))...))
+		case status.Resting.OrderId != 0:
+			span.AddEvent("resting", trace.WithAttributes(append(attrs, AttrOid.Int(status.Resting.OrderId))...))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package hyperliquid
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestOrderTracing tests that the spans of an order and its WebSocket events form one trace
+func TestOrderTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+	tracing := NewTracing(provider)
+
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSPostRequest
+		if FastUnmarshal(message, &request); request.Method == "post" {
+			replyToPost(t, conn, message, "action", `{"status":"ok","response":{"type":"order","data":{"statuses":[{"resting":{"oid":42}},{"error":"Insufficient margin"}]}}}`)
+		}
+	})
+	ws.SetTracing(tracing)
+	api := newTestExchangeAPI(t, ws)
+	api.SetTracing(tracing)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "strategy")
+	cloid := GetRandomCloid()
+	rejected := GetRandomCloid()
+	orderType := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	_, err := api.BulkOrdersContext(ctx, []OrderRequest{
+		{Coin: "BTC", IsBuy: true, Sz: 0.001, LimitPx: 50000, OrderType: orderType, Cloid: cloid},
+		{Coin: "BTC", IsBuy: true, Sz: 10, LimitPx: 50000, OrderType: orderType, Cloid: rejected},
+	}, GroupingNa, false)
+	if err != nil {
+		t.Fatalf("BulkOrdersContext failed: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	byName := make(map[string]tracetest.SpanStub)
+	for _, span := range spans {
+		if span.SpanContext.TraceID() != parent.SpanContext().TraceID() {
+			t.Errorf("Span %s is not part of the trace", span.Name)
+		}
+		byName[span.Name] = span
+	}
+	bulk, exists := byName["hyperliquid.BulkOrders"]
+	if !exists {
+		t.Fatalf("Missing BulkOrders span, got %v", spans)
+	}
+	if bulk.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("Expected BulkOrders span to be a child of the caller's span")
+	}
+	for _, name := range []string{"hyperliquid.sign", "hyperliquid.request", "hyperliquid.parseResponse"} {
+		if span, exists := byName[name]; !exists {
+			t.Errorf("Missing %s span", name)
+		} else if span.Parent.SpanID() != bulk.SpanContext.SpanID() {
+			t.Errorf("Expected %s span to be a child of the BulkOrders span", name)
+		}
+	}
+	if len(bulk.Events) != 2 || bulk.Events[0].Name != "resting" || bulk.Events[1].Name != "rejected" {
+		t.Errorf("Expected resting and rejected events, got %v", bulk.Events)
+	}
+
+	// Stream events of the resting order are children of the BulkOrders span
+	exporter.Reset()
+	if _, err := ws.SubscribeOrderUpdates(api.AccountAddress(), func(data interface{}) {}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	ws.processJSONMessage([]byte(`{"channel":"orderUpdates","data":[{"order":{"coin":"BTC","side":"B","limitPx":"50000","sz":"0.001","oid":42,"timestamp":1,"origSz":"0.001","cloid":"` + cloid + `"},"status":"filled","statusTimestamp":2}]}`))
+	ws.processJSONMessage([]byte(`{"channel":"orderUpdates","data":[{"order":{"coin":"BTC","side":"B","limitPx":"50000","sz":"10","oid":43,"timestamp":1,"origSz":"10","cloid":"` + rejected + `"},"status":"open","statusTimestamp":2}]}`))
+	ws.processJSONMessage([]byte(`{"channel":"orderUpdates","data":[{"order":{"coin":"BTC","side":"B","limitPx":"50000","sz":"0.001","oid":42,"timestamp":1,"origSz":"0.001","cloid":"` + cloid + `"},"status":"open","statusTimestamp":3}]}`))
+	spans = exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 order update span, got %d", len(spans))
+	}
+	if spans[0].Name != "hyperliquid.orderUpdate" || spans[0].Parent.SpanID() != bulk.SpanContext.SpanID() {
+		t.Errorf("Expected order update span as child of the BulkOrders span, got %s", spans[0].Name)
+	}
+
+	// Fills arriving after the filled order update are still traced
+	exporter.Reset()
+	if _, err := ws.SubscribeUserFills(api.AccountAddress(), func(data interface{}) {}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	ws.processJSONMessage([]byte(`{"channel":"userFills","data":{"user":"` + api.AccountAddress() + `","fills":[{"coin":"BTC","px":"50000","sz":"0.001","side":"B","time":4,"startPosition":"0","dir":"Open Long","closedPnl":"0","hash":"0x1","oid":42,"crossed":false,"fee":"0","tid":1,"feeToken":"USDC","cloid":"` + cloid + `"}]}}`))
+	spans = exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "hyperliquid.fill" || spans[0].Parent.SpanID() != bulk.SpanContext.SpanID() {
+		t.Errorf("Expected fill span as child of the BulkOrders span, got %v", spans)
+	}
+
+	// Orders are forgotten once the grace period after their final status is over
+	tracing.mu.Lock()
+	tracing.evictLocked(time.Now().Add(ORDER_TRACE_GRACE))
+	tracked := len(tracing.orders)
+	tracing.mu.Unlock()
+	if tracked != 0 {
+		t.Errorf("Expected no tracked orders after the grace period, got %d", tracked)
+	}
+}
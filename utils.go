@@ -13,10 +13,10 @@ import (
 var nonceCounter = time.Now().UnixMilli()
 
 // Hyperliquid uses timestamps in milliseconds for nonce
-// GetNonce returns a unique nonce that is always at least the current time in milliseconds.
-// It ensures thread-safe updates using atomic operations.
+// GetNonce returns a unique nonce that is always at least the current time in milliseconds,
+// shifted by the offset of SetNonceClockOffset. It ensures thread-safe updates using atomic operations.
 func GetNonce() uint64 {
-	now := time.Now().UnixMilli()
+	now := time.Now().UnixMilli() + nonceClockOffset.Load()
 	for {
 		// Load the current nonce value atomically.
 		current := atomic.LoadInt64(&nonceCounter)
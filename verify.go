@@ -0,0 +1,317 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var ErrUnorderedAction = errors.New("action key order is unknown, use the action struct or its raw JSON")
+
+// ErrUnknownAction is returned for action types that are neither known L1 nor user signable actions.
+// Recovering them with the wrong scheme would return a wrong address.
+var ErrUnknownAction = errors.New("unknown action type")
+
+// userSignedAction describes the EIP-712 payload of a user signable action type
+type userSignedAction struct {
+	types       []apitypes.Type
+	primaryType string
+}
+
+var userSignedActions = map[string]userSignedAction{
+	"withdraw3":         {withdrawSignTypes, withdrawPrimaryType},
+	"approveBuilderFee": {approveBuilderFeeSignTypes, approveBuilderFeePrimaryType},
+}
+
+// l1Actions are the action types whose signature signs the msgpack hash of the action.
+// User signable types missing from userSignedActions (usdSend, spotSend, approveAgent, ...)
+// must not be listed here.
+var l1Actions = map[string]bool{
+	"order":                   true,
+	"cancel":                  true,
+	"cancelByCloid":           true,
+	"modify":                  true,
+	"batchModify":             true,
+	"scheduleCancel":          true,
+	"updateLeverage":          true,
+	"updateIsolatedMargin":    true,
+	"topUpIsolatedOnlyMargin": true,
+	"twapOrder":               true,
+	"twapCancel":              true,
+	"vaultTransfer":           true,
+	"createVault":             true,
+	"vaultModify":             true,
+	"vaultDistribute":         true,
+	"createSubAccount":        true,
+	"subAccountModify":        true,
+	"subAccountTransfer":      true,
+	"subAccountSpotTransfer":  true,
+	"setReferrer":             true,
+	"setDisplayName":          true,
+	"spotUser":                true,
+	"claimRewards":            true,
+	"evmUserModify":           true,
+	"reserveRequestWeight":    true,
+	"noop":                    true,
+}
+
+// ParseExchangeRequest parses an /exchange request body. The action is kept as raw JSON,
+// so the signer can be recovered with RecoverSigner.
+func ParseExchangeRequest(data []byte) (*ExchangeRequest, error) {
+	var raw struct {
+		Action       jsoniter.RawMessage `json:"action"`
+		Nonce        uint64              `json:"nonce"`
+		Signature    RsvSignature        `json:"signature"`
+		VaultAddress *string             `json:"vaultAddress"`
+		ExpiresAfter *uint64             `json:"expiresAfter"`
+	}
+	if err := FastUnmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Action) == 0 {
+		return nil, APIError{Message: "Request has no action"}
+	}
+	return &ExchangeRequest{
+		Action:       raw.Action,
+		Nonce:        raw.Nonce,
+		Signature:    raw.Signature,
+		VaultAddress: raw.VaultAddress,
+		ExpiresAfter: raw.ExpiresAfter,
+	}, nil
+}
+
+// RecoverSigner returns the address that signed request, for L1 and user signable actions.
+// Action types it does not know return ErrUnknownAction.
+// L1 actions are recovered for the network given by isMainnet, user signable actions carry
+// their network in the action.
+//
+// The action can be an action struct of this package or its raw JSON (jsoniter.RawMessage or []byte),
+// e.g. from ParseExchangeRequest. Decoded maps return ErrUnorderedAction, because the key order
+// is part of the signed hash of L1 actions.
+func RecoverSigner(request ExchangeRequest, isMainnet bool) (string, error) {
+	typedData, err := requestTypedData(request, isMainnet)
+	if err != nil {
+		return "", err
+	}
+	return recoverTypedDataSigner(typedData, request.Signature)
+}
+
+// requestTypedData rebuilds the EIP-712 typed data that the signature of request signs,
+// from its action, nonce, vault address and expiry
+func requestTypedData(request ExchangeRequest, isMainnet bool) (apitypes.TypedData, error) {
+	var actionJSON []byte
+	var err error
+	switch action := request.Action.(type) {
+	case jsoniter.RawMessage:
+		actionJSON = action
+	case []byte:
+		actionJSON = action
+	case map[string]interface{}:
+		return apitypes.TypedData{}, ErrUnorderedAction
+	default:
+		if actionJSON, err = FastMarshal(action); err != nil {
+			return apitypes.TypedData{}, err
+		}
+	}
+	var message map[string]interface{}
+	if err := FastUnmarshal(actionJSON, &message); err != nil {
+		return apitypes.TypedData{}, fmt.Errorf("error while decoding action: %w", err)
+	}
+	actionType, _ := message["type"].(string)
+
+	if userSigned, exists := userSignedActions[actionType]; exists {
+		return userSignedTypedData(message, userSigned)
+	}
+	if !l1Actions[actionType] {
+		return apitypes.TypedData{}, fmt.Errorf("%w: %q", ErrUnknownAction, actionType)
+	}
+	encoded, err := encodeActionMsgpack(request.Action, actionJSON)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	var vaultAddress string
+	if request.VaultAddress != nil {
+		vaultAddress = *request.VaultAddress
+	}
+	var expiresAfter uint64
+	if request.ExpiresAfter != nil {
+		expiresAfter = *request.ExpiresAfter
+	}
+	hash := hashEncodedAction(encoded, vaultAddress, request.Nonce, expiresAfter)
+	return SignRequestToEIP712TypedData(newL1SignRequest(hash.Bytes(), isMainnet)), nil
+}
+
+// VerifySignature reports whether request was signed by address
+func VerifySignature(request ExchangeRequest, address string, isMainnet bool) (bool, error) {
+	signer, err := RecoverSigner(request, isMainnet)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(signer, address), nil
+}
+
+// userSignedTypedData rebuilds the typed data of a user signable action.
+// The domain chain id is the signatureChainId of the action.
+func userSignedTypedData(message map[string]interface{}, action userSignedAction) (apitypes.TypedData, error) {
+	chainID, _ := message["signatureChainId"].(string)
+	id, ok := math.ParseBig256(chainID)
+	if !ok {
+		return apitypes.TypedData{}, fmt.Errorf("invalid signatureChainId: %q", chainID)
+	}
+	delete(message, "type")
+	delete(message, "signatureChainId")
+	request := &SignRequest{
+		DomainName:  "HyperliquidSignTransaction",
+		PrimaryType: action.primaryType,
+		DType:       action.types,
+		DTypeMsg:    message,
+	}
+	typedData := SignRequestToEIP712TypedData(request)
+	typedData.Domain.ChainId = (*math.HexOrDecimal256)(id)
+	return typedData, nil
+}
+
+// recoverTypedDataSigner recovers the address that signed typedData
+func recoverTypedDataSigner(typedData apitypes.TypedData, signature RsvSignature) (string, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return "", err
+	}
+	r, ok := parseSignatureHex(signature.R)
+	if !ok {
+		return "", fmt.Errorf("invalid signature r: %q", signature.R)
+	}
+	s, ok := parseSignatureHex(signature.S)
+	if !ok {
+		return "", fmt.Errorf("invalid signature s: %q", signature.S)
+	}
+	if signature.V != 27 && signature.V != 28 {
+		return "", fmt.Errorf("invalid signature v: %d", signature.V)
+	}
+	if r.BitLen() > 256 || s.BitLen() > 256 {
+		return "", errors.New("invalid signature length")
+	}
+	sig := make([]byte, 65)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = signature.V - 27
+	publicKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return "", err
+	}
+	return crypto.PubkeyToAddress(*publicKey).Hex(), nil
+}
+
+// parseSignatureHex parses r or s of a signature, with or without leading zeros
+func parseSignatureHex(value string) (*big.Int, bool) {
+	value = strings.TrimPrefix(value, "0x")
+	if value == "" {
+		return nil, false
+	}
+	return new(big.Int).SetString(value, 16)
+}
+
+// encodeActionMsgpack returns the msgpack encoding of an action the way it was hashed when signed.
+// Action structs are encoded directly, raw JSON is converted keeping its key order.
+func encodeActionMsgpack(action any, actionJSON []byte) ([]byte, error) {
+	switch action.(type) {
+	case jsoniter.RawMessage, []byte:
+		return JSONToMsgpack(actionJSON)
+	}
+	return msgpack.Marshal(action)
+}
+
+// JSONToMsgpack converts JSON to msgpack preserving the key order of objects.
+// Integers use the smallest encoding, like the reference Python SDK.
+func JSONToMsgpack(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	if err := encodeJSONValue(data, enc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeJSONValue(data []byte, enc *msgpack.Encoder) error {
+	iter := jsoniter.ParseBytes(fastJSON, data)
+	var err error
+	switch iter.WhatIsNext() {
+	case jsoniter.ObjectValue:
+		var keys []string
+		var values [][]byte
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+			keys = append(keys, key)
+			values = append(values, iter.SkipAndReturnBytes())
+			return true
+		})
+		if iter.Error != nil {
+			return iter.Error
+		}
+		if err = enc.EncodeMapLen(len(keys)); err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if err = enc.EncodeString(key); err != nil {
+				return err
+			}
+			if err = encodeJSONValue(values[i], enc); err != nil {
+				return err
+			}
+		}
+		return nil
+	case jsoniter.ArrayValue:
+		var values [][]byte
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			values = append(values, iter.SkipAndReturnBytes())
+			return true
+		})
+		if iter.Error != nil {
+			return iter.Error
+		}
+		if err = enc.EncodeArrayLen(len(values)); err != nil {
+			return err
+		}
+		for _, value := range values {
+			if err = encodeJSONValue(value, enc); err != nil {
+				return err
+			}
+		}
+		return nil
+	case jsoniter.StringValue:
+		err = enc.EncodeString(iter.ReadString())
+	case jsoniter.NumberValue:
+		number := string(iter.ReadNumber())
+		if i, parseErr := strconv.ParseInt(number, 10, 64); parseErr == nil {
+			err = enc.EncodeInt(i)
+		} else if u, parseErr := strconv.ParseUint(number, 10, 64); parseErr == nil {
+			err = enc.EncodeUint(u)
+		} else if f, ok := new(big.Float).SetString(number); ok {
+			value, _ := f.Float64()
+			err = enc.EncodeFloat64(value)
+		} else {
+			return fmt.Errorf("invalid JSON number: %s", number)
+		}
+	case jsoniter.BoolValue:
+		err = enc.EncodeBool(iter.ReadBool())
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		err = enc.EncodeNil()
+	default:
+		return fmt.Errorf("invalid JSON value: %s", data)
+	}
+	// Scalars at the end of the input stop with io.EOF
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	return err
+}
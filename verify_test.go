@@ -0,0 +1,132 @@
+package hyperliquid
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type dummyAction struct {
+	Type string `msgpack:"type" json:"type"`
+	Num  int    `msgpack:"num" json:"num"`
+}
+
+func assertSignature(t *testing.T, name string, sig RsvSignature, r string, s string, v byte) {
+	t.Helper()
+	gotR, _ := parseSignatureHex(sig.R)
+	gotS, _ := parseSignatureHex(sig.S)
+	wantR, _ := parseSignatureHex(r)
+	wantS, _ := parseSignatureHex(s)
+	if gotR == nil || gotS == nil || gotR.Cmp(wantR) != 0 || gotS.Cmp(wantS) != 0 || sig.V != v {
+		t.Errorf("%s: expected r=%s s=%s v=%d, got %+v", name, r, s, v, sig)
+	}
+}
+
+// TestSigningVectors checks L1 signing against the vectors of the reference Python SDK
+func TestSigningVectors(t *testing.T) {
+	mainnet := newTestExchangeAPI(t, nil)
+	testnet := newTestExchangeAPI(t, nil)
+	testnet.Client = *NewClient(false)
+	testnet.SetPrivateKey(testPrivateKey)
+
+	action := dummyAction{Type: "dummy", Num: 100000000000}
+	v, r, s, err := mainnet.SignL1Action(action, 0)
+	if err != nil {
+		t.Fatalf("SignL1Action failed: %v", err)
+	}
+	assertSignature(t, "mainnet", ToTypedSig(r, s, v),
+		"0x53749d5b30552aeb2fca34b530185976545bb22d0b3ce6f62e31be961a59298",
+		"0x755c40ba9bf05223521753995abb2f73ab3229be8ec921f350cb447e384d8ed8", 27)
+
+	v, r, s, err = testnet.SignL1Action(action, 0)
+	if err != nil {
+		t.Fatalf("SignL1Action failed: %v", err)
+	}
+	assertSignature(t, "testnet", ToTypedSig(r, s, v),
+		"0x542af61ef1f429707e3c76c5293c80d01f74ef853e34b76efffcb57e574f9510",
+		"0x17b8b32f086e8cdede991f1e2c529f5dd5297cbe8128500e00cbaf766204a613", 28)
+
+	withdrawal := WithdrawAction{
+		Type:             "withdraw3",
+		Destination:      "0x5e9ee1089755c3435139848e47e6635505d5a13a",
+		Amount:           "1",
+		Time:             1687816341423,
+		HyperliquidChain: "Testnet",
+		SignatureChainID: "0x66eee",
+	}
+	v, r, s, err = testnet.SignWithdrawAction(withdrawal)
+	if err != nil {
+		t.Fatalf("SignWithdrawAction failed: %v", err)
+	}
+	assertSignature(t, "withdrawal", ToTypedSig(r, s, v),
+		"0x8363524c799e90ce9bc41022f7c39b4e9bdba786e5f9c72b20e43e1462c37cf9",
+		"0x58b1411a775938b83e29182e8ef74975f9054c8e97ebf5ec2dc8d51bfc893881", 28)
+}
+
+// TestRecoverSigner tests recovering the signer of L1 and user signable actions from
+// action structs and raw request bodies
+func TestRecoverSigner(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	api := newTestExchangeAPI(t, nil)
+	api.SetBuilder(&BuilderInfo{Builder: "0x0000000000000000000000000000000000000002", Fee: 10})
+	api.SetExpiresAfter(60000000000)
+
+	order := OrderRequest{
+		Coin: "BTC", IsBuy: false, Sz: 0.0123, LimitPx: 123456, ReduceOnly: true,
+		OrderType: OrderType{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: "120000", TpSl: TriggerSl}},
+		Cloid:     GetRandomCloid(),
+	}
+	action, _ := api.buildOrderAction([]OrderRequest{order}, GroupingTpSl, false)
+	request, err := api.newL1Request(context.Background(), action)
+	if err != nil {
+		t.Fatalf("newL1Request failed: %v", err)
+	}
+	signer, err := RecoverSigner(*request, true)
+	if err != nil || signer != address {
+		t.Errorf("Expected signer %s of action struct, got %s (%v)", address, signer, err)
+	}
+	if ok, _ := VerifySignature(*request, address, false); ok {
+		t.Error("Expected L1 signature to be invalid on testnet")
+	}
+
+	body, _ := FastMarshal(request)
+	parsed, err := ParseExchangeRequest(body)
+	if err != nil {
+		t.Fatalf("ParseExchangeRequest failed: %v", err)
+	}
+	if ok, err := VerifySignature(*parsed, address, true); !ok || err != nil {
+		t.Errorf("Expected signature of raw request to verify, got %v", err)
+	}
+	encoded, _ := msgpack.Marshal(action)
+	converted, err := JSONToMsgpack(parsed.Action.(jsoniter.RawMessage))
+	if err != nil || string(converted) != string(encoded) {
+		t.Errorf("Expected JSON conversion to match msgpack encoding:\n%x\n%x", converted, encoded)
+	}
+
+	var decoded ExchangeRequest
+	FastUnmarshal(body, &decoded)
+	if _, err := RecoverSigner(decoded, true); !errors.Is(err, ErrUnorderedAction) {
+		t.Errorf("Expected ErrUnorderedAction for decoded map, got %v", err)
+	}
+
+	usdSend := ExchangeRequest{
+		Action:    jsoniter.RawMessage(`{"type":"usdSend","signatureChainId":"0xa4b1","hyperliquidChain":"Mainnet","destination":"0x0000000000000000000000000000000000000001","amount":"1","time":1}`),
+		Nonce:     1,
+		Signature: request.Signature,
+	}
+	if _, err := RecoverSigner(usdSend, true); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("Expected ErrUnknownAction for usdSend, got %v", err)
+	}
+
+	withdrawal, _ := api.BuildWithdrawEnvelope("0x0000000000000000000000000000000000000001", 10)
+	signature, _ := api.SignEnvelope(withdrawal)
+	signer, err = RecoverSigner(withdrawal.Request(signature), false)
+	if err != nil || signer != address {
+		t.Errorf("Expected signer %s of withdrawal, got %s (%v)", address, signer, err)
+	}
+}
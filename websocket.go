@@ -1,14 +1,18 @@
 package hyperliquid
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/url"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
 )
 
 // IWebSocketAPI is the interface for WebSocket operations
@@ -20,22 +24,40 @@ type IWebSocketAPI interface {
 	SetDebug(status bool)
 
 	// Subscription methods
-	SubscribeOrderbook(coin string, handler SubscriptionHandler) error
-	SubscribeTrades(coin string, handler SubscriptionHandler) error
-	SubscribeUserFills(user string, handler SubscriptionHandler) error
-	SubscribeAllMids(handler SubscriptionHandler) error
-	SubscribeUserEvents(user string, handler SubscriptionHandler) error
-	SubscribeUserFundings(user string, handler SubscriptionHandler) error
-	SubscribeUserNonFundingLedgerUpdates(user string, handler SubscriptionHandler) error
-	SubscribeUserTwapSliceFills(user string, handler SubscriptionHandler) error
-	SubscribeUserTwapHistory(user string, handler SubscriptionHandler) error
-	SubscribeActiveAssetCtx(coin string, handler SubscriptionHandler) error
-	SubscribeActiveAssetData(user string, coin string, handler SubscriptionHandler) error
-	SubscribeBbo(coin string, handler SubscriptionHandler) error
-	SubscribeCandle(coin string, interval string, handler SubscriptionHandler) error
-	SubscribeOrderUpdates(user string, handler SubscriptionHandler) error
-	SubscribeNotification(user string, handler SubscriptionHandler) error
-	SubscribeWebData2(user string, handler SubscriptionHandler) error
+	SubscribeOrderbook(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeTrades(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeUserFills(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeAllMids(handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeUserEvents(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeUserFundings(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeUserNonFundingLedgerUpdates(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeUserTwapSliceFills(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeUserTwapHistory(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeActiveAssetCtx(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeActiveAssetData(user string, coin string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeBbo(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeCandle(coin string, interval string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeOrderUpdates(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeNotification(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+	SubscribeWebData2(user string, handler SubscriptionHandler) (*SubscriptionHandle, error)
+
+	// Channel based subscription methods, see Seq for iterating them
+	OrderbookChan(ctx context.Context, coin string) (<-chan L2BookSnapshot, error)
+	TradesChan(ctx context.Context, coin string) (<-chan []Trade, error)
+	UserFillsChan(ctx context.Context, user string) (<-chan UserFills, error)
+	AllMidsChan(ctx context.Context) (<-chan AllMids, error)
+	UserEventsChan(ctx context.Context, user string) (<-chan UserEvent, error)
+	UserFundingsChan(ctx context.Context, user string) (<-chan UserFundings, error)
+	UserNonFundingLedgerUpdatesChan(ctx context.Context, user string) (<-chan UserNonFundingLedgerUpdates, error)
+	UserTwapSliceFillsChan(ctx context.Context, user string) (<-chan UserTwapSliceFills, error)
+	UserTwapHistoryChan(ctx context.Context, user string) (<-chan UserTwapHistory, error)
+	ActiveAssetCtxChan(ctx context.Context, coin string) (<-chan ActiveAssetCtx, error)
+	ActiveAssetDataChan(ctx context.Context, user string, coin string) (<-chan ActiveAssetData, error)
+	BboChan(ctx context.Context, coin string) (<-chan Bbo, error)
+	CandleChan(ctx context.Context, coin string, interval string) (<-chan CandleSnapshot, error)
+	OrderUpdatesChan(ctx context.Context, user string) (<-chan []OrderUpdate, error)
+	NotificationChan(ctx context.Context, user string) (<-chan Notification, error)
+	WebData2Chan(ctx context.Context, user string) (<-chan WebData2, error)
 
 	// Unsubscribe methods
 	UnsubscribeOrderbook(coin string) error
@@ -57,6 +79,7 @@ type IWebSocketAPI interface {
 
 	// Post request methods
 	PostRequest(requestType string, payload interface{}) (*WSPostResponseData, error)
+	PostRequestWithTimeout(requestType string, payload interface{}, timeout time.Duration) (*WSPostResponseData, error)
 	PostInfoRequest(payload interface{}) (*WSPostResponseData, error)
 	PostActionRequest(payload interface{}) (*WSPostResponseData, error)
 	PostOrderRequest(action interface{}, nonce uint64, signature RsvSignature, vaultAddress *string) (*WSPostResponseData, error)
@@ -84,15 +107,16 @@ const (
 	SubTypeAllMids
 )
 
-// Subscription represents a subscription with optimized matching
+// Subscription represents an upstream subscription with optimized matching.
+// It is shared by all listeners of the same channel.
 type Subscription struct {
-	Type     SubscriptionType
-	Channel  chan interface{}
-	Params   map[string]string // Pre-split parameters
-	Handler  SubscriptionHandler
-	User     string // For user-specific subscriptions
-	Coin     string // For coin-specific subscriptions
-	Interval string // For candle subscriptions
+	Key       string // Channel key, e.g. "l2Book:BTC"
+	Type      SubscriptionType
+	Params    map[string]string // Pre-split parameters
+	User      string            // For user-specific subscriptions
+	Coin      string            // For coin-specific subscriptions
+	Interval  string            // For candle subscriptions
+	listeners []*SubscriptionHandle
 }
 
 type WebSocketAPI struct {
@@ -102,23 +126,32 @@ type WebSocketAPI struct {
 	channelHandlers  map[string][]*Subscription // Fast lookup by channel
 	postResponses    map[int]chan WSPostResponseData
 	mu               sync.RWMutex
+	upstreamMu       sync.Mutex // Serialises listener changes with their upstream (un)subscribe messages
 	reconnectCount   int
-	isConnected      bool
+	isConnected      atomic.Bool
 	Debug            bool
 	manualDisconnect bool         // Flag to prevent auto-reconnect on manual disconnect
 	latencyMs        atomic.Int64 // Current latency in milliseconds
 	lastPingTime     atomic.Value // Timestamp of the last ping sent (time.Time)
 	nextPostID       atomic.Int64 // Next post request ID
+	postTimeout      atomic.Int64 // Default timeout for post requests (time.Duration)
 
 	// Pre-marshaled messages for efficiency
 	pingMessageBytes []byte
 
 	// Performance optimizations
-	messageBufferPool sync.Pool // Pool for message buffers
-	responsePool      sync.Pool // Pool for WSResponse objects
+	messageBufferPool sync.Pool // Pool for read buffers (*bytes.Buffer)
 
 	// Goroutine management
 	pingStopChan chan struct{} // Channel to stop ping handler
+
+	telemetry *Telemetry   // Round trip and stream lag measurements, DefaultTelemetry if nil
+	metrics   MetricsHook  // Connection and message metrics, NopMetrics if nil
+	logger    *slog.Logger // Structured logger, see Logger
+	tracing   *Tracing     // Spans of order events, DefaultTracing if nil
+
+	recorder atomic.Pointer[Recorder] // Records subscription messages, see SetRecorder
+	replay   bool                     // Fed by a Replayer, subscriptions are local and handlers are called synchronously
 }
 
 // WSSubscription represents a WebSocket subscription request
@@ -133,7 +166,9 @@ type WSResponse struct {
 	Data    interface{} `json:"data"`
 }
 
-// SubscriptionHandler is a function type for handling subscription data
+// SubscriptionHandler is a function type for handling subscription data.
+// data is the decoded value of the channel, e.g. an L2BookSnapshot for SubscribeOrderbook
+// or a []Trade for SubscribeTrades, see decodeSubscriptionData.
 type SubscriptionHandler func(data interface{})
 
 // WSPostRequest represents a WebSocket post request
@@ -163,8 +198,60 @@ type WSPostResponseData struct {
 
 // WSPostResponseBody represents the response body of a post response
 type WSPostResponseBody struct {
-	Type    string      `json:"type"`
-	Payload interface{} `json:"payload"`
+	Type       string      `json:"type"`
+	Payload    interface{} `json:"payload"`
+	RawPayload []byte      `json:"-"` // Payload exactly as received from the server
+}
+
+// wsPostResponseRaw is used to decode post responses without losing the raw payload bytes
+type wsPostResponseRaw struct {
+	Data struct {
+		ID       int `json:"id"`
+		Response struct {
+			Type    string              `json:"type"`
+			Payload jsoniter.RawMessage `json:"payload"`
+		} `json:"response"`
+	} `json:"data"`
+}
+
+// DefaultPostTimeout is the default time to wait for a post response
+const DefaultPostTimeout = 30 * time.Second
+
+// Post request errors
+var (
+	// ErrPostTimeout is returned when no response arrived for a post request in time.
+	// The request was sent, so its outcome is unknown.
+	ErrPostTimeout = errors.New("post request timeout")
+	// ErrPostNotSent is returned when a post request could not be written to the connection.
+	// It is always safe to retry such a request over HTTP.
+	ErrPostNotSent = errors.New("post request not sent")
+	// ErrPostConnectionClosed is returned when the connection was closed while waiting for a response
+	ErrPostConnectionClosed = errors.New("connection closed while waiting for post response")
+)
+
+// Bytes returns the post response payload in the same format as the HTTP API returns it.
+// Info responses are unwrapped to their data, action responses are returned untouched
+// and error responses are converted to an APIError.
+func (body *WSPostResponseBody) Bytes() ([]byte, error) {
+	switch body.Type {
+	case "error":
+		var message string
+		if err := FastUnmarshal(body.RawPayload, &message); err != nil {
+			message = string(body.RawPayload)
+		}
+		return nil, APIError{Message: message}
+	case "info":
+		var info struct {
+			Type string              `json:"type"`
+			Data jsoniter.RawMessage `json:"data"`
+		}
+		if err := FastUnmarshal(body.RawPayload, &info); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal info post response: %w", err)
+		}
+		return info.Data, nil
+	default:
+		return body.RawPayload, nil
+	}
 }
 
 // PostResponseHandler is a function type for handling post response data
@@ -191,19 +278,14 @@ func NewWebSocketAPI(isMainnet bool) *WebSocketAPI {
 		pingMessageBytes: pingBytes,
 		messageBufferPool: sync.Pool{
 			New: func() interface{} {
-				return make([]byte, 0, 4096) // Pre-allocate 4KB buffer
-			},
-		},
-		responsePool: sync.Pool{
-			New: func() interface{} {
-				return &WSResponse{}
+				return bytes.NewBuffer(make([]byte, 0, 4096)) // Pre-allocate 4KB buffer
 			},
 		},
 	}
 
 	client.manualDisconnect = false
 	client.nextPostID.Store(1)
-	client.isConnected = false
+	client.postTimeout.Store(int64(DefaultPostTimeout))
 	client.reconnectCount = 0
 	return client
 }
@@ -215,33 +297,33 @@ func (ws *WebSocketAPI) Connect() error {
 		return fmt.Errorf("failed to parse WebSocket URL: %w", err)
 	}
 
-	if ws.Debug {
-		log.Printf("Connecting to WebSocket URL: %s", u.String())
-	}
+	ws.Logger().Debug("Connecting to WebSocket", "url", u.String())
 
 	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 
+	ws.mu.Lock()
 	// Stop any existing ping handler before starting a new one
 	if ws.pingStopChan != nil {
 		close(ws.pingStopChan)
 	}
 
 	ws.conn = conn
-	ws.isConnected = true
+	ws.isConnected.Store(true)
 	ws.manualDisconnect = false // Reset manual disconnect flag
 	ws.reconnectCount = 0
-	ws.pingStopChan = make(chan struct{}) // Create new stop channel
-	ws.lastPingTime.Store(time.Time{})    // Reset ping time to avoid immediate timeout
+	stop := make(chan struct{}) // Create new stop channel
+	ws.pingStopChan = stop
+	ws.lastPingTime.Store(time.Time{}) // Reset ping time to avoid immediate timeout
+	ws.Metrics().SetWSConnected(true)
+	ws.mu.Unlock()
 
-	if ws.Debug {
-		log.Println("WebSocket connection established")
-	}
+	ws.Logger().Debug("WebSocket connection established", "url", ws.url)
 
-	go ws.readMessages()
-	go ws.pingHandler()
+	go ws.readMessages(conn)
+	go ws.pingHandler(stop)
 
 	return nil
 }
@@ -251,9 +333,10 @@ func (ws *WebSocketAPI) Disconnect() error {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 
-	ws.isConnected = false
+	ws.isConnected.Store(false)
 	ws.manualDisconnect = true // Prevent auto-reconnect
 	ws.reconnectCount = 0      // Reset reconnect count on manual disconnect
+	ws.Metrics().SetWSConnected(false)
 
 	// Stop ping handler
 	if ws.pingStopChan != nil {
@@ -263,8 +346,10 @@ func (ws *WebSocketAPI) Disconnect() error {
 
 	// Clean up all subscriptions and their goroutines
 	for _, sub := range ws.subscriptions {
-		// Close the subscription channel to terminate the handler goroutine
-		close(sub.Channel)
+		for _, listener := range append([]*SubscriptionHandle(nil), sub.listeners...) {
+			// Close the listener channel to terminate the handler goroutine
+			ws.removeListenerLocked(listener)
+		}
 	}
 
 	// Clear subscription maps
@@ -272,10 +357,7 @@ func (ws *WebSocketAPI) Disconnect() error {
 	ws.channelHandlers = make(map[string][]*Subscription)
 
 	// Clean up post response channels
-	for id, responseChan := range ws.postResponses {
-		close(responseChan)
-		delete(ws.postResponses, id)
-	}
+	ws.failPendingPostsLocked()
 
 	if ws.conn != nil {
 		return ws.conn.Close()
@@ -289,7 +371,8 @@ func (ws *WebSocketAPI) DisconnectForTesting() error {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 
-	ws.isConnected = false
+	ws.isConnected.Store(false)
+	ws.Metrics().SetWSConnected(false)
 	// Don't set manualDisconnect = true, so auto-reconnect will work
 	// Don't reset reconnectCount, so it continues from where it left off
 
@@ -307,7 +390,7 @@ func (ws *WebSocketAPI) DisconnectForTesting() error {
 
 // IsConnected returns the connection status
 func (ws *WebSocketAPI) IsConnected() bool {
-	return ws.isConnected
+	return ws.isConnected.Load()
 }
 
 // SetDebugActive enables debug mode
@@ -315,53 +398,148 @@ func (ws *WebSocketAPI) SetDebug(status bool) {
 	ws.Debug = status
 }
 
-// readMessages reads messages from the WebSocket connection with optimized processing
-func (ws *WebSocketAPI) readMessages() {
+// SetLogger sets the structured logger of the WebSocket. Signatures and keys are redacted
+// from its records, see NewRedactingHandler. nil restores the default logger.
+func (ws *WebSocketAPI) SetLogger(logger *slog.Logger) {
+	ws.logger = redactingLogger(logger)
+}
+
+// Logger returns the logger set with SetLogger. Without one, messages go to slog.Default,
+// or to stdout including debug messages in debug mode.
+func (ws *WebSocketAPI) Logger() *slog.Logger {
+	return clientLogger(ws.logger, ws.Debug)
+}
+
+// debugEnabled reports whether debug messages are logged, to skip them on hot paths
+func (ws *WebSocketAPI) debugEnabled() bool {
+	return ws.Logger().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// SetTelemetry sets the Telemetry that records ping and post round trips and stream lag.
+// It must be set before connecting.
+func (ws *WebSocketAPI) SetTelemetry(telemetry *Telemetry) {
+	ws.telemetry = telemetry
+}
+
+// Telemetry returns the Telemetry of the WebSocket, DefaultTelemetry unless set with SetTelemetry
+func (ws *WebSocketAPI) Telemetry() *Telemetry {
+	if ws.telemetry == nil {
+		return DefaultTelemetry
+	}
+	return ws.telemetry
+}
+
+// Latency returns the round trip time of the last ping, 0 before the first pong
+func (ws *WebSocketAPI) Latency() time.Duration {
+	return time.Duration(ws.latencyMs.Load()) * time.Millisecond
+}
+
+// SetMetrics sets the MetricsHook that receives connection state changes, reconnects,
+// dropped messages and post timeouts. It must be set before connecting.
+func (ws *WebSocketAPI) SetMetrics(metrics MetricsHook) {
+	ws.metrics = metrics
+}
+
+// Metrics returns the MetricsHook of the WebSocket, NopMetrics unless set with SetMetrics
+func (ws *WebSocketAPI) Metrics() MetricsHook {
+	if ws.metrics == nil {
+		return NopMetrics{}
+	}
+	return ws.metrics
+}
+
+// SetTracing sets the Tracing that creates spans for the orderUpdates and userFills events
+// of traced orders. It must be the Tracing of the ExchangeAPI placing the orders.
+func (ws *WebSocketAPI) SetTracing(tracing *Tracing) {
+	ws.tracing = tracing
+}
+
+// Tracing returns the Tracing of the WebSocket, DefaultTracing unless set with SetTracing
+func (ws *WebSocketAPI) Tracing() *Tracing {
+	if ws.tracing == nil {
+		return DefaultTracing
+	}
+	return ws.tracing
+}
+
+// SetRecorder attaches a Recorder that writes every subscription message to its segment files.
+// A nil recorder detaches the current one. It can be changed while connected.
+func (ws *WebSocketAPI) SetRecorder(recorder *Recorder) {
+	ws.recorder.Store(recorder)
+}
+
+// SetPostTimeout sets the default timeout for post requests
+func (ws *WebSocketAPI) SetPostTimeout(timeout time.Duration) {
+	ws.postTimeout.Store(int64(timeout))
+}
+
+// PostTimeout returns the default timeout for post requests
+func (ws *WebSocketAPI) PostTimeout() time.Duration {
+	return time.Duration(ws.postTimeout.Load())
+}
+
+// failPendingPostsLocked releases every request waiting for a post response.
+// The caller must hold ws.mu.
+func (ws *WebSocketAPI) failPendingPostsLocked() {
+	for id, responseChan := range ws.postResponses {
+		close(responseChan)
+		delete(ws.postResponses, id)
+	}
+}
+
+// readMessages reads messages from conn with optimized processing
+func (ws *WebSocketAPI) readMessages(conn *websocket.Conn) {
 	defer func() {
-		ws.isConnected = false
+		ws.mu.Lock()
+		// A reader of a replaced connection must not touch the posts of the current one
+		if ws.conn == conn {
+			ws.isConnected.Store(false)
+			ws.Metrics().SetWSConnected(false)
+			// Responses to pending post requests will never arrive on this connection
+			ws.failPendingPostsLocked()
+		}
+		ws.mu.Unlock()
 	}()
 
 	for {
-		_, message, err := ws.conn.ReadMessage()
+		_, reader, err := conn.NextReader()
 		if err != nil {
-			if ws.Debug {
-				log.Printf("WebSocket read error: %v", err)
-			}
+			ws.Logger().Debug("WebSocket read error", "err", err)
 			// Let ping handler handle reconnection
 			return
 		}
+		buf := ws.getMessageBuffer()
+		if _, err := buf.ReadFrom(reader); err != nil {
+			ws.putMessageBuffer(buf)
+			ws.Logger().Debug("WebSocket read error", "err", err)
+			return
+		}
+		message := buf.Bytes()
 
-		if ws.Debug {
-			log.Printf("Received WebSocket message: %v", message)
+		if ws.debugEnabled() {
+			ws.Logger().Debug("Received WebSocket message", "message", message)
 		}
 
-		// Process all messages as JSON
+		// Process all messages as JSON. Nothing keeps a reference to the message,
+		// so the buffer is reused for the next one.
 		ws.processJSONMessage(message)
+		ws.putMessageBuffer(buf)
 	}
 }
 
-// processJSONMessage efficiently processes JSON messages
+// processJSONMessage peeks the channel of a message and decodes subscription data
+// directly into the typed value of the channel
 func (ws *WebSocketAPI) processJSONMessage(message []byte) {
-	// Get response object from pool
-	response := ws.responsePool.Get().(*WSResponse)
-	defer ws.responsePool.Put(response)
-
-	// Reset response object
-	*response = WSResponse{}
-
-	if err := PooledUnmarshal(message, response); err != nil {
-		if ws.Debug {
-			log.Printf("Failed to unmarshal WebSocket message: %v", err)
-		}
+	channel, err := peekChannel(message)
+	if err != nil {
+		ws.Logger().Debug("Failed to unmarshal WebSocket message", "err", err)
 		return
 	}
 
 	// Fast path for special messages
-	switch response.Channel {
+	switch channel {
 	case "subscribed", "subscription", "subscriptionResponse":
-		if ws.Debug {
-			log.Printf("Received subscription acknowledgment: %+v", response.Data)
-		}
+		ws.Logger().Debug("Received subscription acknowledgment", "data", rawMessageData(message))
 		return
 
 	case "pong":
@@ -373,360 +551,306 @@ func (ws *WebSocketAPI) processJSONMessage(message []byte) {
 		return
 
 	case "error":
-		if ws.Debug {
-			log.Printf("Received error message: %+v", response.Data)
-		}
+		ws.Logger().Warn("Received WebSocket error message", "data", rawMessageData(message))
 		return
 	}
 
+	if recorder := ws.recorder.Load(); recorder != nil {
+		recorder.record(channel, message)
+	}
+
 	// Process subscription messages with optimized matching
-	ws.processSubscriptionMessage(response)
+	ws.processSubscriptionMessage(channel, message)
 }
 
 // handlePong processes pong messages and updates latency
 func (ws *WebSocketAPI) handlePong() {
 	if lastPingTimeValue := ws.lastPingTime.Load(); lastPingTimeValue != nil {
 		if lastPingTime, ok := lastPingTimeValue.(time.Time); ok && !lastPingTime.IsZero() {
-			latency := time.Since(lastPingTime).Milliseconds()
+			rtt := time.Since(lastPingTime)
+			latency := rtt.Milliseconds()
 			ws.latencyMs.Store(latency)
-			if ws.Debug {
-				log.Printf("Received pong response, latency: %dms", latency)
-			}
+			ws.Telemetry().ObserveRTT(EndpointWSPing, rtt)
+			ws.Logger().Debug("Received pong", "latency", rtt)
 		}
 	}
 }
 
 // handlePostResponse processes post response messages
 func (ws *WebSocketAPI) handlePostResponse(message []byte) {
-	var postResponse WSPostResponse
-	if err := PooledUnmarshal(message, &postResponse); err != nil {
-		if ws.Debug {
-			log.Printf("Failed to unmarshal post response: %v", err)
-		}
+	var raw wsPostResponseRaw
+	if err := PooledUnmarshal(message, &raw); err != nil {
+		ws.Logger().Debug("Failed to unmarshal post response", "err", err)
 		return
 	}
 
+	postResponse := WSPostResponse{
+		Channel: "post",
+		Data: WSPostResponseData{
+			ID: raw.Data.ID,
+			Response: WSPostResponseBody{
+				Type:       raw.Data.Response.Type,
+				RawPayload: raw.Data.Response.Payload,
+			},
+		},
+	}
+	if len(raw.Data.Response.Payload) > 0 {
+		if err := FastUnmarshal(raw.Data.Response.Payload, &postResponse.Data.Response.Payload); err != nil {
+			ws.Logger().Debug("Failed to unmarshal post response payload", "id", raw.Data.ID, "err", err)
+		}
+	}
+
 	ws.mu.RLock()
 	if ch, exists := ws.postResponses[postResponse.Data.ID]; exists {
 		select {
 		case ch <- postResponse.Data:
-			if ws.Debug {
-				log.Printf("Sent post response to channel for ID: %d", postResponse.Data.ID)
-			}
+			ws.Logger().Debug("Received post response", "id", postResponse.Data.ID)
 		default:
-			if ws.Debug {
-				log.Printf("Post response channel full for ID: %d", postResponse.Data.ID)
-			}
+			ws.Logger().Debug("Post response channel full", "id", postResponse.Data.ID)
 		}
 	}
 	ws.mu.RUnlock()
 }
 
-// processSubscriptionMessage efficiently processes subscription messages
-func (ws *WebSocketAPI) processSubscriptionMessage(response *WSResponse) {
+// processSubscriptionMessage decodes a subscription message once and sends it to the matching listeners
+func (ws *WebSocketAPI) processSubscriptionMessage(channel string, message []byte) {
 	ws.mu.RLock()
-	handlers, exists := ws.channelHandlers[response.Channel]
+	handlers, exists := ws.channelHandlers[channel]
+	var subType SubscriptionType
+	if exists && len(handlers) > 0 {
+		subType = handlers[0].Type
+	}
 	ws.mu.RUnlock()
+	if !exists || len(handlers) == 0 {
+		ws.Logger().Debug("No handlers for channel", "channel", channel)
+		return
+	}
 
-	if !exists {
-		if ws.Debug {
-			log.Printf("No handlers found for channel: %s", response.Channel)
-		}
+	received := time.Now()
+	data, err := decodeSubscriptionData(subType, message)
+	if err != nil {
+		ws.Logger().Debug("Failed to decode subscription message", "channel", channel, "err", err)
+		return
+	}
+	if ws.replay {
+		ws.replaySubscriptionMessage(channel, data)
 		return
 	}
+	if exchangeTime, ok := streamMessageTime(data); ok {
+		ws.Telemetry().ObserveStreamTime(channel, exchangeTime, received)
+	}
+	ws.Tracing().traceStreamEvent(data)
+
+	// Listener channels are only closed under the write lock, so sending under the read lock is safe
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
 
 	// Process all handlers for this channel with essential filtering
-	for _, handler := range handlers {
-		if ws.matchesSubscription(handler, response) {
+	for _, handler := range ws.channelHandlers[channel] {
+		if !matchesSubscription(handler, data) {
+			continue
+		}
+		for _, listener := range handler.listeners {
 			select {
-			case handler.Channel <- response.Data:
-				if ws.Debug {
-					log.Printf("Sent data to subscription: %s", response.Channel)
+			case listener.channel <- data:
+				if ws.debugEnabled() {
+					ws.Logger().Debug("Sent data to subscription", "channel", channel)
 				}
 			default:
-				if ws.Debug {
-					log.Printf("Handler channel full, skipping message")
-				}
-			}
-		}
-	}
-}
-
-// matchesSubscription efficiently checks if a message matches a subscription
-func (ws *WebSocketAPI) matchesSubscription(sub *Subscription, response *WSResponse) bool {
-	switch sub.Type {
-	case SubTypeUserFills:
-		return ws.matchUserFills(sub, response)
-	case SubTypeL2Book:
-		return ws.matchL2Book(sub, response)
-	case SubTypeTrades:
-		return ws.matchTrades(sub, response)
-	case SubTypeOrderUpdates:
-		return ws.matchOrderUpdates(sub, response)
-	case SubTypeUserEvents:
-		return ws.matchUserEvents(sub, response)
-	case SubTypeUserFundings:
-		return ws.matchUserFundings(sub, response)
-	case SubTypeUserNonFundingLedgerUpdates:
-		return ws.matchUserNonFundingLedgerUpdates(sub, response)
-	case SubTypeUserTwapSliceFills:
-		return ws.matchUserTwapSliceFills(sub, response)
-	case SubTypeUserTwapHistory:
-		return ws.matchUserTwapHistory(sub, response)
-	case SubTypeNotification:
-		return ws.matchNotification(sub, response)
-	case SubTypeWebData2:
-		return ws.matchWebData2(sub, response)
-	case SubTypeActiveAssetCtx:
-		return ws.matchActiveAssetCtx(sub, response)
-	case SubTypeActiveAssetData:
-		return ws.matchActiveAssetData(sub, response)
-	case SubTypeBbo:
-		return ws.matchBbo(sub, response)
-	case SubTypeCandle:
-		return ws.matchCandle(sub, response)
-	case SubTypeAllMids:
-		return true // Accept all mids
-	default:
-		return false
-	}
-}
-
-// Essential matching functions - optimized for performance
-func (ws *WebSocketAPI) matchUserFills(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User // Direct comparison, no strings.EqualFold
+				ws.Metrics().MessageDropped(channel)
+				ws.Logger().Debug("Handler channel full, dropping message", "channel", channel)
 			}
 		}
 	}
-	return false
 }
 
-func (ws *WebSocketAPI) matchTrades(sub *Subscription, response *WSResponse) bool {
-	// For trades, we need to check if any trade in the array matches the coin
-	if tradesArray, ok := response.Data.([]interface{}); ok {
-		for _, trade := range tradesArray {
-			if tradeMap, ok := trade.(map[string]interface{}); ok {
-				if coin, exists := tradeMap["coin"]; exists {
-					if coinStr, ok := coin.(string); ok {
-						if coinStr == sub.Coin {
-							return true
-						}
-					}
-				}
-			}
+// replaySubscriptionMessage calls the handlers of the matching listeners in order on the calling
+// goroutine, so replayed messages are neither dropped nor reordered across channels
+func (ws *WebSocketAPI) replaySubscriptionMessage(channel string, data interface{}) {
+	var handlers []SubscriptionHandler
+	ws.mu.RLock()
+	for _, sub := range ws.channelHandlers[channel] {
+		if !matchesSubscription(sub, data) {
+			continue
 		}
-	}
-	return false
-}
-
-func (ws *WebSocketAPI) matchL2Book(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if coin, exists := dataMap["coin"]; exists {
-			if coinStr, ok := coin.(string); ok {
-				return coinStr == sub.Coin
-			}
+		for _, listener := range sub.listeners {
+			handlers = append(handlers, listener.handler)
 		}
 	}
-	return false
-}
+	ws.mu.RUnlock()
 
-func (ws *WebSocketAPI) matchActiveAssetCtx(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if coin, exists := dataMap["coin"]; exists {
-			if coinStr, ok := coin.(string); ok {
-				return coinStr == sub.Coin
-			}
-		}
+	// Handlers may subscribe or close their handle, so they run without the lock
+	for _, handler := range handlers {
+		handler(data)
 	}
-	return false
 }
 
-func (ws *WebSocketAPI) matchActiveAssetData(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				if userStr != sub.User {
-					return false
-				}
-			}
-		}
-		if coin, exists := dataMap["coin"]; exists {
-			if coinStr, ok := coin.(string); ok {
-				return coinStr == sub.Coin
-			}
-		}
+// addSubscription adds a listener to the subscription of channel.
+// The upstream subscription is only sent for the first listener of a channel.
+func (ws *WebSocketAPI) addSubscription(channel string, subType SubscriptionType, handler SubscriptionHandler, params map[string]string) (*SubscriptionHandle, error) {
+	// Create listener with appropriate buffer size
+	handle := &SubscriptionHandle{
+		ws:      ws,
+		handler: handler,
+		channel: make(chan interface{}, listenerBufferSize(subType)),
+		done:    make(chan struct{}),
 	}
-	return false
-}
 
-func (ws *WebSocketAPI) matchOrderUpdates(sub *Subscription, response *WSResponse) bool {
-	return response.Channel == "orderUpdates"
-}
+	// Held until the upstream subscription was sent, so listeners joining meanwhile
+	// wait for its outcome and a concurrent unsubscribe of the channel goes out first
+	ws.upstreamMu.Lock()
+	defer ws.upstreamMu.Unlock()
 
-func (ws *WebSocketAPI) matchUserEvents(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User
-			}
+	ws.mu.Lock()
+	sub, exists := ws.subscriptions[channel]
+	if !exists {
+		sub = &Subscription{
+			Key:      channel,
+			Type:     subType,
+			Params:   params,
+			User:     params["user"],
+			Coin:     params["coin"],
+			Interval: params["interval"],
 		}
-	}
-	return false
-}
+		ws.subscriptions[channel] = sub
 
-func (ws *WebSocketAPI) matchUserFundings(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User
-			}
-		}
+		// Add to channel handlers for fast lookup
+		channelName := getChannelName(subType)
+		ws.channelHandlers[channelName] = append(ws.channelHandlers[channelName], sub)
 	}
-	return false
-}
+	handle.sub = sub
+	sub.listeners = append(sub.listeners, handle)
+	ws.mu.Unlock()
 
-func (ws *WebSocketAPI) matchUserNonFundingLedgerUpdates(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User
-			}
+	// Start handler goroutine
+	go func() {
+		defer close(handle.done)
+		for data := range handle.channel {
+			handler(data)
 		}
-	}
-	return false
-}
+	}()
 
-func (ws *WebSocketAPI) matchUserTwapSliceFills(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User
-			}
-		}
+	if exists {
+		return handle, nil
+	}
+	if err := ws.subscribe(getChannelName(subType), sub.upstreamParams()); err != nil {
+		ws.mu.Lock()
+		ws.removeListenerLocked(handle)
+		ws.mu.Unlock()
+		return nil, err
 	}
-	return false
+	return handle, nil
 }
 
-func (ws *WebSocketAPI) matchUserTwapHistory(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User
-			}
-		}
+// listenerBufferSize returns the buffer size of a listener for the expected message rate
+func listenerBufferSize(subType SubscriptionType) int {
+	switch subType {
+	case SubTypeL2Book, SubTypeTrades:
+		return 100 // High frequency
+	case SubTypeUserFills, SubTypeOrderUpdates:
+		return 50 // Medium frequency
+	default:
+		return 10 // Low frequency
 	}
-	return false
 }
 
-func (ws *WebSocketAPI) matchNotification(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User
-			}
-		}
+// upstreamParams returns the parameters of the upstream subscription message
+func (sub *Subscription) upstreamParams() map[string]interface{} {
+	params := make(map[string]interface{}, len(sub.Params))
+	for k, v := range sub.Params {
+		params[k] = v
 	}
-	return false
+	return params
 }
 
-func (ws *WebSocketAPI) matchWebData2(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if user, exists := dataMap["user"]; exists {
-			if userStr, ok := user.(string); ok {
-				return userStr == sub.User
-			}
-		}
+// removeListenerLocked removes a listener and stops its handler goroutine.
+// It returns true if the listener was the last one of its subscription, which is then removed as well.
+// The caller must hold ws.mu.
+func (ws *WebSocketAPI) removeListenerLocked(handle *SubscriptionHandle) bool {
+	if handle.closed {
+		return false
 	}
-	return false
-}
+	handle.closed = true
+	close(handle.channel)
 
-func (ws *WebSocketAPI) matchBbo(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if coin, exists := dataMap["coin"]; exists {
-			if coinStr, ok := coin.(string); ok {
-				return coinStr == sub.Coin
-			}
+	sub := handle.sub
+	for i, listener := range sub.listeners {
+		if listener == handle {
+			sub.listeners = append(sub.listeners[:i], sub.listeners[i+1:]...)
+			break
 		}
 	}
-	return false
+	if len(sub.listeners) > 0 || ws.subscriptions[sub.Key] != sub {
+		return false
+	}
+	ws.deleteSubscriptionLocked(sub)
+	return true
 }
 
-func (ws *WebSocketAPI) matchCandle(sub *Subscription, response *WSResponse) bool {
-	if dataMap, ok := response.Data.(map[string]interface{}); ok {
-		if coin, exists := dataMap["s"]; exists {
-			if coinStr, ok := coin.(string); ok {
-				if coinStr != sub.Coin {
-					return false
-				}
-			}
-		}
-		if interval, exists := dataMap["i"]; exists {
-			if intervalStr, ok := interval.(string); ok {
-				return intervalStr == sub.Interval
+// deleteSubscriptionLocked removes a subscription from the internal storage.
+// The caller must hold ws.mu.
+func (ws *WebSocketAPI) deleteSubscriptionLocked(sub *Subscription) {
+	delete(ws.subscriptions, sub.Key)
+
+	channelName := getChannelName(sub.Type)
+	if handlers, exists := ws.channelHandlers[channelName]; exists {
+		for i, handler := range handlers {
+			if handler == sub {
+				// Copy to keep slices handed out to readers intact
+				remaining := make([]*Subscription, 0, len(handlers)-1)
+				remaining = append(remaining, handlers[:i]...)
+				ws.channelHandlers[channelName] = append(remaining, handlers[i+1:]...)
+				break
 			}
 		}
 	}
-	return false
 }
 
-// addSubscription adds a subscription with optimized storage
-func (ws *WebSocketAPI) addSubscription(channel string, subType SubscriptionType, handler SubscriptionHandler, params map[string]string) error {
-	// Check for existing subscription for user-specific channels
-	user := params["user"]
-	if user != "" {
-		// For user-specific channels, check if subscription already exists for this user
-		channelName := getChannelName(subType)
-		ws.mu.RLock()
-		for _, existingSub := range ws.channelHandlers[channelName] {
-			if existingSub.User == user {
-				ws.mu.RUnlock()
-				// Subscription already exists for this user, return without error
-				return nil
-			}
-		}
-		ws.mu.RUnlock()
-	}
+// SubscriptionHandle is a single listener of a WebSocket subscription.
+// Several listeners can share one channel, each receives every message on its own goroutine.
+type SubscriptionHandle struct {
+	ws      *WebSocketAPI
+	handler SubscriptionHandler
+	sub     *Subscription
+	channel chan interface{}
+	done    chan struct{} // Closed once the handler goroutine has stopped
+	closed  bool          // Guarded by ws.mu
+	once    sync.Once
+}
 
-	// Create subscription with appropriate buffer size
-	var bufferSize int
-	switch subType {
-	case SubTypeL2Book, SubTypeTrades:
-		bufferSize = 100 // High frequency
-	case SubTypeUserFills, SubTypeOrderUpdates:
-		bufferSize = 50 // Medium frequency
-	default:
-		bufferSize = 10 // Low frequency
-	}
+// Channel returns the key of the subscribed channel, e.g. "l2Book:BTC"
+func (h *SubscriptionHandle) Channel() string {
+	return h.sub.Key
+}
 
-	sub := &Subscription{
-		Type:     subType,
-		Params:   params,
-		Handler:  handler,
-		Channel:  make(chan interface{}, bufferSize),
-		User:     params["user"],
-		Coin:     params["coin"],
-		Interval: params["interval"],
-	}
+// Done returns a channel that is closed when the listener has stopped, either because
+// it was closed, its channel was unsubscribed or the WebSocket was disconnected
+func (h *SubscriptionHandle) Done() <-chan struct{} {
+	return h.done
+}
 
-	ws.mu.Lock()
-	ws.subscriptions[channel] = sub
+// Close removes the listener. The upstream unsubscribe is only sent when the last listener
+// of the channel goes away. Closing a handle more than once is a no-op.
+func (h *SubscriptionHandle) Close() error {
+	var err error
+	h.once.Do(func() {
+		ws := h.ws
+		ws.upstreamMu.Lock()
+		defer ws.upstreamMu.Unlock()
 
-	// Add to channel handlers for fast lookup
-	channelName := getChannelName(subType)
-	ws.channelHandlers[channelName] = append(ws.channelHandlers[channelName], sub)
-	ws.mu.Unlock()
+		ws.mu.Lock()
+		last := ws.removeListenerLocked(h)
+		connected := ws.isConnected.Load() && ws.conn != nil
+		ws.mu.Unlock()
 
-	// Start handler goroutine
-	go func() {
-		for data := range sub.Channel {
-			handler(data)
+		if last && connected {
+			err = ws.unsubscribe(getChannelName(h.sub.Type), h.sub.upstreamParams())
 		}
-	}()
-
-	return nil
+		if last {
+			ws.Logger().Debug("Removed subscription", "channel", h.sub.Key)
+		}
+	})
+	return err
 }
 
 // getChannelName returns the channel name for a subscription type
@@ -769,8 +893,8 @@ func getChannelName(subType SubscriptionType) string {
 	}
 }
 
-// pingHandler sends periodic ping messages to keep the connection alive
-func (ws *WebSocketAPI) pingHandler() {
+// pingHandler sends periodic ping messages to keep the connection alive until stop is closed
+func (ws *WebSocketAPI) pingHandler(stop <-chan struct{}) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
@@ -783,9 +907,7 @@ func (ws *WebSocketAPI) pingHandler() {
 					if lastPingTime, ok := lastPingTimeValue.(time.Time); ok && !lastPingTime.IsZero() {
 						// If no pong received within 45 seconds, trigger reconnection
 						if time.Since(lastPingTime) > 45*time.Second {
-							if ws.Debug {
-								log.Printf("Pong timeout detected, triggering reconnection")
-							}
+							ws.Logger().Warn("Pong timeout, reconnecting")
 							if !ws.manualDisconnect {
 								go ws.reconnect()
 							}
@@ -796,16 +918,17 @@ func (ws *WebSocketAPI) pingHandler() {
 
 				// Use pre-marshaled ping message for efficiency
 				ws.mu.Lock()
+				if ws.conn == nil {
+					ws.mu.Unlock()
+					return
+				}
 				err := ws.conn.WriteMessage(websocket.TextMessage, ws.pingMessageBytes)
 				ws.mu.Unlock()
 
 				if err != nil {
-					if ws.Debug {
-						log.Printf("Ping failed: %v", err)
-					}
 					// Trigger reconnection on ping failure
 					if !ws.manualDisconnect {
-						log.Printf("Ping failed, triggering reconnection")
+						ws.Logger().Warn("Ping failed, reconnecting", "err", err)
 						go ws.reconnect()
 					}
 					return
@@ -814,14 +937,10 @@ func (ws *WebSocketAPI) pingHandler() {
 				// Record the timestamp when ping was sent
 				ws.lastPingTime.Store(time.Now())
 
-				if ws.Debug {
-					log.Printf("Ping sent successfully")
-				}
-			}
-		case <-ws.pingStopChan:
-			if ws.Debug {
-				log.Printf("Ping handler stopped")
+				ws.Logger().Debug("Ping sent")
 			}
+		case <-stop:
+			ws.Logger().Debug("Ping handler stopped")
 			return
 		}
 	}
@@ -849,28 +968,16 @@ func (ws *WebSocketAPI) reconnect() {
 
 	time.Sleep(backoff)
 
-	// Store active subscriptions before reconnecting
+	// Store active subscriptions before reconnecting.
+	// Their listeners stay registered, only the upstream subscriptions have to be renewed.
 	ws.mu.RLock()
 	activeSubs := make([]*Subscription, 0, len(ws.subscriptions))
 	for _, sub := range ws.subscriptions {
-		// Create a copy of the subscription to avoid race conditions
-		subCopy := &Subscription{
-			Type:     sub.Type,
-			Params:   make(map[string]string),
-			Handler:  sub.Handler,
-			User:     sub.User,
-			Coin:     sub.Coin,
-			Interval: sub.Interval,
-		}
-		// Copy params
-		for k, v := range sub.Params {
-			subCopy.Params[k] = v
-		}
-		activeSubs = append(activeSubs, subCopy)
+		activeSubs = append(activeSubs, sub)
 	}
 	ws.mu.RUnlock()
 
-	log.Printf("Storing %d active subscriptions for resubscription", len(activeSubs))
+	ws.Logger().Info("Reconnecting WebSocket", "attempt", ws.reconnectCount, "subscriptions", len(activeSubs))
 
 	// Close the old connection first to stop existing goroutines
 	ws.mu.Lock()
@@ -878,7 +985,10 @@ func (ws *WebSocketAPI) reconnect() {
 		ws.conn.Close()
 		ws.conn = nil
 	}
-	ws.isConnected = false
+	ws.isConnected.Store(false)
+	ws.Metrics().SetWSConnected(false)
+	// The reader of the old connection no longer owns its posts
+	ws.failPendingPostsLocked()
 
 	// Stop ping handler
 	if ws.pingStopChan != nil {
@@ -890,80 +1000,54 @@ func (ws *WebSocketAPI) reconnect() {
 	// Wait longer for goroutines to properly terminate
 	time.Sleep(2 * time.Second)
 
-	if err := ws.Connect(); err != nil {
-		log.Printf("Reconnection failed: %v", err)
+	err := ws.Connect()
+	ws.Metrics().WSReconnect(err)
+	if err != nil {
+		ws.Logger().Error("WebSocket reconnection failed", "attempt", ws.reconnectCount, "err", err)
 		return
 	}
 
-	log.Println("Reconnected successfully")
+	ws.Logger().Info("WebSocket reconnected")
 
 	// Wait a moment for connection to stabilize
 	time.Sleep(100 * time.Millisecond)
 
-	// Resubscribe to all active subscriptions with retry logic
+	// Resubscribe to all active subscriptions
 	successCount := 0
 
 	for _, sub := range activeSubs {
-		// Reconstruct the original subscription parameters
-		params := make(map[string]interface{})
-
-		// Add parameters based on subscription type
-		if sub.User != "" {
-			params["user"] = sub.User
-		}
-		if sub.Coin != "" {
-			params["coin"] = sub.Coin
-		}
-		if sub.Interval != "" {
-			params["interval"] = sub.Interval
-		}
-
 		// Get the subscription type name
 		subTypeName := getChannelName(sub.Type)
 		if subTypeName == "unknown" {
-			log.Printf("Unknown subscription type during resubscribe: %v", sub.Type)
+			ws.Logger().Warn("Unknown subscription type during resubscribe", "channel", sub.Key)
 			continue
 		}
 
-		// Generate the channel key to check if already subscribed
-		var channelKey string
-		switch sub.Type {
-		case SubTypeTrades, SubTypeL2Book, SubTypeBbo, SubTypeActiveAssetCtx:
-			channelKey = fmt.Sprintf("%s:%s", subTypeName, sub.Coin)
-		case SubTypeUserFills, SubTypeUserEvents, SubTypeUserFundings, SubTypeUserNonFundingLedgerUpdates,
-			SubTypeUserTwapSliceFills, SubTypeUserTwapHistory, SubTypeOrderUpdates, SubTypeNotification, SubTypeWebData2:
-			channelKey = fmt.Sprintf("%s:%s", subTypeName, sub.User)
-		case SubTypeActiveAssetData:
-			channelKey = fmt.Sprintf("%s:%s:%s", subTypeName, sub.User, sub.Coin)
-		case SubTypeCandle:
-			channelKey = fmt.Sprintf("%s:%s:%s", subTypeName, sub.Coin, sub.Interval)
-		case SubTypeAllMids:
-			channelKey = subTypeName
-		default:
-			channelKey = fmt.Sprintf("%s:%s", subTypeName, sub.Coin)
-		}
-
-		// Check if already subscribed to avoid duplicates
-		ws.mu.RLock()
-		if _, exists := ws.subscriptions[channelKey]; exists {
-			ws.mu.RUnlock()
-			log.Printf("Already subscribed to %s, skipping", channelKey)
+		if sent, err := ws.resubscribe(sub); err != nil {
+			ws.Metrics().ResubscribeFailed(subTypeName)
+			ws.Logger().Warn("Failed to resubscribe", "channel", sub.Key, "err", err)
+		} else if sent {
 			successCount++
-			continue
-		}
-		ws.mu.RUnlock()
-
-		// Try to resubscribe (single attempt since reconnect has backoff)
-		if err := ws.subscribe(subTypeName, params); err == nil {
-			// Restore the handler function
-			ws.addSubscription(channelKey, sub.Type, sub.Handler, sub.Params)
-			successCount++
-		} else {
-			log.Printf("Failed to resubscribe %s: %v", subTypeName, err)
 		}
 	}
 
-	log.Printf("Resubscribed to %d/%d active subscriptions successfully", successCount, len(activeSubs))
+	ws.Logger().Info("Resubscribed after reconnect", "resubscribed", successCount, "subscriptions", len(activeSubs))
+}
+
+// resubscribe renews the upstream subscription of sub. It returns false if the last listener
+// of sub left while reconnecting.
+func (ws *WebSocketAPI) resubscribe(sub *Subscription) (bool, error) {
+	ws.upstreamMu.Lock()
+	defer ws.upstreamMu.Unlock()
+
+	ws.mu.RLock()
+	current := ws.subscriptions[sub.Key]
+	ws.mu.RUnlock()
+	if current != sub {
+		return false, nil
+	}
+	// Single attempt since reconnect has backoff
+	return true, ws.subscribe(getChannelName(sub.Type), sub.upstreamParams())
 }
 
 // subscribe sends a subscription request to the WebSocket server
@@ -983,6 +1067,10 @@ func (ws *WebSocketAPI) subscribe(subType string, params map[string]interface{})
 		}
 	}
 
+	if ws.replay {
+		return nil
+	}
+
 	msg = map[string]interface{}{
 		"method":       "subscribe",
 		"subscription": subscription,
@@ -993,11 +1081,13 @@ func (ws *WebSocketAPI) subscribe(subType string, params map[string]interface{})
 		return err
 	}
 
-	if ws.Debug {
-		log.Printf("Sending subscription message (format 1): %s", string(b))
-	}
+	ws.Logger().Debug("Sending subscription message", "message", b)
 
 	ws.mu.Lock()
+	if ws.conn == nil {
+		ws.mu.Unlock()
+		return errors.New("websocket not connected")
+	}
 	err = ws.conn.WriteMessage(websocket.TextMessage, b)
 	ws.mu.Unlock()
 
@@ -1005,9 +1095,7 @@ func (ws *WebSocketAPI) subscribe(subType string, params map[string]interface{})
 		return err
 	}
 
-	if ws.Debug {
-		log.Printf("Subscription message sent successfully for type: %s", subType)
-	}
+	ws.Logger().Debug("Subscription message sent", "type", subType)
 
 	return nil
 }
@@ -1039,11 +1127,13 @@ func (ws *WebSocketAPI) unsubscribe(subType string, params map[string]interface{
 		return err
 	}
 
-	if ws.Debug {
-		log.Printf("Sending unsubscribe message: %s", string(b))
-	}
+	ws.Logger().Debug("Sending unsubscribe message", "message", b)
 
 	ws.mu.Lock()
+	if ws.conn == nil {
+		ws.mu.Unlock()
+		return errors.New("websocket not connected")
+	}
 	err = ws.conn.WriteMessage(websocket.TextMessage, b)
 	ws.mu.Unlock()
 
@@ -1051,324 +1141,218 @@ func (ws *WebSocketAPI) unsubscribe(subType string, params map[string]interface{
 		return err
 	}
 
-	if ws.Debug {
-		log.Printf("Unsubscribe message sent successfully for type: %s", subType)
-	}
+	ws.Logger().Debug("Unsubscribe message sent", "type", subType)
 
 	return nil
 }
 
-// removeSubscription removes a subscription from the internal storage
-func (ws *WebSocketAPI) removeSubscription(channel string) error {
-	ws.mu.Lock()
-	defer ws.mu.Unlock()
+// removeSubscription removes a subscription and all of its listeners from the internal storage
+func (ws *WebSocketAPI) removeSubscription(channel string, subType string, params map[string]interface{}) error {
+	ws.upstreamMu.Lock()
+	defer ws.upstreamMu.Unlock()
 
-	// Remove from subscriptions map
+	ws.mu.Lock()
 	if sub, exists := ws.subscriptions[channel]; exists {
-		// Close the channel
-		close(sub.Channel)
-		delete(ws.subscriptions, channel)
-
-		// Remove from channelHandlers
-		channelName := getChannelName(sub.Type)
-		if handlers, exists := ws.channelHandlers[channelName]; exists {
-			for i, handler := range handlers {
-				if handler == sub {
-					// Remove from slice
-					ws.channelHandlers[channelName] = append(handlers[:i], handlers[i+1:]...)
-					break
-				}
-			}
+		for _, listener := range append([]*SubscriptionHandle(nil), sub.listeners...) {
+			ws.removeListenerLocked(listener)
 		}
-
-		if ws.Debug {
-			log.Printf("Removed subscription for channel: %s", channel)
+		// Listeners that were already closed leave nothing to remove
+		if ws.subscriptions[channel] == sub {
+			ws.deleteSubscriptionLocked(sub)
 		}
+
+		ws.Logger().Debug("Removed subscription", "channel", channel)
 	}
+	ws.mu.Unlock()
 
-	return nil
+	return ws.unsubscribe(subType, params)
 }
 
 // SubscribeOrderbook subscribes to orderbook updates for a specific coin
-func (ws *WebSocketAPI) SubscribeOrderbook(coin string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeOrderbook(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("l2Book:%s", coin)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeL2Book, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeL2Book, handler, map[string]string{
 		"coin": coin,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("l2Book", map[string]interface{}{"coin": coin})
 }
 
 // SubscribeTrades subscribes to trade updates for a specific coin
-func (ws *WebSocketAPI) SubscribeTrades(coin string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeTrades(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("trades:%s", coin)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeTrades, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeTrades, handler, map[string]string{
 		"coin": coin,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("trades", map[string]interface{}{"coin": coin})
 }
 
 // SubscribeUserFills subscribes to user fill updates
-func (ws *WebSocketAPI) SubscribeUserFills(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeUserFills(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("userFills:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeUserFills, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeUserFills, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	// Format the subscription message correctly
-	msg := map[string]interface{}{
-		"method": "subscribe",
-		"subscription": map[string]interface{}{
-			"type": "userFills",
-			"user": user,
-		},
-	}
-
-	b, err := FastMarshal(msg)
-	if err != nil {
-		return err
-	}
-
-	ws.mu.Lock()
-	err = ws.conn.WriteMessage(websocket.TextMessage, b)
-	ws.mu.Unlock()
-
-	return err
 }
 
 // SubscribeAllMids subscribes to all mid price updates
-func (ws *WebSocketAPI) SubscribeAllMids(handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeAllMids(handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := "allMids"
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeAllMids, handler, map[string]string{})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("allMids", nil)
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeAllMids, handler, map[string]string{})
 }
 
 // SubscribeUserEvents subscribes to user events for a specific user
-func (ws *WebSocketAPI) SubscribeUserEvents(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeUserEvents(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("userEvents:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeUserEvents, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeUserEvents, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("userEvents", map[string]interface{}{"user": user})
 }
 
 // SubscribeUserFundings subscribes to user fundings for a specific user
-func (ws *WebSocketAPI) SubscribeUserFundings(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeUserFundings(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("userFundings:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeUserFundings, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeUserFundings, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("userFundings", map[string]interface{}{"user": user})
 }
 
 // SubscribeUserNonFundingLedgerUpdates subscribes to user non-funding ledger updates for a specific user
-func (ws *WebSocketAPI) SubscribeUserNonFundingLedgerUpdates(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeUserNonFundingLedgerUpdates(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("userNonFundingLedgerUpdates:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeUserNonFundingLedgerUpdates, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeUserNonFundingLedgerUpdates, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("userNonFundingLedgerUpdates", map[string]interface{}{"user": user})
 }
 
 // SubscribeUserTwapSliceFills subscribes to user TWAP slice fills for a specific user
-func (ws *WebSocketAPI) SubscribeUserTwapSliceFills(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeUserTwapSliceFills(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("userTwapSliceFills:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeUserTwapSliceFills, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeUserTwapSliceFills, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("userTwapSliceFills", map[string]interface{}{"user": user})
 }
 
 // SubscribeUserTwapHistory subscribes to user TWAP history for a specific user
-func (ws *WebSocketAPI) SubscribeUserTwapHistory(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeUserTwapHistory(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("userTwapHistory:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeUserTwapHistory, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeUserTwapHistory, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("userTwapHistory", map[string]interface{}{"user": user})
 }
 
 // SubscribeActiveAssetCtx subscribes to active asset context for a specific coin
-func (ws *WebSocketAPI) SubscribeActiveAssetCtx(coin string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeActiveAssetCtx(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("activeAssetCtx:%s", coin)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeActiveAssetCtx, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeActiveAssetCtx, handler, map[string]string{
 		"coin": coin,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("activeAssetCtx", map[string]interface{}{"coin": coin})
 }
 
 // SubscribeActiveAssetData subscribes to active asset data for a specific user and coin
-func (ws *WebSocketAPI) SubscribeActiveAssetData(user string, coin string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeActiveAssetData(user string, coin string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("activeAssetData:%s:%s", user, coin)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeActiveAssetData, handler, map[string]string{
-		"user": user,
-		"coin": coin,
-	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("activeAssetData", map[string]interface{}{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeActiveAssetData, handler, map[string]string{
 		"user": user,
 		"coin": coin,
 	})
 }
 
 // SubscribeBbo subscribes to best bid/offer updates for a specific coin
-func (ws *WebSocketAPI) SubscribeBbo(coin string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeBbo(coin string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("bbo:%s", coin)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeBbo, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeBbo, handler, map[string]string{
 		"coin": coin,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("bbo", map[string]interface{}{"coin": coin})
 }
 
 // SubscribeCandle subscribes to candle updates for a specific coin and interval
-func (ws *WebSocketAPI) SubscribeCandle(coin string, interval string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeCandle(coin string, interval string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("candle:%s:%s", coin, interval)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeCandle, handler, map[string]string{
-		"coin":     coin,
-		"interval": interval,
-	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("candle", map[string]interface{}{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeCandle, handler, map[string]string{
 		"coin":     coin,
 		"interval": interval,
 	})
 }
 
 // SubscribeOrderUpdates subscribes to order updates for a specific user
-func (ws *WebSocketAPI) SubscribeOrderUpdates(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeOrderUpdates(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("orderUpdates:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeOrderUpdates, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeOrderUpdates, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("orderUpdates", map[string]interface{}{"user": user})
 }
 
 // SubscribeNotification subscribes to notifications for a specific user
-func (ws *WebSocketAPI) SubscribeNotification(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeNotification(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("notification:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeNotification, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeNotification, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("notification", map[string]interface{}{"user": user})
 }
 
 // SubscribeWebData2 subscribes to web data for a specific user
-func (ws *WebSocketAPI) SubscribeWebData2(user string, handler SubscriptionHandler) error {
+func (ws *WebSocketAPI) SubscribeWebData2(user string, handler SubscriptionHandler) (*SubscriptionHandle, error) {
 	channel := fmt.Sprintf("webData2:%s", user)
 
-	// Add subscription with optimized storage
-	err := ws.addSubscription(channel, SubTypeWebData2, handler, map[string]string{
+	// Add listener, the first listener of a channel subscribes upstream
+	return ws.addSubscription(channel, SubTypeWebData2, handler, map[string]string{
 		"user": user,
 	})
-	if err != nil {
-		return err
-	}
-
-	return ws.subscribe("webData2", map[string]interface{}{"user": user})
 }
 
-// PostRequest sends a post request through WebSocket and returns the response
+// PostRequest sends a post request through WebSocket and returns the response.
+// It waits for the response up to the default post timeout (see SetPostTimeout).
 func (ws *WebSocketAPI) PostRequest(requestType string, payload interface{}) (*WSPostResponseData, error) {
-	postID := int(ws.nextPostID.Add(1))
+	return ws.postRequest(context.Background(), requestType, payload, ws.PostTimeout())
+}
 
-	// Create response channel
-	responseCh := make(chan WSPostResponseData, 1)
-	ws.mu.Lock()
-	ws.postResponses[postID] = responseCh
-	ws.mu.Unlock()
+// PostRequestContext is PostRequest that stops waiting for the response once ctx is done.
+// The request may still be executed then, like after a timeout.
+func (ws *WebSocketAPI) PostRequestContext(ctx context.Context, requestType string, payload interface{}) (*WSPostResponseData, error) {
+	return ws.postRequest(ctx, requestType, payload, ws.PostTimeout())
+}
 
-	// Clean up response channel after use
-	defer func() {
-		ws.mu.Lock()
-		delete(ws.postResponses, postID)
-		ws.mu.Unlock()
-	}()
+// PostRequestWithTimeout sends a post request through WebSocket and waits up to timeout for the response.
+// ErrPostNotSent is returned if the request never left the client, ErrPostTimeout if the response
+// did not arrive in time and ErrPostConnectionClosed if the connection dropped while waiting.
+func (ws *WebSocketAPI) PostRequestWithTimeout(requestType string, payload interface{}, timeout time.Duration) (*WSPostResponseData, error) {
+	return ws.postRequest(context.Background(), requestType, payload, timeout)
+}
+
+// postRequest sends a post request and waits for the response until timeout or ctx is done
+func (ws *WebSocketAPI) postRequest(ctx context.Context, requestType string, payload interface{}, timeout time.Duration) (*WSPostResponseData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPostNotSent, err)
+	}
+	postID := int(ws.nextPostID.Add(1))
 
 	// Create post request
 	postRequest := WSPostRequest{
@@ -1380,30 +1364,56 @@ func (ws *WebSocketAPI) PostRequest(requestType string, payload interface{}) (*W
 		},
 	}
 
-	// Marshal and send request
+	// Marshal request
 	b, err := FastMarshal(postRequest)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal post request: %w", err)
+		return nil, fmt.Errorf("%w: failed to marshal post request: %w", ErrPostNotSent, err)
 	}
 
-	if ws.Debug {
-		log.Printf("Sending post request: %s", string(b))
-	}
+	ws.Logger().Debug("Sending post request", "type", requestType, "id", postID, "payload", b)
 
+	// Create response channel and send request
+	responseCh := make(chan WSPostResponseData, 1)
 	ws.mu.Lock()
+	if ws.conn == nil || !ws.isConnected.Load() {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("%w: websocket not connected", ErrPostNotSent)
+	}
+	ws.postResponses[postID] = responseCh
+	start := time.Now()
 	err = ws.conn.WriteMessage(websocket.TextMessage, b)
+	if err != nil {
+		delete(ws.postResponses, postID)
+	}
 	ws.mu.Unlock()
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to send post request: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrPostNotSent, err)
 	}
 
+	// Clean up response channel after use
+	defer func() {
+		ws.mu.Lock()
+		delete(ws.postResponses, postID)
+		ws.mu.Unlock()
+	}()
+
 	// Wait for response with timeout
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
 	select {
-	case response := <-responseCh:
+	case response, ok := <-responseCh:
+		if !ok {
+			return nil, ErrPostConnectionClosed
+		}
+		ws.Telemetry().ObserveRTT("ws:"+requestType, time.Since(start))
 		return &response, nil
-	case <-time.After(30 * time.Second):
-		return nil, fmt.Errorf("post request timeout")
+	case <-timer.C:
+		ws.Metrics().PostTimeout(requestType)
+		return nil, fmt.Errorf("%w after %s (id %d)", ErrPostTimeout, timeout, postID)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("post request canceled (id %d): %w", postID, ctx.Err())
 	}
 }
 
@@ -1435,190 +1445,126 @@ func (ws *WebSocketAPI) PostOrderRequest(action interface{}, nonce uint64, signa
 func (ws *WebSocketAPI) UnsubscribeOrderbook(coin string) error {
 	channel := fmt.Sprintf("l2Book:%s", coin)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("l2Book", map[string]interface{}{"coin": coin})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "l2Book", map[string]interface{}{"coin": coin})
 }
 
 // UnsubscribeTrades unsubscribes from trade updates for a specific coin
 func (ws *WebSocketAPI) UnsubscribeTrades(coin string) error {
 	channel := fmt.Sprintf("trades:%s", coin)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("trades", map[string]interface{}{"coin": coin})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "trades", map[string]interface{}{"coin": coin})
 }
 
 // UnsubscribeUserFills unsubscribes from user fill updates
 func (ws *WebSocketAPI) UnsubscribeUserFills(user string) error {
 	channel := fmt.Sprintf("userFills:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("userFills", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "userFills", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeAllMids unsubscribes from all mids updates
 func (ws *WebSocketAPI) UnsubscribeAllMids() error {
 	channel := "allMids"
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("allMids", nil)
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "allMids", nil)
 }
 
 // UnsubscribeUserEvents unsubscribes from user events
 func (ws *WebSocketAPI) UnsubscribeUserEvents(user string) error {
 	channel := fmt.Sprintf("userEvents:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("userEvents", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "userEvents", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeUserFundings unsubscribes from user fundings
 func (ws *WebSocketAPI) UnsubscribeUserFundings(user string) error {
 	channel := fmt.Sprintf("userFundings:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("userFundings", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "userFundings", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeUserNonFundingLedgerUpdates unsubscribes from user non-funding ledger updates
 func (ws *WebSocketAPI) UnsubscribeUserNonFundingLedgerUpdates(user string) error {
 	channel := fmt.Sprintf("userNonFundingLedgerUpdates:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("userNonFundingLedgerUpdates", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "userNonFundingLedgerUpdates", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeUserTwapSliceFills unsubscribes from user TWAP slice fills
 func (ws *WebSocketAPI) UnsubscribeUserTwapSliceFills(user string) error {
 	channel := fmt.Sprintf("userTwapSliceFills:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("userTwapSliceFills", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "userTwapSliceFills", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeUserTwapHistory unsubscribes from user TWAP history
 func (ws *WebSocketAPI) UnsubscribeUserTwapHistory(user string) error {
 	channel := fmt.Sprintf("userTwapHistory:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("userTwapHistory", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "userTwapHistory", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeActiveAssetCtx unsubscribes from active asset context
 func (ws *WebSocketAPI) UnsubscribeActiveAssetCtx(coin string) error {
 	channel := fmt.Sprintf("activeAssetCtx:%s", coin)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("activeAssetCtx", map[string]interface{}{"coin": coin})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "activeAssetCtx", map[string]interface{}{"coin": coin})
 }
 
 // UnsubscribeActiveAssetData unsubscribes from active asset data
 func (ws *WebSocketAPI) UnsubscribeActiveAssetData(user string, coin string) error {
 	channel := fmt.Sprintf("activeAssetData:%s:%s", user, coin)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("activeAssetData", map[string]interface{}{"user": user, "coin": coin})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "activeAssetData", map[string]interface{}{"user": user, "coin": coin})
 }
 
 // UnsubscribeBbo unsubscribes from best bid/offer updates
 func (ws *WebSocketAPI) UnsubscribeBbo(coin string) error {
 	channel := fmt.Sprintf("bbo:%s", coin)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("bbo", map[string]interface{}{"coin": coin})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "bbo", map[string]interface{}{"coin": coin})
 }
 
 // UnsubscribeCandle unsubscribes from candle updates
 func (ws *WebSocketAPI) UnsubscribeCandle(coin string, interval string) error {
 	channel := fmt.Sprintf("candle:%s:%s", coin, interval)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("candle", map[string]interface{}{"coin": coin, "interval": interval})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "candle", map[string]interface{}{"coin": coin, "interval": interval})
 }
 
 // UnsubscribeOrderUpdates unsubscribes from order updates
 func (ws *WebSocketAPI) UnsubscribeOrderUpdates(user string) error {
 	channel := fmt.Sprintf("orderUpdates:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("orderUpdates", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "orderUpdates", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeNotification unsubscribes from notifications
 func (ws *WebSocketAPI) UnsubscribeNotification(user string) error {
 	channel := fmt.Sprintf("notification:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("notification", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "notification", map[string]interface{}{"user": user})
 }
 
 // UnsubscribeWebData2 unsubscribes from web data 2
 func (ws *WebSocketAPI) UnsubscribeWebData2(user string) error {
 	channel := fmt.Sprintf("webData2:%s", user)
 
-	// Remove from internal storage
-	if err := ws.removeSubscription(channel); err != nil {
-		return err
-	}
-
-	return ws.unsubscribe("webData2", map[string]interface{}{"user": user})
+	// Remove from internal storage and unsubscribe upstream
+	return ws.removeSubscription(channel, "webData2", map[string]interface{}{"user": user})
 }
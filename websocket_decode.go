@@ -0,0 +1,153 @@
+package hyperliquid
+
+import (
+	"bytes"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// maxPooledMessageSize is the largest read buffer kept for reuse, bigger buffers
+// (e.g. of a webData2 snapshot) are left to the garbage collector
+const maxPooledMessageSize = 1 << 20
+
+// peekChannel returns the channel of a WebSocket message without decoding its data
+func peekChannel(message []byte) (string, error) {
+	iter := fastJSON.BorrowIterator(message)
+	defer fastJSON.ReturnIterator(iter)
+	var channel string
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		if key == "channel" {
+			channel = iter.ReadString()
+			return false
+		}
+		iter.Skip()
+		return true
+	})
+	if iter.Error != nil && channel == "" {
+		return "", iter.Error
+	}
+	return channel, nil
+}
+
+// decodeMessageData decodes the data field of a WebSocket message into a T.
+// The data is decoded in place, without an intermediate map or copy of the message.
+func decodeMessageData[T any](message []byte) (interface{}, error) {
+	iter := fastJSON.BorrowIterator(message)
+	defer fastJSON.ReturnIterator(iter)
+	var data T
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		if key == "data" {
+			iter.ReadVal(&data)
+			return false
+		}
+		iter.Skip()
+		return true
+	})
+	if iter.Error != nil {
+		return nil, iter.Error
+	}
+	return data, nil
+}
+
+// rawMessageData returns the data field of a WebSocket message as raw JSON, for logging
+func rawMessageData(message []byte) []byte {
+	data, _ := decodeMessageData[jsoniter.RawMessage](message)
+	raw, _ := data.(jsoniter.RawMessage)
+	return raw
+}
+
+// decodeSubscriptionData decodes the data of a subscription update into the typed value
+// delivered to the handlers of subType, e.g. an L2BookSnapshot for SubTypeL2Book
+func decodeSubscriptionData(subType SubscriptionType, message []byte) (interface{}, error) {
+	switch subType {
+	case SubTypeUserFills:
+		return decodeMessageData[UserFills](message)
+	case SubTypeL2Book:
+		return decodeMessageData[L2BookSnapshot](message)
+	case SubTypeTrades:
+		return decodeMessageData[[]Trade](message)
+	case SubTypeOrderUpdates:
+		return decodeMessageData[[]OrderUpdate](message)
+	case SubTypeUserEvents:
+		return decodeMessageData[UserEvent](message)
+	case SubTypeUserFundings:
+		return decodeMessageData[UserFundings](message)
+	case SubTypeUserNonFundingLedgerUpdates:
+		return decodeMessageData[UserNonFundingLedgerUpdates](message)
+	case SubTypeUserTwapSliceFills:
+		return decodeMessageData[UserTwapSliceFills](message)
+	case SubTypeUserTwapHistory:
+		return decodeMessageData[UserTwapHistory](message)
+	case SubTypeActiveAssetCtx:
+		return decodeMessageData[ActiveAssetCtx](message)
+	case SubTypeActiveAssetData:
+		return decodeMessageData[ActiveAssetData](message)
+	case SubTypeBbo:
+		return decodeMessageData[Bbo](message)
+	case SubTypeCandle:
+		return decodeMessageData[CandleSnapshot](message)
+	case SubTypeNotification:
+		return decodeMessageData[Notification](message)
+	case SubTypeWebData2:
+		return decodeMessageData[WebData2](message)
+	case SubTypeAllMids:
+		return decodeMessageData[AllMids](message)
+	default:
+		return decodeMessageData[interface{}](message)
+	}
+}
+
+// matchesSubscription checks if a decoded update belongs to a subscription.
+// Updates without a user (order updates, user events, notifications) are only sent
+// for the subscribed user and always match.
+func matchesSubscription(sub *Subscription, data interface{}) bool {
+	switch update := data.(type) {
+	case L2BookSnapshot:
+		return update.Coin == sub.Coin
+	case []Trade:
+		for i := range update {
+			if update[i].Coin == sub.Coin {
+				return true
+			}
+		}
+		return false
+	case Bbo:
+		return update.Coin == sub.Coin
+	case ActiveAssetCtx:
+		return update.Coin == sub.Coin
+	case ActiveAssetData:
+		return (update.User == "" || update.User == sub.User) && update.Coin == sub.Coin
+	case CandleSnapshot:
+		return update.Symbol == sub.Coin && update.Interval == sub.Interval
+	case UserFills:
+		return update.User == sub.User
+	case UserFundings:
+		return update.User == sub.User
+	case UserNonFundingLedgerUpdates:
+		return update.User == sub.User
+	case UserTwapSliceFills:
+		return update.User == sub.User
+	case UserTwapHistory:
+		return update.User == sub.User
+	case WebData2:
+		return update.User == sub.User
+	case []OrderUpdate, UserEvent, Notification, AllMids:
+		return true
+	default:
+		return false
+	}
+}
+
+// getMessageBuffer returns a read buffer from the pool
+func (ws *WebSocketAPI) getMessageBuffer() *bytes.Buffer {
+	buf := ws.messageBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putMessageBuffer returns a read buffer to the pool
+func (ws *WebSocketAPI) putMessageBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledMessageSize {
+		ws.messageBufferPool.Put(buf)
+	}
+}
@@ -0,0 +1,179 @@
+package hyperliquid
+
+import (
+	"context"
+	"iter"
+)
+
+// subscribeChan adapts a callback subscription to a typed channel bound to ctx.
+// Every update is decoded into T and delivered in order; updates that can not be decoded are dropped.
+// The channel is closed once ctx is done or the WebSocketAPI is disconnected with Disconnect.
+// Reconnects keep the channel open. A consumer that stops reading holds back its own listener only,
+// once the listener buffer is full further updates for it are dropped.
+func subscribeChan[T any](ctx context.Context, subType SubscriptionType, subscribe func(handler SubscriptionHandler) (*SubscriptionHandle, error)) (<-chan T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := make(chan T, listenerBufferSize(subType))
+	handle, err := subscribe(func(data interface{}) {
+		value, err := convertWSData[T](data)
+		if err != nil {
+			return
+		}
+		select {
+		case out <- *value:
+		case <-ctx.Done():
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			handle.Close()
+		case <-handle.Done():
+		}
+		// The handler goroutine has stopped, nothing sends to out anymore
+		<-handle.Done()
+		close(out)
+	}()
+	return out, nil
+}
+
+// Seq returns an iterator over the values received from ch. It ends when ch is closed.
+// Stopping the iteration early does not end the subscription, cancel its context for that.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	books, err := ws.OrderbookChan(ctx, "BTC")
+//	if err != nil {
+//		return err
+//	}
+//	for book := range hyperliquid.Seq(books) {
+//		...
+//	}
+func Seq[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range ch {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// OrderbookChan streams the orderbook updates of coin, see SubscribeOrderbook
+func (ws *WebSocketAPI) OrderbookChan(ctx context.Context, coin string) (<-chan L2BookSnapshot, error) {
+	return subscribeChan[L2BookSnapshot](ctx, SubTypeL2Book, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeOrderbook(coin, handler)
+	})
+}
+
+// TradesChan streams the trades of coin, see SubscribeTrades
+func (ws *WebSocketAPI) TradesChan(ctx context.Context, coin string) (<-chan []Trade, error) {
+	return subscribeChan[[]Trade](ctx, SubTypeTrades, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeTrades(coin, handler)
+	})
+}
+
+// UserFillsChan streams the fills of user, see SubscribeUserFills
+func (ws *WebSocketAPI) UserFillsChan(ctx context.Context, user string) (<-chan UserFills, error) {
+	return subscribeChan[UserFills](ctx, SubTypeUserFills, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeUserFills(user, handler)
+	})
+}
+
+// AllMidsChan streams the mid prices of all coins, see SubscribeAllMids
+func (ws *WebSocketAPI) AllMidsChan(ctx context.Context) (<-chan AllMids, error) {
+	return subscribeChan[AllMids](ctx, SubTypeAllMids, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeAllMids(handler)
+	})
+}
+
+// UserEventsChan streams the events of user, see SubscribeUserEvents
+func (ws *WebSocketAPI) UserEventsChan(ctx context.Context, user string) (<-chan UserEvent, error) {
+	return subscribeChan[UserEvent](ctx, SubTypeUserEvents, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeUserEvents(user, handler)
+	})
+}
+
+// UserFundingsChan streams the funding payments of user, see SubscribeUserFundings
+func (ws *WebSocketAPI) UserFundingsChan(ctx context.Context, user string) (<-chan UserFundings, error) {
+	return subscribeChan[UserFundings](ctx, SubTypeUserFundings, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeUserFundings(user, handler)
+	})
+}
+
+// UserNonFundingLedgerUpdatesChan streams the non-funding ledger updates of user, see SubscribeUserNonFundingLedgerUpdates
+func (ws *WebSocketAPI) UserNonFundingLedgerUpdatesChan(ctx context.Context, user string) (<-chan UserNonFundingLedgerUpdates, error) {
+	return subscribeChan[UserNonFundingLedgerUpdates](ctx, SubTypeUserNonFundingLedgerUpdates, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeUserNonFundingLedgerUpdates(user, handler)
+	})
+}
+
+// UserTwapSliceFillsChan streams the TWAP slice fills of user, see SubscribeUserTwapSliceFills
+func (ws *WebSocketAPI) UserTwapSliceFillsChan(ctx context.Context, user string) (<-chan UserTwapSliceFills, error) {
+	return subscribeChan[UserTwapSliceFills](ctx, SubTypeUserTwapSliceFills, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeUserTwapSliceFills(user, handler)
+	})
+}
+
+// UserTwapHistoryChan streams the TWAP history of user, see SubscribeUserTwapHistory
+func (ws *WebSocketAPI) UserTwapHistoryChan(ctx context.Context, user string) (<-chan UserTwapHistory, error) {
+	return subscribeChan[UserTwapHistory](ctx, SubTypeUserTwapHistory, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeUserTwapHistory(user, handler)
+	})
+}
+
+// ActiveAssetCtxChan streams the asset context of coin, see SubscribeActiveAssetCtx
+func (ws *WebSocketAPI) ActiveAssetCtxChan(ctx context.Context, coin string) (<-chan ActiveAssetCtx, error) {
+	return subscribeChan[ActiveAssetCtx](ctx, SubTypeActiveAssetCtx, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeActiveAssetCtx(coin, handler)
+	})
+}
+
+// ActiveAssetDataChan streams the asset data of user for coin, see SubscribeActiveAssetData
+func (ws *WebSocketAPI) ActiveAssetDataChan(ctx context.Context, user string, coin string) (<-chan ActiveAssetData, error) {
+	return subscribeChan[ActiveAssetData](ctx, SubTypeActiveAssetData, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeActiveAssetData(user, coin, handler)
+	})
+}
+
+// BboChan streams the best bid and offer of coin, see SubscribeBbo
+func (ws *WebSocketAPI) BboChan(ctx context.Context, coin string) (<-chan Bbo, error) {
+	return subscribeChan[Bbo](ctx, SubTypeBbo, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeBbo(coin, handler)
+	})
+}
+
+// CandleChan streams the candles of coin, see SubscribeCandle
+func (ws *WebSocketAPI) CandleChan(ctx context.Context, coin string, interval string) (<-chan CandleSnapshot, error) {
+	return subscribeChan[CandleSnapshot](ctx, SubTypeCandle, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeCandle(coin, interval, handler)
+	})
+}
+
+// OrderUpdatesChan streams the order updates of user, see SubscribeOrderUpdates
+func (ws *WebSocketAPI) OrderUpdatesChan(ctx context.Context, user string) (<-chan []OrderUpdate, error) {
+	return subscribeChan[[]OrderUpdate](ctx, SubTypeOrderUpdates, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeOrderUpdates(user, handler)
+	})
+}
+
+// NotificationChan streams the notifications of user, see SubscribeNotification
+func (ws *WebSocketAPI) NotificationChan(ctx context.Context, user string) (<-chan Notification, error) {
+	return subscribeChan[Notification](ctx, SubTypeNotification, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeNotification(user, handler)
+	})
+}
+
+// WebData2Chan streams the web data of user, see SubscribeWebData2
+func (ws *WebSocketAPI) WebData2Chan(ctx context.Context, user string) (<-chan WebData2, error) {
+	return subscribeChan[WebData2](ctx, SubTypeWebData2, func(handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeWebData2(user, handler)
+	})
+}
@@ -1,11 +1,17 @@
 package hyperliquid
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // TestWebSocketConnection tests basic WebSocket connection functionality
@@ -87,7 +93,7 @@ func TestSubscribeOrderbook(t *testing.T) {
 
 	// Subscribe to BTC orderbook
 	received := false
-	err = ws.SubscribeOrderbook("BTC", func(data interface{}) {
+	_, err = ws.SubscribeOrderbook("BTC", func(data interface{}) {
 		received = true
 		t.Logf("Received orderbook data: %+v", data)
 	})
@@ -126,7 +132,7 @@ func TestSubscribeTrades(t *testing.T) {
 
 	// Subscribe to BTC trades
 	received := false
-	err = ws.SubscribeTrades("BTC", func(data interface{}) {
+	_, err = ws.SubscribeTrades("BTC", func(data interface{}) {
 		received = true
 		t.Logf("Received trades data: %+v", data)
 	})
@@ -165,7 +171,7 @@ func TestSubscribeAllMids(t *testing.T) {
 
 	// Subscribe to all mids
 	received := false
-	err = ws.SubscribeAllMids(func(data interface{}) {
+	_, err = ws.SubscribeAllMids(func(data interface{}) {
 		received = true
 		t.Logf("Received all mids data: %+v", data)
 	})
@@ -204,7 +210,7 @@ func TestSubscribeBbo(t *testing.T) {
 
 	// Subscribe to BTC BBO
 	received := false
-	err = ws.SubscribeBbo("BTC", func(data interface{}) {
+	_, err = ws.SubscribeBbo("BTC", func(data interface{}) {
 		received = true
 		t.Logf("Received BBO data: %+v", data)
 	})
@@ -243,7 +249,7 @@ func TestSubscribeCandle(t *testing.T) {
 
 	// Subscribe to BTC 1m candles
 	received := false
-	err = ws.SubscribeCandle("BTC", "1m", func(data interface{}) {
+	_, err = ws.SubscribeCandle("BTC", "1m", func(data interface{}) {
 		received = true
 		t.Logf("Received candle data: %+v", data)
 	})
@@ -282,7 +288,7 @@ func TestSubscribeActiveAssetCtx(t *testing.T) {
 
 	// Subscribe to BTC active asset context
 	received := false
-	err = ws.SubscribeActiveAssetCtx("BTC", func(data interface{}) {
+	_, err = ws.SubscribeActiveAssetCtx("BTC", func(data interface{}) {
 		received = true
 		t.Logf("Received active asset context data: %+v", data)
 	})
@@ -322,7 +328,7 @@ func TestSubscribeUserFills(t *testing.T) {
 	// Subscribe to user fills (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeUserFills(testUser, func(data interface{}) {
+	_, err = ws.SubscribeUserFills(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received user fills data: %+v", data)
 	})
@@ -361,7 +367,7 @@ func TestSubscribeUserEvents(t *testing.T) {
 	// Subscribe to user events (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeUserEvents(testUser, func(data interface{}) {
+	_, err = ws.SubscribeUserEvents(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received user events data: %+v", data)
 	})
@@ -400,7 +406,7 @@ func TestSubscribeUserFundings(t *testing.T) {
 	// Subscribe to user fundings (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeUserFundings(testUser, func(data interface{}) {
+	_, err = ws.SubscribeUserFundings(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received user fundings data: %+v", data)
 	})
@@ -439,7 +445,7 @@ func TestSubscribeUserNonFundingLedgerUpdates(t *testing.T) {
 	// Subscribe to user non-funding ledger updates (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeUserNonFundingLedgerUpdates(testUser, func(data interface{}) {
+	_, err = ws.SubscribeUserNonFundingLedgerUpdates(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received user non-funding ledger updates data: %+v", data)
 	})
@@ -478,7 +484,7 @@ func TestSubscribeUserTwapSliceFills(t *testing.T) {
 	// Subscribe to user TWAP slice fills (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeUserTwapSliceFills(testUser, func(data interface{}) {
+	_, err = ws.SubscribeUserTwapSliceFills(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received user TWAP slice fills data: %+v", data)
 	})
@@ -517,7 +523,7 @@ func TestSubscribeUserTwapHistory(t *testing.T) {
 	// Subscribe to user TWAP history (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeUserTwapHistory(testUser, func(data interface{}) {
+	_, err = ws.SubscribeUserTwapHistory(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received user TWAP history data: %+v", data)
 	})
@@ -556,7 +562,7 @@ func TestSubscribeActiveAssetData(t *testing.T) {
 	// Subscribe to active asset data (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeActiveAssetData(testUser, "BTC", func(data interface{}) {
+	_, err = ws.SubscribeActiveAssetData(testUser, "BTC", func(data interface{}) {
 		received = true
 		t.Logf("Received active asset data: %+v", data)
 	})
@@ -595,7 +601,7 @@ func TestSubscribeOrderUpdates(t *testing.T) {
 	// Subscribe to order updates (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeOrderUpdates(testUser, func(data interface{}) {
+	_, err = ws.SubscribeOrderUpdates(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received order updates data: %+v", data)
 	})
@@ -634,7 +640,7 @@ func TestSubscribeNotification(t *testing.T) {
 	// Subscribe to notifications (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeNotification(testUser, func(data interface{}) {
+	_, err = ws.SubscribeNotification(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received notification data: %+v", data)
 	})
@@ -673,7 +679,7 @@ func TestSubscribeWebData2(t *testing.T) {
 	// Subscribe to web data 2 (using a test address)
 	testUser := "0x1234567890123456789012345678901234567890"
 	received := false
-	err = ws.SubscribeWebData2(testUser, func(data interface{}) {
+	_, err = ws.SubscribeWebData2(testUser, func(data interface{}) {
 		received = true
 		t.Logf("Received web data 2: %+v", data)
 	})
@@ -819,12 +825,12 @@ func TestMultipleSubscriptions(t *testing.T) {
 	}
 
 	// Subscribe to multiple channels
-	err = ws.SubscribeOrderbook("BTC", orderbookHandler)
+	_, err = ws.SubscribeOrderbook("BTC", orderbookHandler)
 	if err != nil {
 		t.Fatalf("Failed to subscribe to orderbook: %v", err)
 	}
 
-	err = ws.SubscribeTrades("BTC", tradesHandler)
+	_, err = ws.SubscribeTrades("BTC", tradesHandler)
 	if err != nil {
 		t.Fatalf("Failed to subscribe to trades: %v", err)
 	}
@@ -881,7 +887,7 @@ func TestWebSocketReconnectionWithSubscriptions(t *testing.T) {
 	}
 
 	// Subscribe to orderbook
-	err = ws.SubscribeOrderbook("BTC", handler)
+	_, err = ws.SubscribeOrderbook("BTC", handler)
 	if err != nil {
 		t.Fatalf("Failed to subscribe to orderbook: %v", err)
 	}
@@ -946,7 +952,7 @@ func BenchmarkSubscription(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		err := ws.SubscribeOrderbook("BTC", handler)
+		_, err := ws.SubscribeOrderbook("BTC", handler)
 		if err != nil {
 			b.Fatalf("Failed to subscribe: %v", err)
 		}
@@ -1015,7 +1021,7 @@ func TestWebSocketConcurrentAccess(t *testing.T) {
 			}
 
 			coin := fmt.Sprintf("COIN%d", id)
-			err := ws.SubscribeOrderbook(coin, handler)
+			_, err := ws.SubscribeOrderbook(coin, handler)
 			if err != nil {
 				t.Errorf("Failed to subscribe in goroutine %d: %v", id, err)
 				return
@@ -1034,3 +1040,376 @@ func TestWebSocketConcurrentAccess(t *testing.T) {
 	wg.Wait()
 	ws.Disconnect()
 }
+
+// TestSubscriptionListeners tests that listeners of the same channel are independent
+// and that the upstream subscription is shared by all of them
+func TestSubscriptionListeners(t *testing.T) {
+	const user = "0x0000000000000000000000000000000000000001"
+	var mu sync.Mutex
+	methods := make(map[string]int)
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSSubscription
+		if err := FastUnmarshal(message, &request); err == nil {
+			mu.Lock()
+			methods[request.Method]++
+			mu.Unlock()
+		}
+	})
+	waitForMethod := func(method string, count int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			got := methods[method]
+			mu.Unlock()
+			if got >= count {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Expected %d %s messages", count, method)
+	}
+
+	first := make(chan interface{}, 10)
+	second := make(chan interface{}, 10)
+	h1, err := ws.SubscribeUserFills(user, func(data interface{}) { first <- data })
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	h2, err := ws.SubscribeUserFills(user, func(data interface{}) { second <- data })
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	waitForMethod("subscribe", 1)
+
+	message := []byte(`{"channel":"userFills","data":{"user":"` + user + `","fills":[]}}`)
+	ws.processJSONMessage(message)
+	for _, ch := range []chan interface{}{first, second} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("Expected both listeners to receive the message")
+		}
+	}
+
+	if err := h1.Close(); err != nil {
+		t.Fatalf("Failed to close handle: %v", err)
+	}
+	<-h1.Done()
+	h1.Close() // Closing twice is a no-op
+	ws.processJSONMessage(message)
+	select {
+	case <-second:
+	case <-time.After(time.Second):
+		t.Fatal("Expected remaining listener to keep receiving")
+	}
+	select {
+	case <-first:
+		t.Fatal("Closed listener must not receive messages")
+	default:
+	}
+
+	if err := h2.Close(); err != nil {
+		t.Fatalf("Failed to close handle: %v", err)
+	}
+	waitForMethod("unsubscribe", 1)
+	mu.Lock()
+	if methods["subscribe"] != 1 || methods["unsubscribe"] != 1 {
+		t.Errorf("Expected a single upstream subscribe and unsubscribe, got %v", methods)
+	}
+	mu.Unlock()
+
+	h3, err := ws.SubscribeTrades("BTC", func(data interface{}) {})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	ws.Disconnect()
+	select {
+	case <-h3.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected listener to stop on disconnect")
+	}
+	if err := h3.Close(); err != nil {
+		t.Errorf("Closing after disconnect failed: %v", err)
+	}
+}
+
+// slowSendHandler delays the debug records logged right before upstream (un)subscribe messages
+// are sent, widening the window in which listeners can race with them
+type slowSendHandler struct {
+	slog.Handler
+}
+
+func (h slowSendHandler) Handle(ctx context.Context, record slog.Record) error {
+	switch record.Message {
+	case "Sending subscription message":
+		time.Sleep(time.Millisecond)
+	case "Sending unsubscribe message":
+		time.Sleep(2 * time.Millisecond)
+	}
+	return nil
+}
+
+// TestSubscriptionUpstreamOrder checks that listeners coming and going concurrently keep the
+// upstream subscribe and unsubscribe messages of a channel in order
+func TestSubscriptionUpstreamOrder(t *testing.T) {
+	var mu sync.Mutex
+	var methods []string
+	done := make(chan struct{})
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request struct {
+			Method       string            `json:"method"`
+			Subscription map[string]string `json:"subscription"`
+		}
+		if err := FastUnmarshal(message, &request); err != nil {
+			return
+		}
+		switch request.Subscription["type"] {
+		case "trades":
+			mu.Lock()
+			methods = append(methods, request.Method)
+			mu.Unlock()
+		case "allMids":
+			close(done)
+		}
+	})
+	ws.SetLogger(slog.New(slowSendHandler{slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug})}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				handle, err := ws.SubscribeTrades("BTC", func(data interface{}) {})
+				if err != nil {
+					t.Errorf("Failed to subscribe: %v", err)
+					return
+				}
+				handle.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := ws.SubscribeTrades("BTC", func(data interface{}) {}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	// Messages are handled in order, so the marker arrives after all trades messages
+	if _, err := ws.SubscribeAllMids(func(data interface{}) {}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the marker subscription")
+	}
+
+	mu.Lock()
+	for i, method := range methods {
+		if expected := []string{"subscribe", "unsubscribe"}[i%2]; method != expected {
+			t.Fatalf("Expected alternating upstream messages, got %v", methods)
+		}
+	}
+	if len(methods)%2 == 0 {
+		t.Errorf("Expected the remaining listener to be subscribed upstream, got %v", methods)
+	}
+	mu.Unlock()
+
+	// Listeners joining while the first subscribe fails are not left without upstream
+	offline := NewWebSocketAPI(true)
+	offline.SetLogger(ws.Logger())
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := offline.SubscribeTrades("BTC", func(data interface{}) {}); err == nil {
+				t.Error("Expected subscribe to fail without connection")
+			}
+		}()
+	}
+	wg.Wait()
+	if len(offline.subscriptions) != 0 || len(offline.channelHandlers["trades"]) != 0 {
+		t.Errorf("Expected no registered listeners, got %v", offline.subscriptions)
+	}
+}
+
+// TestSubscriptionChan tests typed channel subscriptions and their lifetime
+func TestSubscriptionChan(t *testing.T) {
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	books, err := ws.OrderbookChan(ctx, "BTC")
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	mids, err := ws.AllMidsChan(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	ws.processJSONMessage([]byte(`{"channel":"l2Book","data":{"coin":"BTC","time":1,"levels":[[{"px":"100","sz":"1","n":1}],[{"px":"101","sz":"2","n":2}]]}}`))
+	ws.processJSONMessage([]byte(`{"channel":"l2Book","data":{"coin":"BTC","time":2,"levels":[[],[]]}}`))
+	received := 0
+	for book := range Seq(books) {
+		received++
+		if received == 1 && (book.Levels[1][0].Px != 101 || book.Levels[1][0].N != 2) {
+			t.Errorf("Unexpected book: %+v", book)
+		}
+		if book.Time == 2 {
+			break
+		}
+	}
+	if received != 2 {
+		t.Errorf("Expected 2 books, got %d", received)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-books:
+		if ok {
+			t.Error("Expected no more books after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected channel to be closed after cancel")
+	}
+
+	ws.processJSONMessage([]byte(`{"channel":"allMids","data":{"mids":{"BTC":"100.5"}}}`))
+	select {
+	case update := <-mids:
+		if update.Mids["BTC"] != "100.5" {
+			t.Errorf("Unexpected mids: %+v", update)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected mids update")
+	}
+	ws.Disconnect()
+	select {
+	case _, ok := <-mids:
+		if ok {
+			t.Error("Expected no more mids after disconnect")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected channel to be closed on disconnect")
+	}
+}
+
+// TestTypedMessageDecoding tests that subscription messages read from the connection are decoded
+// into the typed value of their channel and that reused read buffers do not corrupt delivered values
+func TestTypedMessageDecoding(t *testing.T) {
+	ws := newFakeWSServer(t, func(conn *websocket.Conn, message []byte) {
+		var request WSSubscription
+		if FastUnmarshal(message, &request); request.Method != "subscribe" {
+			return
+		}
+		for _, reply := range []string{
+			`{"channel":"trades","data":[{"coin":"ETH","side":"B","px":"2500","sz":"1","time":1,"hash":"0x1","tid":1}]}`,
+			`{"data":[{"coin":"BTC","side":"A","px":"100000.5","sz":"0.01","time":2,"hash":"0x2","tid":2,"users":["0xa","0xb"]}],"channel":"trades"}`,
+			`{"channel":"trades","data":[{"coin":"BTC","px":"oops"}]}`,
+			`{"channel":"trades","data":[{"coin":"BTC","side":"B","px":"99999","sz":"0.02","time":3,"hash":"0x3","tid":3}]}`,
+		} {
+			conn.WriteMessage(websocket.TextMessage, []byte(reply))
+		}
+	})
+
+	received := make(chan interface{}, 10)
+	if _, err := ws.SubscribeTrades("BTC", func(data interface{}) { received <- data }); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	var trades [][]Trade
+	for len(trades) < 2 {
+		select {
+		case data := <-received:
+			update, ok := data.([]Trade)
+			if !ok {
+				t.Fatalf("Expected []Trade, got %T", data)
+			}
+			trades = append(trades, update)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected 2 BTC trade updates, got %d", len(trades))
+		}
+	}
+	first, second := trades[0][0], trades[1][0]
+	if first.Px != 100000.5 || first.Hash != "0x2" || len(first.Users) != 2 || first.Users[1] != "0xb" {
+		t.Errorf("Unexpected first trade: %+v", first)
+	}
+	if second.Px != 99999 || second.Hash != "0x3" {
+		t.Errorf("Unexpected second trade: %+v", second)
+	}
+
+	if channel, _ := peekChannel([]byte(`{"data":{"x":[1,{"channel":"nested"}]},"channel":"l2Book"}`)); channel != "l2Book" {
+		t.Errorf("Expected l2Book channel, got %q", channel)
+	}
+	if _, err := peekChannel([]byte(`not json`)); err == nil {
+		t.Error("Expected error for invalid message")
+	}
+}
+
+func benchmarkL2BookMessage() []byte {
+	var levels [2][]string
+	for side := range levels {
+		for i := 0; i < 20; i++ {
+			levels[side] = append(levels[side], fmt.Sprintf(`{"px":"%d.5","sz":"%d.1234","n":%d}`, 100000+(side*2-1)*i, i+1, i%7+1))
+		}
+	}
+	return []byte(`{"channel":"l2Book","data":{"coin":"BTC","time":1700000000000,"levels":[[` +
+		strings.Join(levels[0], ",") + `],[` + strings.Join(levels[1], ",") + `]]}}`)
+}
+
+func benchmarkTradesMessage() []byte {
+	trades := make([]string, 10)
+	for i := range trades {
+		trades[i] = fmt.Sprintf(`{"coin":"BTC","side":"B","px":"100000.%d","sz":"0.0%d","time":1700000000%03d,"hash":"0x%064d","tid":%d,"users":["0x%040d","0x%040d"]}`, i, i+1, i, i, 1000000+i, i, i+1)
+	}
+	return []byte(`{"channel":"trades","data":[` + strings.Join(trades, ",") + `]}`)
+}
+
+// benchmarkMessageDispatch measures decoding and dispatching message to a subscribed listener
+func benchmarkMessageDispatch(b *testing.B, message []byte, subscribe func(ws *WebSocketAPI, handler SubscriptionHandler) (*SubscriptionHandle, error)) {
+	ws := newFakeWSServer(b, func(conn *websocket.Conn, message []byte) {})
+	delivered := make(chan struct{}, 1)
+	if _, err := subscribe(ws, func(data interface{}) { delivered <- struct{}{} }); err != nil {
+		b.Fatalf("Failed to subscribe: %v", err)
+	}
+	b.SetBytes(int64(len(message)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ws.processJSONMessage(message)
+		<-delivered
+	}
+}
+
+// benchmarkMapDecoding measures the previous decode path: a generic map and a re-marshal into T
+func benchmarkMapDecoding[T any](b *testing.B, message []byte) {
+	b.SetBytes(int64(len(message)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var response WSResponse
+		if err := PooledUnmarshal(message, &response); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := convertWSData[T](response.Data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkL2BookMessage(b *testing.B) {
+	benchmarkMessageDispatch(b, benchmarkL2BookMessage(), func(ws *WebSocketAPI, handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeOrderbook("BTC", handler)
+	})
+}
+
+func BenchmarkL2BookMessageMap(b *testing.B) {
+	benchmarkMapDecoding[L2BookSnapshot](b, benchmarkL2BookMessage())
+}
+
+func BenchmarkTradesMessage(b *testing.B) {
+	benchmarkMessageDispatch(b, benchmarkTradesMessage(), func(ws *WebSocketAPI, handler SubscriptionHandler) (*SubscriptionHandle, error) {
+		return ws.SubscribeTrades("BTC", handler)
+	})
+}
+
+func BenchmarkTradesMessageMap(b *testing.B) {
+	benchmarkMapDecoding[[]Trade](b, benchmarkTradesMessage())
+}
@@ -0,0 +1,189 @@
+package hyperliquid
+
+// Trade is a single trade received from the trades subscription
+type Trade struct {
+	Coin  string   `json:"coin"`
+	Side  string   `json:"side"`
+	Px    float64  `json:"px,string"`
+	Sz    float64  `json:"sz,string"`
+	Time  int64    `json:"time"`
+	Hash  string   `json:"hash"`
+	Tid   int64    `json:"tid"`
+	Users []string `json:"users"`
+}
+
+// convertWSData converts data received by a SubscriptionHandler into the typed value T.
+// Data that already has the requested type is returned as is.
+func convertWSData[T any](data interface{}) (*T, error) {
+	switch v := data.(type) {
+	case *T:
+		return v, nil
+	case T:
+		return &v, nil
+	}
+	b, err := FastMarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var result T
+	if err := FastUnmarshal(b, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WebData2 is the aggregated account data pushed by the webData2 subscription
+type WebData2 struct {
+	User               string         `json:"user"`
+	ClearinghouseState UserState      `json:"clearinghouseState"`
+	Meta               Meta           `json:"meta"`
+	AssetCtxs          []PerpAssetCtx `json:"assetCtxs"`
+	ServerTime         int64          `json:"serverTime"`
+	TotalVaultEquity   float64        `json:"totalVaultEquity,string"`
+	AgentAddress       string         `json:"agentAddress"`
+	AgentValidUntil    int64          `json:"agentValidUntil"`
+	IsVault            bool           `json:"isVault"`
+}
+
+// BookLevel is a single price level of an order book
+type BookLevel struct {
+	Px float64 `json:"px,string"`
+	Sz float64 `json:"sz,string"`
+	N  int     `json:"n"`
+}
+
+// AllMids is the data of the allMids subscription
+type AllMids struct {
+	Mids map[string]string `json:"mids"`
+}
+
+// Bbo is the best bid and offer of a coin, either side is nil if the book side is empty
+type Bbo struct {
+	Coin string        `json:"coin"`
+	Time int64         `json:"time"`
+	Bbo  [2]*BookLevel `json:"bbo"`
+}
+
+// UserFills is the data of the userFills subscription
+type UserFills struct {
+	IsSnapshot bool        `json:"isSnapshot"`
+	User       string      `json:"user"`
+	Fills      []OrderFill `json:"fills"`
+}
+
+// OrderUpdate is a status change of an order received from the orderUpdates subscription
+type OrderUpdate struct {
+	Order           Order  `json:"order"`
+	Status          string `json:"status"`
+	StatusTimestamp int64  `json:"statusTimestamp"`
+}
+
+// UserFunding is a funding payment received from the userFundings or userEvents subscription
+type UserFunding struct {
+	Time        int64   `json:"time"`
+	Coin        string  `json:"coin"`
+	Usdc        float64 `json:"usdc,string"`
+	Szi         float64 `json:"szi,string"`
+	FundingRate float64 `json:"fundingRate,string"`
+}
+
+// UserLiquidation is a liquidation received from the userEvents subscription
+type UserLiquidation struct {
+	Lid                    int64   `json:"lid"`
+	Liquidator             string  `json:"liquidator"`
+	LiquidatedUser         string  `json:"liquidated_user"`
+	LiquidatedNtlPos       float64 `json:"liquidated_ntl_pos,string"`
+	LiquidatedAccountValue float64 `json:"liquidated_account_value,string"`
+}
+
+// NonUserCancel is an order canceled by the exchange
+type NonUserCancel struct {
+	Coin string `json:"coin"`
+	Oid  int64  `json:"oid"`
+}
+
+// UserEvent is the data of the userEvents subscription, exactly one of the fields is set
+type UserEvent struct {
+	Fills         []OrderFill      `json:"fills,omitempty"`
+	Funding       *UserFunding     `json:"funding,omitempty"`
+	Liquidation   *UserLiquidation `json:"liquidation,omitempty"`
+	NonUserCancel []NonUserCancel  `json:"nonUserCancel,omitempty"`
+}
+
+// UserFundings is the data of the userFundings subscription
+type UserFundings struct {
+	IsSnapshot bool          `json:"isSnapshot"`
+	User       string        `json:"user"`
+	Fundings   []UserFunding `json:"fundings"`
+}
+
+// UserNonFundingLedgerUpdates is the data of the userNonFundingLedgerUpdates subscription
+type UserNonFundingLedgerUpdates struct {
+	IsSnapshot              bool               `json:"isSnapshot"`
+	User                    string             `json:"user"`
+	NonFundingLedgerUpdates []NonFundingUpdate `json:"nonFundingLedgerUpdates"`
+}
+
+// TwapSliceFill is a fill of a TWAP order slice
+type TwapSliceFill struct {
+	Fill   OrderFill `json:"fill"`
+	TwapId int64     `json:"twapId"`
+}
+
+// UserTwapSliceFills is the data of the userTwapSliceFills subscription
+type UserTwapSliceFills struct {
+	IsSnapshot     bool            `json:"isSnapshot"`
+	User           string          `json:"user"`
+	TwapSliceFills []TwapSliceFill `json:"twapSliceFills"`
+}
+
+// TwapState is the state of a TWAP order
+type TwapState struct {
+	Coin        string  `json:"coin"`
+	User        string  `json:"user"`
+	Side        string  `json:"side"`
+	Sz          float64 `json:"sz,string"`
+	ExecutedSz  float64 `json:"executedSz,string"`
+	ExecutedNtl float64 `json:"executedNtl,string"`
+	Minutes     int     `json:"minutes"`
+	ReduceOnly  bool    `json:"reduceOnly"`
+	Randomize   bool    `json:"randomize"`
+	Timestamp   int64   `json:"timestamp"`
+}
+
+// TwapHistoryEntry is a TWAP order status change
+type TwapHistoryEntry struct {
+	State  TwapState `json:"state"`
+	Status struct {
+		Status      string `json:"status"` // "activated", "terminated", "finished" or "error"
+		Description string `json:"description"`
+	} `json:"status"`
+	Time int64 `json:"time"`
+}
+
+// UserTwapHistory is the data of the userTwapHistory subscription
+type UserTwapHistory struct {
+	IsSnapshot bool               `json:"isSnapshot"`
+	User       string             `json:"user"`
+	History    []TwapHistoryEntry `json:"history"`
+}
+
+// ActiveAssetCtx is the data of the activeAssetCtx subscription
+type ActiveAssetCtx struct {
+	Coin string       `json:"coin"`
+	Ctx  PerpAssetCtx `json:"ctx"`
+}
+
+// ActiveAssetData is the data of the activeAssetData subscription
+type ActiveAssetData struct {
+	User             string       `json:"user"`
+	Coin             string       `json:"coin"`
+	Leverage         Leverage     `json:"leverage"`
+	MaxTradeSzs      StringFloats `json:"maxTradeSzs"`
+	AvailableToTrade StringFloats `json:"availableToTrade"`
+}
+
+// Notification is the data of the notification subscription
+type Notification struct {
+	Notification string `json:"notification"`
+}